@@ -0,0 +1,469 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FieldMismatchError CopyBetween 的源、目标结构体的字段不能一一对应
+// 返回该错误时已匹配的字段仍然被正常拷贝，只在某一侧存在的字段和类型不兼容的字段被跳过，调用方可以选择忽略
+type FieldMismatchError struct {
+	Src, Dst     reflect.Type
+	SourceOnly   []string // 只存在于源结构体中的字段，嵌套字段以 . 连接，如 Address.Zip
+	DestOnly     []string // 只存在于目标结构体中的字段
+	Incompatible []string // 同名但类型无法拷贝的字段，如 "Age (string -> int, not convertible)"
+	Collisions   []string // 被多个源字段（经 WithFieldRename 改名后）匹配的目标字段，如 "ID (ID, UserID)"，该目标字段不被拷贝
+}
+
+func (e *FieldMismatchError) Error() string {
+	var parts []string
+	if len(e.SourceOnly) > 0 {
+		parts = append(parts, "source only: "+strings.Join(e.SourceOnly, ", "))
+	}
+	if len(e.DestOnly) > 0 {
+		parts = append(parts, "dest only: "+strings.Join(e.DestOnly, ", "))
+	}
+	if len(e.Incompatible) > 0 {
+		parts = append(parts, "incompatible: "+strings.Join(e.Incompatible, ", "))
+	}
+	if len(e.Collisions) > 0 {
+		parts = append(parts, "collisions: "+strings.Join(e.Collisions, ", "))
+	}
+	return fmt.Sprintf("deepcopy: fields of %s and %s do not match (%s)", e.Src, e.Dst, strings.Join(parts, "; "))
+}
+
+// ConversionPolicy CopyBetween 中同名字段类型不同、但可以转换时的处理方式
+type ConversionPolicy int
+
+const (
+	// ConvertSafe 允许命名类型与底层类型之间的转换，以及不会溢出的数值转换（拓宽、整数转浮点数），默认
+	ConvertSafe ConversionPolicy = iota
+	// ConvertLossless 只允许无损转换：命名类型转换，以及能精确表示源类型所有值的数值拓宽（如 int32 -> int64、int32 -> float64）
+	ConvertLossless
+	// ConvertNarrowing 允许所有数值转换，包括可能溢出或截断的收窄（如 int64 -> int32、float64 -> int）
+	ConvertNarrowing
+)
+
+// WithConversionPolicy 设置 CopyBetween、FromMap 的类型转换策略，默认为 ConvertSafe
+func WithConversionPolicy(p ConversionPolicy) Option {
+	return func(c *copyConfig) {
+		c.conversionPolicy = p
+	}
+}
+
+// WithFieldRename CopyBetween 匹配字段前先把源字段名按 renames（源字段名 -> 目标字段名）改名，
+// 如 {"UserID": "ID"} 使源的 UserID 拷贝到目标的 ID。改名按字段名作用于各层嵌套结构体；
+// 改名后的目标字段不存在时源字段列入 SourceOnly（如 "UserID -> ID"），
+// 多个源字段对应同一目标字段时该目标字段被跳过并列入 Collisions
+func WithFieldRename(renames map[string]string) Option {
+	copied := make(map[string]string, len(renames))
+	for from, to := range renames {
+		copied[from] = to
+	}
+	return func(c *copyConfig) {
+		c.fieldRenames = copied
+	}
+}
+
+// conversionLoss 转换的损失程度
+type conversionLoss int
+
+const (
+	lossNone      conversionLoss = iota // 无损
+	lossPrecision                       // 不会溢出，但可能损失精度（大整数转浮点数）
+	lossOverflow                        // 可能溢出或截断
+)
+
+// allows 策略是否允许该损失程度的转换
+func (p ConversionPolicy) allows(loss conversionLoss) bool {
+	switch p {
+	case ConvertLossless:
+		return loss == lossNone
+	case ConvertNarrowing:
+		return true
+	}
+	return loss <= lossPrecision
+}
+
+// numericConversionLoss 两个基础类型之间转换的损失程度，不是可以转换的数值或同种类型时返回 false
+func numericConversionLoss(st, dt reflect.Type) (conversionLoss, bool) {
+	sk, dk := st.Kind(), dt.Kind()
+	if sk == dk {
+		// 命名类型与底层类型之间，或底层类型相同的两个命名类型
+		return lossNone, st.ConvertibleTo(dt)
+	}
+	switch {
+	case isSignedKind(sk) && isSignedKind(dk), isUnsignedKind(sk) && isUnsignedKind(dk):
+		return widening(st.Bits() <= dt.Bits()), true
+	case isUnsignedKind(sk) && isSignedKind(dk):
+		return widening(st.Bits() < dt.Bits()), true
+	case isSignedKind(sk) && isUnsignedKind(dk):
+		return lossOverflow, true
+	case (isSignedKind(sk) || isUnsignedKind(sk)) && isFloatKind(dk):
+		// 整数的有效位数不超过浮点数的尾数时可以精确表示
+		bits := st.Bits()
+		if isSignedKind(sk) {
+			bits--
+		}
+		if bits <= mantissaBits(dt) {
+			return lossNone, true
+		}
+		return lossPrecision, true
+	case isFloatKind(sk) && isFloatKind(dk), sk == reflect.Complex64 && dk == reflect.Complex128,
+		sk == reflect.Complex128 && dk == reflect.Complex64:
+		return widening(st.Bits() <= dt.Bits()), true
+	case isFloatKind(sk) && (isSignedKind(dk) || isUnsignedKind(dk)):
+		return lossOverflow, true
+	}
+	return 0, false
+}
+
+// widening 拓宽为无损，否则可能溢出
+func widening(ok bool) conversionLoss {
+	if ok {
+		return lossNone
+	}
+	return lossOverflow
+}
+
+func isSignedKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUnsignedKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// mantissaBits 浮点数尾数的有效位数（含隐含位）
+func mantissaBits(t reflect.Type) int {
+	if t.Kind() == reflect.Float32 {
+		return 24
+	}
+	return 53
+}
+
+// betweenMode 字段的拷贝方式
+type betweenMode int
+
+const (
+	betweenDeepCopy betweenMode = iota // 类型相同，直接深拷贝
+	betweenStruct                      // 不同的结构体，按字段映射递归拷贝
+	betweenPtr                         // 指向不同结构体的指针，分配新对象后按字段映射拷贝
+	betweenConvert                     // 可以转换的基础类型或同种类的命名类型，按源类型拷贝后转换
+)
+
+// betweenField 一对同名字段
+type betweenField struct {
+	name     string
+	src, dst int // 字段下标
+	mode     betweenMode
+	plan     *betweenPlan // betweenStruct、betweenPtr 时两个结构体之间的映射
+}
+
+// betweenPlan 两个结构体类型之间的字段映射
+type betweenPlan struct {
+	fields       []betweenField
+	sourceOnly   []string
+	destOnly     []string
+	incompatible []string
+	collisions   []string
+}
+
+// betweenMapping 缓存的根映射，err 为汇总了嵌套结构体的 *FieldMismatchError，完全匹配时为 nil
+type betweenMapping struct {
+	plan *betweenPlan
+	err  error
+}
+
+// typePair 源类型和目标类型
+type typePair struct {
+	src, dst reflect.Type
+}
+
+// betweenKey 字段映射的缓存键，转换策略或字段改名不同时映射不同
+type betweenKey struct {
+	typePair
+	policy  ConversionPolicy
+	renames string // 排序后的改名映射
+}
+
+var betweenMappings sync.Map // map[betweenKey]*betweenMapping
+
+// CopyBetween 在两个字段相近的结构体类型之间深拷贝，如 API 的 v1/v2 结构体、领域模型与持久化模型。
+// 导出字段按名称匹配：类型相同时深拷贝，同为结构体（或结构体指针）时按同样的规则递归；
+// 类型不同但可以转换的字段（int32 -> int64、MyString -> string 等）按 WithConversionPolicy 的策略转换，
+// 默认拒绝可能溢出的收窄转换。
+// 字段名不同时可以用 WithFieldRename 指定对应关系。
+// 源或目标字段带 redact 标签时目标字段为零值，WithFieldFilter、WithTagWhitelist、WithSkipZeroSource 同样生效。
+// 未导出字段、只在一侧存在的字段和类型不兼容的字段被跳过，后两者通过 *FieldMismatchError 列出。
+// Src、Dst 须为结构体或结构体指针，字段映射按类型对、转换策略和字段改名缓存
+func CopyBetween[Dst, Src any](src Src, opts ...Option) (Dst, error) {
+	var dst Dst
+	st := reflect.TypeOf((*Src)(nil)).Elem()
+	dt := reflect.TypeOf((*Dst)(nil)).Elem()
+	srcVal := reflect.ValueOf(&src).Elem()
+	dstVal := reflect.ValueOf(&dst).Elem()
+
+	if st == dt {
+		copied, err := CopyE(src, opts...)
+		dstVal.Set(reflect.ValueOf(&copied).Elem())
+		return dst, err
+	}
+
+	if st.Kind() == reflect.Ptr && dt.Kind() == reflect.Ptr {
+		if srcVal.IsNil() {
+			return dst, nil
+		}
+		st, dt = st.Elem(), dt.Elem()
+		srcVal = srcVal.Elem()
+		dstVal.Set(reflect.New(dt))
+		dstVal = dstVal.Elem()
+	}
+	if st.Kind() != reflect.Struct || dt.Kind() != reflect.Struct {
+		return dst, fmt.Errorf("%w: CopyBetween needs structs or pointers to structs, got %s and %s",
+			ErrTypeConversion, reflect.TypeOf((*Src)(nil)).Elem(), reflect.TypeOf((*Dst)(nil)).Elem())
+	}
+
+	cfg := newCopyConfig(opts)
+	if cfg.locker != nil {
+		cfg.locker.Lock()
+		defer cfg.locker.Unlock()
+	}
+	mapping := getBetweenMapping(st, dt, cfg.conversionPolicy, cfg.fieldRenames)
+	state := newCopyState(cfg)
+	if err := state.run(func() { state.copyBetween(mapping.plan, srcVal, dstVal) }); err != nil {
+		var zero Dst
+		return zero, err
+	}
+	return dst, mapping.err
+}
+
+// getBetweenMapping 获取或创建两个结构体类型之间的映射
+func getBetweenMapping(st, dt reflect.Type, policy ConversionPolicy, renames map[string]string) *betweenMapping {
+	_, fp := renameFingerprint(renames)
+	key := betweenKey{typePair{st, dt}, policy, fp}
+	if cached, ok := betweenMappings.Load(key); ok {
+		return cached.(*betweenMapping)
+	}
+
+	b := &planBuilder{policy: policy, renames: renames, building: make(map[typePair]*betweenPlan)}
+	mapping := &betweenMapping{plan: b.build(st, dt)}
+	mismatch := &FieldMismatchError{Src: st, Dst: dt}
+	mapping.plan.collectMismatch("", mismatch, make(map[*betweenPlan]bool))
+	if len(mismatch.SourceOnly) > 0 || len(mismatch.DestOnly) > 0 || len(mismatch.Incompatible) > 0 ||
+		len(mismatch.Collisions) > 0 {
+		mapping.err = mismatch
+	}
+
+	actual, _ := betweenMappings.LoadOrStore(key, mapping)
+	return actual.(*betweenMapping)
+}
+
+// planBuilder 创建字段映射
+type planBuilder struct {
+	policy   ConversionPolicy
+	renames  map[string]string         // 源字段名 -> 目标字段名
+	building map[typePair]*betweenPlan // 已创建的映射，递归类型复用同一个映射
+}
+
+// targetName 源字段改名后的名称
+func (b *planBuilder) targetName(name string) string {
+	if to, ok := b.renames[name]; ok {
+		return to
+	}
+	return name
+}
+
+// build 匹配两个结构体的字段
+func (b *planBuilder) build(st, dt reflect.Type) *betweenPlan {
+	key := typePair{st, dt}
+	if plan, ok := b.building[key]; ok {
+		return plan
+	}
+	plan := &betweenPlan{}
+	b.building[key] = plan
+
+	// 只匹配源结构体的直接导出字段，按改名后的名称分组
+	sources := make(map[string][]int)
+	for i := 0; i < st.NumField(); i++ {
+		if sf := st.Field(i); sf.PkgPath == "" {
+			name := b.targetName(sf.Name)
+			sources[name] = append(sources[name], i)
+		}
+	}
+
+	for i := 0; i < dt.NumField(); i++ {
+		df := dt.Field(i)
+		if df.PkgPath != "" {
+			continue
+		}
+		matched := sources[df.Name]
+		if len(matched) == 0 {
+			plan.destOnly = append(plan.destOnly, df.Name)
+			continue
+		}
+		if len(matched) > 1 {
+			names := make([]string, len(matched))
+			for n, idx := range matched {
+				names[n] = st.Field(idx).Name
+			}
+			plan.collisions = append(plan.collisions, fmt.Sprintf("%s (%s)", df.Name, strings.Join(names, ", ")))
+			continue
+		}
+		sf := st.Field(matched[0])
+
+		field := betweenField{name: df.Name, src: matched[0], dst: i}
+		switch {
+		case sf.Type == df.Type:
+			field.mode = betweenDeepCopy
+		case sf.Type.Kind() == reflect.Struct && df.Type.Kind() == reflect.Struct:
+			field.mode = betweenStruct
+			field.plan = b.build(sf.Type, df.Type)
+		case sf.Type.Kind() == reflect.Ptr && df.Type.Kind() == reflect.Ptr &&
+			sf.Type.Elem().Kind() == reflect.Struct && df.Type.Elem().Kind() == reflect.Struct:
+			field.mode = betweenPtr
+			field.plan = b.build(sf.Type.Elem(), df.Type.Elem())
+		default:
+			if reason := b.conversion(sf.Type, df.Type); reason != "" {
+				plan.incompatible = append(plan.incompatible, fmt.Sprintf("%s (%s -> %s%s)", df.Name, sf.Type, df.Type, reason))
+				continue
+			}
+			field.mode = betweenConvert
+		}
+		plan.fields = append(plan.fields, field)
+	}
+
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := b.targetName(sf.Name)
+		if df, ok := dt.FieldByName(name); !ok || df.PkgPath != "" || len(df.Index) != 1 {
+			if name != sf.Name {
+				plan.sourceOnly = append(plan.sourceOnly, sf.Name+" -> "+name)
+			} else {
+				plan.sourceOnly = append(plan.sourceOnly, sf.Name)
+			}
+		}
+	}
+	return plan
+}
+
+// conversion 检查字段能否按策略转换，不能时返回附加在错误信息中的原因
+func (b *planBuilder) conversion(st, dt reflect.Type) string {
+	if loss, ok := numericConversionLoss(st, dt); ok {
+		switch {
+		case b.policy.allows(loss):
+			return ""
+		case loss == lossOverflow:
+			return ", narrowing"
+		default:
+			return ", lossy"
+		}
+	}
+	// 同种类的命名类型，如 type IDs []int 与 []int
+	if st.Kind() == dt.Kind() && st.ConvertibleTo(dt) {
+		return ""
+	}
+	return ", not convertible"
+}
+
+// collectMismatch 汇总映射及其嵌套映射中的不匹配字段，每个映射只记录一次
+func (p *betweenPlan) collectMismatch(prefix string, e *FieldMismatchError, seen map[*betweenPlan]bool) {
+	if seen[p] {
+		return
+	}
+	seen[p] = true
+	for _, name := range p.sourceOnly {
+		e.SourceOnly = append(e.SourceOnly, prefix+name)
+	}
+	for _, name := range p.destOnly {
+		e.DestOnly = append(e.DestOnly, prefix+name)
+	}
+	for _, desc := range p.incompatible {
+		e.Incompatible = append(e.Incompatible, prefix+desc)
+	}
+	for _, desc := range p.collisions {
+		e.Collisions = append(e.Collisions, prefix+desc)
+	}
+	for _, f := range p.fields {
+		if f.plan != nil {
+			f.plan.collectMismatch(prefix+f.name+".", e, seen)
+		}
+	}
+}
+
+// copyBetween 按映射把结构体 src 的字段拷贝到 dst 中
+func (s *copyState) copyBetween(p *betweenPlan, src, dst reflect.Value) {
+	skip := s.betweenSkipper(src.Type(), dst.Type())
+	for _, f := range p.fields {
+		if s.err != nil {
+			return
+		}
+		if skip(f, src) {
+			continue
+		}
+		s.pushField(dst.Type(), f.dst)
+		sv, dv := src.Field(f.src), dst.Field(f.dst)
+		switch f.mode {
+		case betweenDeepCopy:
+			s.copyRecursive(sv, dv)
+		case betweenStruct:
+			s.copyBetween(f.plan, sv, dv)
+		case betweenPtr:
+			s.copyBetweenPtr(f.plan, sv, dv)
+		case betweenConvert:
+			tmp := reflect.New(sv.Type()).Elem()
+			s.copyRecursive(sv, tmp)
+			dv.Set(tmp.Convert(dv.Type()))
+		}
+		s.popPath()
+	}
+}
+
+// copyBetweenPtr 为指向不同结构体的指针分配新对象，同一个源指针只拷贝一次
+func (s *copyState) copyBetweenPtr(p *betweenPlan, src, dst reflect.Value) {
+	if src.IsNil() {
+		dst.Set(reflect.Zero(dst.Type()))
+		return
+	}
+	key := refKey{ptr: src.Pointer(), typ: dst.Type()}
+	if v, ok := s.lookupRef(key); ok {
+		dst.Set(v)
+		return
+	}
+	newPtr := s.cfg.allocator.New(dst.Type().Elem())
+	dst.Set(newPtr)
+	s.markRef(key, newPtr)
+	s.copyBetween(p, src.Elem(), newPtr.Elem())
+}
+
+// betweenSkipper 返回判断字段是否跳过的函数：源或目标字段带 redact 标签，或被 WithFieldFilter、
+// WithTagWhitelist（按源字段的标签）、WithSkipZeroSource 排除时跳过，目标字段保持零值
+func (s *copyState) betweenSkipper(st, dt reflect.Type) func(betweenField, reflect.Value) bool {
+	srcRedacted := s.manager.getOrAnalyzeType(st).RedactedFieldIndices
+	dstRedacted := s.manager.getOrAnalyzeType(dt).RedactedFieldIndices
+	var allowed []int
+	if s.cfg.whitelist {
+		allowed = s.manager.getOrAnalyzeType(st).whitelistFields(s.manager, st, s.cfg.whitelistKey)
+	}
+	return func(f betweenField, src reflect.Value) bool {
+		switch {
+		case slices.Contains(srcRedacted, f.src), slices.Contains(dstRedacted, f.dst):
+			return true
+		case s.cfg.whitelist && !slices.Contains(allowed, f.src):
+			return true
+		case s.cfg.fieldFilter != nil && !s.cfg.fieldFilter(st.Field(f.src)):
+			return true
+		}
+		return s.cfg.skipZeroSource && src.Field(f.src).IsZero()
+	}
+}
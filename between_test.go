@@ -0,0 +1,391 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// APIUserV1 / APIUserV2 两个版本的生成类型
+type APIUserV1 struct {
+	ID       int
+	Name     string
+	Tags     []string
+	Address  APIAddressV1
+	Manager  *APIUserV1
+	Legacy   string
+	Age      string
+	internal int
+}
+
+type APIAddressV1 struct {
+	City string
+	Zip  int
+}
+
+type APIUserV2 struct {
+	ID      int
+	Name    string
+	Tags    []string
+	Address APIAddressV2
+	Manager *APIUserV2
+	Email   string
+	Age     int
+}
+
+type APIAddressV2 struct {
+	City    string
+	Country string
+}
+
+func TestCopyBetween(t *testing.T) {
+	boss := &APIUserV1{ID: 1, Name: "boss"}
+	boss.Manager = boss // 自己管理自己
+	src := APIUserV1{
+		ID:      2,
+		Name:    "alice",
+		Tags:    []string{"admin"},
+		Address: APIAddressV1{City: "Paris", Zip: 75001},
+		Manager: boss,
+		Legacy:  "old",
+		Age:     "30",
+	}
+
+	dst, err := CopyBetween[APIUserV2](src)
+	var mismatch *FieldMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected *FieldMismatchError, got %v", err)
+	}
+	if want := []string{"Legacy", "Address.Zip"}; !reflect.DeepEqual(mismatch.SourceOnly, want) {
+		t.Errorf("SourceOnly = %v, want %v", mismatch.SourceOnly, want)
+	}
+	if want := []string{"Email", "Address.Country"}; !reflect.DeepEqual(mismatch.DestOnly, want) {
+		t.Errorf("DestOnly = %v, want %v", mismatch.DestOnly, want)
+	}
+	if want := []string{"Age (string -> int, not convertible)"}; !reflect.DeepEqual(mismatch.Incompatible, want) {
+		t.Errorf("Incompatible = %v, want %v", mismatch.Incompatible, want)
+	}
+
+	// 已匹配的字段仍然被拷贝
+	if dst.ID != 2 || dst.Name != "alice" || dst.Address.City != "Paris" || dst.Age != 0 {
+		t.Errorf("dst = %+v", dst)
+	}
+	dst.Tags[0] = "changed"
+	if src.Tags[0] != "admin" {
+		t.Error("Tags should be deep copied")
+	}
+	if dst.Manager == nil || dst.Manager.Name != "boss" || dst.Manager.Manager != dst.Manager {
+		t.Errorf("Manager cycle not preserved: %+v", dst.Manager)
+	}
+}
+
+func TestCopyBetweenPointers(t *testing.T) {
+	type pointV1 struct{ X, Y int }
+	type pointV2 struct{ X, Y int }
+
+	dst, err := CopyBetween[*pointV2](&pointV1{X: 1, Y: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *dst != (pointV2{X: 1, Y: 2}) {
+		t.Errorf("dst = %+v", *dst)
+	}
+	if dst, err := CopyBetween[*pointV2]((*pointV1)(nil)); err != nil || dst != nil {
+		t.Errorf("nil source: %v, %v", dst, err)
+	}
+	if _, err := CopyBetween[int](pointV1{}); !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("expected ErrTypeConversion, got %v", err)
+	}
+}
+
+// 字段映射按类型对缓存
+func TestCopyBetweenCached(t *testing.T) {
+	CopyBetween[APIUserV2](APIUserV1{})
+	key := betweenKey{typePair{reflect.TypeOf(APIUserV1{}), reflect.TypeOf(APIUserV2{})}, ConvertSafe, ""}
+	first, ok := betweenMappings.Load(key)
+	if !ok {
+		t.Fatal("mapping should be cached")
+	}
+	CopyBetween[APIUserV2](APIUserV1{})
+	if second, _ := betweenMappings.Load(key); second != first {
+		t.Error("mapping should be reused")
+	}
+}
+
+// 数值类型和命名类型之间的转换矩阵
+type (
+	convMyString string
+	convMyInt    int
+	convIDs      []int
+)
+
+func TestCopyBetweenConversions(t *testing.T) {
+	type narrow struct{ V int8 }
+	type wide struct{ V int64 }
+
+	tests := []struct {
+		name string
+		copy func(opts ...Option) (any, error)
+		want map[ConversionPolicy]any // 策略允许时的结果，不在其中的策略应报告不兼容
+	}{
+		{
+			name: "int8 -> int64",
+			copy: func(opts ...Option) (any, error) { return CopyBetween[wide](narrow{V: -3}, opts...) },
+			want: map[ConversionPolicy]any{ConvertLossless: wide{-3}, ConvertSafe: wide{-3}, ConvertNarrowing: wide{-3}},
+		},
+		{
+			name: "int64 -> int8",
+			copy: func(opts ...Option) (any, error) { return CopyBetween[narrow](wide{V: 300}, opts...) },
+			want: map[ConversionPolicy]any{ConvertNarrowing: narrow{V: 44}},
+		},
+		{
+			name: "uint16 -> int32",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V int32 }](struct{ V uint16 }{65535}, opts...)
+			},
+			want: map[ConversionPolicy]any{
+				ConvertLossless: struct{ V int32 }{65535}, ConvertSafe: struct{ V int32 }{65535}, ConvertNarrowing: struct{ V int32 }{65535},
+			},
+		},
+		{
+			name: "uint32 -> int32",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V int32 }](struct{ V uint32 }{7}, opts...)
+			},
+			want: map[ConversionPolicy]any{ConvertNarrowing: struct{ V int32 }{7}},
+		},
+		{
+			name: "int32 -> uint64",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V uint64 }](struct{ V int32 }{7}, opts...)
+			},
+			want: map[ConversionPolicy]any{ConvertNarrowing: struct{ V uint64 }{7}},
+		},
+		{
+			name: "int32 -> float64",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V float64 }](struct{ V int32 }{-5}, opts...)
+			},
+			want: map[ConversionPolicy]any{
+				ConvertLossless: struct{ V float64 }{-5}, ConvertSafe: struct{ V float64 }{-5}, ConvertNarrowing: struct{ V float64 }{-5},
+			},
+		},
+		{
+			name: "int64 -> float64",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V float64 }](struct{ V int64 }{1 << 40}, opts...)
+			},
+			want: map[ConversionPolicy]any{ConvertSafe: struct{ V float64 }{1 << 40}, ConvertNarrowing: struct{ V float64 }{1 << 40}},
+		},
+		{
+			name: "float32 -> float64",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V float64 }](struct{ V float32 }{1.5}, opts...)
+			},
+			want: map[ConversionPolicy]any{
+				ConvertLossless: struct{ V float64 }{1.5}, ConvertSafe: struct{ V float64 }{1.5}, ConvertNarrowing: struct{ V float64 }{1.5},
+			},
+		},
+		{
+			name: "float64 -> int",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V int }](struct{ V float64 }{2.9}, opts...)
+			},
+			want: map[ConversionPolicy]any{ConvertNarrowing: struct{ V int }{2}},
+		},
+		{
+			name: "complex64 -> complex128",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V complex128 }](struct{ V complex64 }{1 + 2i}, opts...)
+			},
+			want: map[ConversionPolicy]any{
+				ConvertLossless: struct{ V complex128 }{1 + 2i}, ConvertSafe: struct{ V complex128 }{1 + 2i}, ConvertNarrowing: struct{ V complex128 }{1 + 2i},
+			},
+		},
+		{
+			name: "named string",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V string }](struct{ V convMyString }{"x"}, opts...)
+			},
+			want: map[ConversionPolicy]any{
+				ConvertLossless: struct{ V string }{"x"}, ConvertSafe: struct{ V string }{"x"}, ConvertNarrowing: struct{ V string }{"x"},
+			},
+		},
+		{
+			name: "named int to int64",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V int64 }](struct{ V convMyInt }{9}, opts...)
+			},
+			want: map[ConversionPolicy]any{
+				ConvertLossless: struct{ V int64 }{9}, ConvertSafe: struct{ V int64 }{9}, ConvertNarrowing: struct{ V int64 }{9},
+			},
+		},
+		{
+			name: "int to string",
+			copy: func(opts ...Option) (any, error) {
+				return CopyBetween[struct{ V string }](struct{ V int }{65}, opts...)
+			},
+			want: map[ConversionPolicy]any{},
+		},
+	}
+
+	policies := []ConversionPolicy{ConvertLossless, ConvertSafe, ConvertNarrowing}
+	for _, tt := range tests {
+		for _, policy := range policies {
+			got, err := tt.copy(WithConversionPolicy(policy))
+			want, allowed := tt.want[policy]
+			var mismatch *FieldMismatchError
+			switch {
+			case allowed && err != nil:
+				t.Errorf("%s (policy %d): unexpected error %v", tt.name, policy, err)
+			case allowed && !reflect.DeepEqual(got, want):
+				t.Errorf("%s (policy %d): got %+v, want %+v", tt.name, policy, got, want)
+			case !allowed && (!errors.As(err, &mismatch) || len(mismatch.Incompatible) != 1):
+				t.Errorf("%s (policy %d): expected one incompatible field, got %v", tt.name, policy, err)
+			}
+		}
+	}
+}
+
+// 同种类的命名类型在深拷贝后转换，不与源值共享
+func TestCopyBetweenNamedSlice(t *testing.T) {
+	type withIDs struct{ IDs convIDs }
+	type withInts struct{ IDs []int }
+
+	src := withIDs{IDs: convIDs{1, 2}}
+	dst, err := CopyBetween[withInts](src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst.IDs[0] = 100
+	if src.IDs[0] != 1 {
+		t.Error("converted slice should not share storage with src")
+	}
+}
+
+// RowUser / ModelUser 命名习惯不同的两层结构体
+type RowUser struct {
+	UserID   int
+	Name     string
+	Settings RowSettings
+}
+
+type RowSettings struct {
+	UserID int
+	Theme  string
+}
+
+type ModelUser struct {
+	ID       int
+	Name     string
+	Settings ModelSettings
+}
+
+type ModelSettings struct {
+	ID    int
+	Theme string
+}
+
+func TestCopyBetweenFieldRename(t *testing.T) {
+	src := RowUser{UserID: 7, Name: "alice", Settings: RowSettings{UserID: 7, Theme: "dark"}}
+	dst, err := CopyBetween[ModelUser](src, WithFieldRename(map[string]string{"UserID": "ID"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (ModelUser{ID: 7, Name: "alice", Settings: ModelSettings{ID: 7, Theme: "dark"}}); dst != want {
+		t.Errorf("got %+v, want %+v", dst, want)
+	}
+
+	// 不改名时 UserID 与 ID 互不匹配，映射按改名内容分别缓存
+	_, err = CopyBetween[ModelUser](src)
+	var mismatch *FieldMismatchError
+	if !errors.As(err, &mismatch) || !reflect.DeepEqual(mismatch.DestOnly, []string{"ID", "Settings.ID"}) {
+		t.Errorf("without rename: %v", err)
+	}
+
+	// 改名后的目标字段不存在
+	dst, err = CopyBetween[ModelUser](src, WithFieldRename(map[string]string{"UserID": "Identifier"}))
+	if !errors.As(err, &mismatch) ||
+		!reflect.DeepEqual(mismatch.SourceOnly, []string{"UserID -> Identifier", "Settings.UserID -> Identifier"}) {
+		t.Errorf("missing target: %v", err)
+	}
+	if dst.Name != "alice" || dst.ID != 0 {
+		t.Errorf("missing target: got %+v", dst)
+	}
+
+	// 改名后与同名字段冲突，目标字段被跳过
+	renames := map[string]string{"UserID": "Name"}
+	dst, err = CopyBetween[ModelUser](src, WithFieldRename(renames))
+	renames["UserID"] = "ID" // 选项保存的是映射的副本
+	if !errors.As(err, &mismatch) || !reflect.DeepEqual(mismatch.Collisions, []string{"Name (UserID, Name)"}) {
+		t.Errorf("collision: %v", err)
+	}
+	if dst.Name != "" || dst.Settings.Theme != "dark" {
+		t.Errorf("collision: got %+v", dst)
+	}
+}
+
+// BetweenLogin / BetweenLoginView 带脱敏和白名单标签的两个结构体
+type BetweenLogin struct {
+	User    string `deepcopy:"include"`
+	Pass    string `deepcopy:"redact"`
+	Token   string
+	Profile BetweenProfile `deepcopy:"include"`
+}
+
+type BetweenProfile struct {
+	Nick  string `deepcopy:"include"`
+	Email string
+}
+
+type BetweenLoginView struct {
+	User    string
+	Pass    string
+	Token   string `deepcopy:"redact"`
+	Profile BetweenProfileView
+}
+
+type BetweenProfileView struct {
+	Nick  string
+	Email string
+}
+
+func TestCopyBetweenFieldOptions(t *testing.T) {
+	src := BetweenLogin{User: "u", Pass: "s", Token: "t", Profile: BetweenProfile{Nick: "n", Email: "e"}}
+
+	// 源或目标字段带 redact 标签
+	got, err := CopyBetween[BetweenLoginView](src)
+	want := BetweenLoginView{User: "u", Profile: BetweenProfileView{Nick: "n", Email: "e"}}
+	if err != nil || got != want {
+		t.Errorf("redact: got %+v, %v, want %+v", got, err, want)
+	}
+
+	// 白名单按源字段的标签，嵌套结构体同样生效
+	got, err = CopyBetween[BetweenLoginView](src, WithTagWhitelist(""))
+	want = BetweenLoginView{User: "u", Profile: BetweenProfileView{Nick: "n"}}
+	if err != nil || got != want {
+		t.Errorf("whitelist: got %+v, %v, want %+v", got, err, want)
+	}
+
+	got, err = CopyBetween[BetweenLoginView](src, WithFieldFilter(func(f reflect.StructField) bool {
+		return f.Name != "Email"
+	}))
+	want = BetweenLoginView{User: "u", Profile: BetweenProfileView{Nick: "n"}}
+	if err != nil || got != want {
+		t.Errorf("field filter: got %+v, %v, want %+v", got, err, want)
+	}
+
+	// 目标总是新值，零值的源字段被跳过后仍为零值；非 nil 的空切片不是零值，照常拷贝
+	type counters struct {
+		A, B int32
+		Tags []string
+	}
+	type counters64 struct {
+		A, B int64
+		Tags []string
+	}
+	skipped, err := CopyBetween[counters64](counters{A: 1, Tags: []string{}}, WithSkipZeroSource())
+	if err != nil || skipped.A != 1 || skipped.B != 0 || skipped.Tags == nil {
+		t.Errorf("skip zero source: got %+v, %v", skipped, err)
+	}
+}
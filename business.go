@@ -0,0 +1,35 @@
+package deepcopy
+
+import "reflect"
+
+// TypedBusinessCopy 绑定业务 key 和类型 T 的拷贝器，与 CopyWithKey 使用相同的优化，
+// 但类型信息保存在自身中而不是全局缓存里，不需要每次按 key 查找，也不会因为同一个 key
+// 被用于不同类型而取到错误的缓存。通常每个业务场景创建一个，作为结构体字段持有
+type TypedBusinessCopy[T any] struct {
+	key  string
+	info BusinessCopyInfo
+}
+
+// NewTypedBusinessCopy 创建业务 key 对应的类型化拷贝器，类型分析在首次拷贝或 WarmUp 时进行
+func NewTypedBusinessCopy[T any](key string) *TypedBusinessCopy[T] {
+	return &TypedBusinessCopy[T]{
+		key:  key,
+		info: BusinessCopyInfo{rtype: reflect.TypeOf((*T)(nil)).Elem()},
+	}
+}
+
+// Key 返回业务 key
+func (b *TypedBusinessCopy[T]) Key() string {
+	return b.key
+}
+
+// WarmUp 立即完成类型分析，避免首次拷贝时的延迟
+func (b *TypedBusinessCopy[T]) WarmUp() {
+	b.info.once.Do(b.info.initializeCopyInfo)
+}
+
+// Copy 创建 src 的深拷贝
+func (b *TypedBusinessCopy[T]) Copy(src T) T {
+	b.WarmUp()
+	return copyWithInfo(src, &b.info)
+}
@@ -0,0 +1,119 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnmatchedTagsError 按标签拷贝时存在未匹配的字段
+// 返回该错误时目标值中已匹配的字段仍然被正常拷贝，调用方可以选择忽略
+type UnmatchedTagsError struct {
+	Tag    string   // 使用的标签名
+	Source []string // 源结构体中未匹配的标签值
+	Dest   []string // 目标结构体中未匹配的标签值
+}
+
+func (e *UnmatchedTagsError) Error() string {
+	return fmt.Sprintf("deepcopy: unmatched %q tags, source: %v, dest: %v", e.Tag, e.Source, e.Dest)
+}
+
+// CopyByTag 按标签值（而不是字段名）在两个结构体之间拷贝字段
+// 例如 `db:"user_id"` 的源字段会被深拷贝到同样标签的目标字段。
+// src 和 D 可以是结构体或结构体指针，只处理带有该标签的导出字段，
+// 标签值取逗号前的部分，"-" 表示忽略。存在未匹配字段时返回 *UnmatchedTagsError。
+func CopyByTag[D any](src any, tag string) (D, error) {
+	var dst D
+
+	srcVal := reflect.ValueOf(src)
+	for srcVal.Kind() == reflect.Ptr {
+		if srcVal.IsNil() {
+			return dst, nil
+		}
+		srcVal = srcVal.Elem()
+	}
+	if srcVal.Kind() != reflect.Struct {
+		return dst, fmt.Errorf("deepcopy: CopyByTag source must be a struct, got %T", src)
+	}
+
+	dstVal := reflect.ValueOf(&dst).Elem()
+	if dstVal.Kind() == reflect.Ptr {
+		dstVal.Set(reflect.New(dstVal.Type().Elem()))
+		dstVal = dstVal.Elem()
+	}
+	if dstVal.Kind() != reflect.Struct {
+		return dst, fmt.Errorf("deepcopy: CopyByTag destination must be a struct, got %s", dstVal.Type())
+	}
+
+	srcFields := taggedFields(srcVal.Type(), tag)
+	dstFields := taggedFields(dstVal.Type(), tag)
+
+	state := newCopyState(&defaultCopyConfig)
+	unmatched := &UnmatchedTagsError{Tag: tag}
+	for _, name := range sortedTagNames(srcFields) {
+		dstIndex, ok := dstFields[name]
+		if !ok {
+			unmatched.Source = append(unmatched.Source, name)
+			continue
+		}
+		delete(dstFields, name)
+
+		srcField := srcVal.Field(srcFields[name])
+		dstField := dstVal.Field(dstIndex)
+		if !srcField.Type().AssignableTo(dstField.Type()) {
+			return dst, fmt.Errorf("deepcopy: tag %q: cannot copy %s to %s", name, srcField.Type(), dstField.Type())
+		}
+		// DeepCopy 方法返回的错误和遍历中的 panic 作为错误返回
+		err := state.run(func() {
+			state.pushField(srcVal.Type(), srcFields[name])
+			if srcField.Type() == dstField.Type() {
+				state.copyRecursive(srcField, dstField)
+			} else {
+				tmp := reflect.New(srcField.Type()).Elem()
+				state.copyRecursive(srcField, tmp)
+				dstField.Set(tmp)
+			}
+			state.popPath()
+		})
+		if err != nil {
+			var zero D
+			return zero, err
+		}
+	}
+	unmatched.Dest = sortedTagNames(dstFields)
+
+	if len(unmatched.Source) > 0 || len(unmatched.Dest) > 0 {
+		return dst, unmatched
+	}
+	return dst, nil
+}
+
+// taggedFields 返回结构体中带有指定标签的导出字段，key 为标签值，value 为字段下标
+func taggedFields(t reflect.Type, tag string) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+// sortedTagNames 按字段下标顺序返回标签值，保证结果稳定
+func sortedTagNames(fields map[string]int) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return fields[names[i]] < fields[names[j]]
+	})
+	return names
+}
@@ -0,0 +1,107 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type UserModel struct {
+	ID       int               `json:"id"`
+	FullName string            `json:"name"`
+	Emails   []string          `json:"emails"`
+	Extra    map[string]string `json:"extra,omitempty"`
+	Password string            `json:"-"`
+}
+
+type UserDTO struct {
+	UserID    int               `json:"id"`
+	Name      string            `json:"name"`
+	Addresses []string          `json:"emails"`
+	Meta      map[string]string `json:"extra"`
+}
+
+func TestCopyByTag(t *testing.T) {
+	model := &UserModel{
+		ID:       7,
+		FullName: "Alice",
+		Emails:   []string{"a@example.com"},
+		Extra:    map[string]string{"k": "v"},
+		Password: "secret",
+	}
+
+	dto, err := CopyByTag[UserDTO](model, "json")
+	if err != nil {
+		t.Fatalf("CopyByTag: %v", err)
+	}
+
+	want := UserDTO{UserID: 7, Name: "Alice", Addresses: []string{"a@example.com"}, Meta: map[string]string{"k": "v"}}
+	if !reflect.DeepEqual(dto, want) {
+		t.Errorf("got %+v, want %+v", dto, want)
+	}
+
+	dto.Addresses[0] = "changed"
+	dto.Meta["k"] = "changed"
+	if model.Emails[0] != "a@example.com" || model.Extra["k"] != "v" {
+		t.Error("modifying the DTO should not affect the model")
+	}
+
+	// 目标为指针
+	ptr, err := CopyByTag[*UserDTO](*model, "json")
+	if err != nil || ptr == nil || ptr.UserID != 7 {
+		t.Errorf("pointer destination: got %+v, %v", ptr, err)
+	}
+}
+
+type PartialDTO struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+func TestCopyByTagUnmatched(t *testing.T) {
+	dto, err := CopyByTag[PartialDTO](UserModel{FullName: "Bob"}, "json")
+
+	var unmatched *UnmatchedTagsError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("expected *UnmatchedTagsError, got %v", err)
+	}
+	if !reflect.DeepEqual(unmatched.Source, []string{"id", "emails", "extra"}) {
+		t.Errorf("Source: got %v", unmatched.Source)
+	}
+	if !reflect.DeepEqual(unmatched.Dest, []string{"phone"}) {
+		t.Errorf("Dest: got %v", unmatched.Dest)
+	}
+	if dto.Name != "Bob" {
+		t.Errorf("matched fields should still be copied, got %q", dto.Name)
+	}
+}
+
+func TestCopyByTagTypeMismatch(t *testing.T) {
+	type Dst struct {
+		ID string `json:"id"`
+	}
+	if _, err := CopyByTag[Dst](UserModel{ID: 1}, "json"); err == nil {
+		t.Error("expected error for mismatched field types")
+	}
+}
+
+// DeepCopy 方法返回的错误作为错误返回，带有字段路径
+func TestCopyByTagCopierError(t *testing.T) {
+	type sealedRow struct {
+		Secret SealedSecret `db:"secret"`
+	}
+	type sealedRecord struct {
+		Secret SealedSecret `db:"secret"`
+	}
+
+	_, err := CopyByTag[sealedRecord](sealedRow{Secret: SealedSecret{Cipher: "x", fail: true}}, "db")
+	var copierErr *CopierError
+	if !errors.As(err, &copierErr) || !errors.Is(err, errSidecar) || copierErr.Path != "Secret" {
+		t.Fatalf("expected *CopierError at Secret, got %v", err)
+	}
+
+	got, err := CopyByTag[sealedRecord](sealedRow{Secret: SealedSecret{Cipher: "x"}}, "db")
+	if err != nil || got.Secret.Cipher != "x'" {
+		t.Errorf("got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,131 @@
+package deepcopy
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock 时间来源，DeepCopy 方法通过 GetCopyContext().Clock() 获取当前时间，测试中可替换为 FakeClock
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock 使用 time.Now 的默认时间来源
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FakeClock 返回固定时间的 Clock，只有调用 Set 或 Advance 时才改变，可并发使用
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock 创建当前时间为 now 的 FakeClock
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now 返回设置的时间
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set 设置当前时间
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	c.now = now
+	c.mu.Unlock()
+}
+
+// Advance 将当前时间向后推进 d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// CopyContext 拷贝上下文，在 DeepCopy 方法中通过 GetCopyContext 获取
+type CopyContext struct {
+	clock Clock
+}
+
+// Clock 返回本次拷贝使用的时间来源，未指定时为系统时间
+func (c *CopyContext) Clock() Clock {
+	return c.clock
+}
+
+// defaultCopyContext 不在拷贝中或拷贝未指定上下文时返回的上下文
+var defaultCopyContext = &CopyContext{clock: systemClock{}}
+
+var (
+	activeCopyContexts atomic.Int32 // 已设置上下文的拷贝数，为 0 时不必查找 goroutine
+	copyContexts       sync.Map     // map[uint64]*CopyContext，按 goroutine id 保存
+)
+
+// GetCopyContext 返回当前 goroutine 上正在进行的拷贝的上下文，供 DeepCopy 方法使用
+// 不在 WithClock 等选项指定的拷贝中时返回默认上下文
+func GetCopyContext() *CopyContext {
+	if activeCopyContexts.Load() == 0 {
+		return defaultCopyContext
+	}
+	if ctx, ok := copyContexts.Load(goroutineID()); ok {
+		return ctx.(*CopyContext)
+	}
+	return defaultCopyContext
+}
+
+// enterCopyContext 为当前 goroutine 设置拷贝上下文，返回恢复原上下文的函数
+// 拷贝在调用方的 goroutine 上同步进行，DeepCopy 方法因此能取到本次拷贝的上下文
+func enterCopyContext(ctx *CopyContext) (leave func()) {
+	id := goroutineID()
+	prev, nested := copyContexts.Load(id)
+	copyContexts.Store(id, ctx)
+	activeCopyContexts.Add(1)
+	return func() {
+		activeCopyContexts.Add(-1)
+		if nested {
+			copyContexts.Store(id, prev)
+		} else {
+			copyContexts.Delete(id)
+		}
+	}
+}
+
+// enterContext 按配置为当前 goroutine 设置拷贝上下文，返回恢复原上下文的函数；未指定 WithClock 时什么也不做
+func (c *copyConfig) enterContext() (leave func()) {
+	if c.clock == nil {
+		return func() {}
+	}
+	return enterCopyContext(&CopyContext{clock: c.clock})
+}
+
+// goroutineID 从栈信息的第一行 "goroutine 123 [running]:" 中解析当前 goroutine 的 id
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	line := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(line, ' '); i >= 0 {
+		line = line[:i]
+	}
+	id, _ := strconv.ParseUint(string(line), 10, 64)
+	return id
+}
+
+// WithClock 指定拷贝期间 GetCopyContext().Clock() 返回的时间来源，对所有接受 Option 的入口生效，传入 nil 时使用系统时间
+func WithClock(c Clock) Option {
+	return func(cfg *copyConfig) {
+		cfg.clock = c
+	}
+}
+
+// CopyWithClock 深拷贝 src，拷贝期间 DeepCopy 方法通过 GetCopyContext().Clock() 取到 clk
+func CopyWithClock[T any](src T, clk Clock) T {
+	return CopyWithOptions(src, WithClock(clk))
+}
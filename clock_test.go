@@ -0,0 +1,97 @@
+package deepcopy
+
+import (
+	"testing"
+	"time"
+)
+
+// StampedRecord 拷贝时记录拷贝时间
+type StampedRecord struct {
+	Name     string
+	CopiedAt time.Time
+}
+
+func (r StampedRecord) DeepCopy() StampedRecord {
+	return StampedRecord{Name: r.Name, CopiedAt: GetCopyContext().Clock().Now()}
+}
+
+type stampedBatch struct {
+	Records []StampedRecord
+	Head    *StampedRecord
+}
+
+func TestCopyWithClock(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := NewFakeClock(fixed)
+
+	copied := CopyWithClock(StampedRecord{Name: "a"}, clock)
+	if !copied.CopiedAt.Equal(fixed) {
+		t.Errorf("CopiedAt = %v, want %v", copied.CopiedAt, fixed)
+	}
+
+	// 嵌套在切片和指针中的值同样取到本次拷贝的时钟
+	clock.Advance(time.Hour)
+	batch := CopyWithClock(stampedBatch{
+		Records: []StampedRecord{{Name: "b"}},
+		Head:    &StampedRecord{Name: "c"},
+	}, clock)
+	want := fixed.Add(time.Hour)
+	if !batch.Records[0].CopiedAt.Equal(want) || !batch.Head.CopiedAt.Equal(want) {
+		t.Errorf("nested CopiedAt = %v / %v, want %v", batch.Records[0].CopiedAt, batch.Head.CopiedAt, want)
+	}
+
+	// 拷贝结束后恢复系统时间
+	if _, ok := GetCopyContext().Clock().(systemClock); !ok {
+		t.Errorf("clock outside copy = %T, want systemClock", GetCopyContext().Clock())
+	}
+	if copied := Copy(StampedRecord{}); copied.CopiedAt.Equal(want) {
+		t.Error("Copy without clock should use the system clock")
+	}
+}
+
+// 其他 goroutine 上的拷贝不受影响
+func TestCopyWithClockOtherGoroutine(t *testing.T) {
+	fixed := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	inner := make(chan time.Time)
+	clock := clockFunc(func() time.Time {
+		go func() { inner <- Copy(StampedRecord{}).CopiedAt }()
+		if got := <-inner; got.Equal(fixed) {
+			t.Error("copy on another goroutine should not see the fake clock")
+		}
+		return fixed
+	})
+
+	if copied := CopyWithClock(StampedRecord{}, clock); !copied.CopiedAt.Equal(fixed) {
+		t.Errorf("CopiedAt = %v, want %v", copied.CopiedAt, fixed)
+	}
+}
+
+// clockFunc 以函数实现 Clock
+type clockFunc func() time.Time
+
+func (f clockFunc) Now() time.Time { return f() }
+
+// 不经过 CopyE 的入口同样使用 WithClock 指定的时钟
+func TestWithClockOtherEntryPoints(t *testing.T) {
+	fixed := time.Date(2030, 6, 7, 8, 9, 10, 0, time.UTC)
+	src := stampedBatch{
+		Records: []StampedRecord{{Name: "a"}},
+		Head:    &StampedRecord{Name: "b"},
+	}
+
+	var dst stampedBatch
+	if err := CopyInto(&dst, src, WithClock(NewFakeClock(fixed))); err != nil {
+		t.Fatalf("CopyInto: %v", err)
+	}
+	if !dst.Records[0].CopiedAt.Equal(fixed) || !dst.Head.CopiedAt.Equal(fixed) {
+		t.Errorf("CopyInto CopiedAt = %v / %v, want %v", dst.Records[0].CopiedAt, dst.Head.CopiedAt, fixed)
+	}
+
+	between, err := CopyBetween[stampedBatch](src, WithClock(NewFakeClock(fixed)))
+	if err != nil {
+		t.Fatalf("CopyBetween: %v", err)
+	}
+	if !between.Records[0].CopiedAt.Equal(fixed) || !between.Head.CopiedAt.Equal(fixed) {
+		t.Errorf("CopyBetween CopiedAt = %v / %v, want %v", between.Records[0].CopiedAt, between.Head.CopiedAt, fixed)
+	}
+}
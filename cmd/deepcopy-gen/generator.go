@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultOutput 默认输出文件名
+const defaultOutput = "deepcopy_gen.go"
+
+// deepcopyPath 运行时库的导入路径，接口字段通过它进行反射拷贝
+const deepcopyPath = "github.com/wsqun/deepcopy"
+
+// config 生成配置
+type config struct {
+	dir             string   // 包目录
+	output          string   // 输出文件名，解析包时会跳过该文件
+	typeNames       []string // 需要生成方法的类型名
+	allowUnexported bool     // 是否允许未导出的引用类型字段
+}
+
+// generator 代码生成器
+type generator struct {
+	cfg         config
+	pkg         *types.Package
+	targets     map[*types.Named]bool // 本次生成 DeepCopy 的类型
+	imports     map[string]string     // 导入路径 -> 包名
+	needManager bool                  // 是否需要反射拷贝管理器（存在接口字段时）
+	tmp         int                   // 临时变量计数
+	buf         bytes.Buffer
+	err         error
+}
+
+// generate 解析包并生成格式化后的源码
+func generate(cfg config) ([]byte, error) {
+	pkg, err := loadPackage(cfg.dir, cfg.output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		cfg:     cfg,
+		pkg:     pkg,
+		targets: make(map[*types.Named]bool),
+		imports: make(map[string]string),
+	}
+
+	var named []*types.Named
+	for _, name := range cfg.typeNames {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in package %s", name, pkg.Name())
+		}
+		n, ok := tn.Type().(*types.Named)
+		if !ok || tn.IsAlias() || n.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("type %s: aliases and generic types are not supported", name)
+		}
+		switch n.Underlying().(type) {
+		case *types.Pointer, *types.Interface:
+			return nil, fmt.Errorf("type %s: pointer and interface types cannot have value-receiver methods", name)
+		}
+		g.targets[n] = true
+		named = append(named, n)
+	}
+
+	for _, n := range named {
+		g.genType(n)
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	return g.finish()
+}
+
+// loadPackage 解析并类型检查目录下的包（跳过测试文件和输出文件）
+func loadPackage(dir, output string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		if name == output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	return conf.Check(bp.Name, fset, files, nil)
+}
+
+// finish 拼接文件头和导入，并格式化源码
+func (g *generator) finish() ([]byte, error) {
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by deepcopy-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.pkg.Name())
+
+	if g.needManager {
+		g.imports[deepcopyPath] = "deepcopy"
+	}
+	if len(g.imports) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		fmt.Fprintf(&out, "import (\n")
+		for _, path := range paths {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		fmt.Fprintf(&out, ")\n\n")
+	}
+	if g.needManager {
+		fmt.Fprintf(&out, "// deepcopyGenManager 用于拷贝接口字段中的动态值\n")
+		fmt.Fprintf(&out, "var deepcopyGenManager = deepcopy.NewDeepCopyManager()\n\n")
+	}
+	out.Write(g.buf.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %v\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
+
+// genType 为单个类型生成 DeepCopy 和 deepCopyMemo 方法
+func (g *generator) genType(n *types.Named) {
+	name := n.Obj().Name()
+	g.printf("// DeepCopy 返回 %s 的深拷贝\n", name)
+	g.printf("func (in %s) DeepCopy() %s {\n", name, name)
+	g.printf("return in.deepCopyMemo(make(map[any]any))\n")
+	g.printf("}\n\n")
+
+	g.printf("// deepCopyMemo 深拷贝 %s，memo 记录已复制的指针以处理循环引用\n", name)
+	g.printf("func (in %s) deepCopyMemo(memo map[any]any) %s {\n", name, name)
+	g.printf("out := in\n")
+	g.copyUnderlying("out", "in", n, n.Underlying(), name)
+	g.printf("return out\n")
+	g.printf("}\n\n")
+}
+
+// copyValue 生成把 src 深拷贝到 dst 的语句
+func (g *generator) copyValue(dst, src string, t types.Type, path string) {
+	if n, ok := t.(*types.Named); ok && g.targets[n] {
+		if g.needsCopy(t, nil) {
+			g.printf("%s = %s.deepCopyMemo(memo)\n", dst, paren(src))
+		} else {
+			g.printf("%s = %s\n", dst, src)
+		}
+		return
+	}
+
+	// 其他实现了 DeepCopy 的类型交给其自身
+	if result := deepCopyResult(t); result != nil {
+		if types.Identical(result, t) {
+			g.printf("%s = %s.DeepCopy()\n", dst, paren(src))
+			return
+		}
+		if ptr, ok := t.Underlying().(*types.Pointer); ok && types.Identical(result, ptr.Elem()) {
+			g.printf("if %s != nil {\n", src)
+			v := g.temp("v")
+			g.printf("%s := %s.DeepCopy()\n", v, paren(src))
+			g.printf("%s = &%s\n", dst, v)
+			g.printf("}\n")
+			return
+		}
+	}
+
+	if !g.needsCopy(t, nil) {
+		g.printf("%s = %s\n", dst, src)
+		return
+	}
+
+	// 结构体和数组先整体赋值，再逐个修正引用类型部分；
+	// 其余类型在 src 为 nil 时 dst 保持零值即可
+	switch t.Underlying().(type) {
+	case *types.Struct, *types.Array:
+		g.printf("%s = %s\n", dst, src)
+	}
+	g.copyUnderlying(dst, src, t, t.Underlying(), path)
+}
+
+// copyUnderlying 按底层类型生成深拷贝语句
+// 结构体和数组要求 dst 已经被赋值为 src，只修正其中需要深拷贝的部分
+func (g *generator) copyUnderlying(dst, src string, t, u types.Type, path string) {
+	switch u := u.(type) {
+	case *types.Pointer:
+		elem := g.typeString(u.Elem())
+		p := g.temp("p")
+		g.printf("if %s != nil {\n", src)
+		g.printf("if v, ok := memo[%s]; ok {\n", src)
+		g.printf("%s = v.(*%s)\n", dst, elem)
+		g.printf("} else {\n")
+		g.printf("%s := new(%s)\n", p, elem)
+		g.printf("memo[%s] = %s\n", src, p)
+		g.copyValue("*"+p, "*"+src, u.Elem(), path)
+		g.printf("%s = %s\n", dst, p)
+		g.printf("}\n")
+		g.printf("}\n")
+
+	case *types.Slice:
+		g.printf("if %s != nil {\n", src)
+		g.printf("%s = make(%s, len(%s), cap(%s))\n", dst, g.typeString(t), src, src)
+		if g.needsCopy(u.Elem(), nil) || deepCopyResult(u.Elem()) != nil {
+			i := g.temp("i")
+			g.printf("for %s := range %s {\n", i, src)
+			g.copyValue(paren(dst)+"["+i+"]", paren(src)+"["+i+"]", u.Elem(), path+"[]")
+			g.printf("}\n")
+		} else {
+			g.printf("copy(%s, %s)\n", dst, src)
+		}
+		g.printf("}\n")
+
+	case *types.Map:
+		k, v := g.temp("k"), g.temp("v")
+		g.printf("if %s != nil {\n", src)
+		g.printf("%s = make(%s, len(%s))\n", dst, g.typeString(t), src)
+		g.printf("for %s, %s := range %s {\n", k, v, src)
+		key, val := k, v
+		if g.needsCopy(u.Key(), nil) {
+			key = g.temp("ck")
+			g.printf("var %s %s\n", key, g.typeString(u.Key()))
+			g.copyValue(key, k, u.Key(), path+"[key]")
+		}
+		if g.needsCopy(u.Elem(), nil) || deepCopyResult(u.Elem()) != nil {
+			val = g.temp("cv")
+			g.printf("var %s %s\n", val, g.typeString(u.Elem()))
+			g.copyValue(val, v, u.Elem(), path+"[]")
+		}
+		g.printf("%s[%s] = %s\n", paren(dst), key, val)
+		g.printf("}\n")
+		g.printf("}\n")
+
+	case *types.Array:
+		if g.needsCopy(u.Elem(), nil) || deepCopyResult(u.Elem()) != nil {
+			i := g.temp("i")
+			g.printf("for %s := range %s {\n", i, src)
+			g.copyValue(paren(dst)+"["+i+"]", paren(src)+"["+i+"]", u.Elem(), path+"[]")
+			g.printf("}\n")
+		}
+
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			f := u.Field(i)
+			if f.Name() == "_" || (!g.needsCopy(f.Type(), nil) && deepCopyResult(f.Type()) == nil) {
+				continue
+			}
+			fieldPath := path + "." + f.Name()
+			if !f.Exported() {
+				if f.Pkg() != g.pkg {
+					g.fail("%s: unexported field of package %s cannot be copied, implement DeepCopy for it", fieldPath, f.Pkg().Path())
+					return
+				}
+				if !g.cfg.allowUnexported {
+					g.fail("%s: unexported reference field (pass -unexported to deep-copy it)", fieldPath)
+					return
+				}
+			}
+			g.copyValue(paren(dst)+"."+f.Name(), paren(src)+"."+f.Name(), f.Type(), fieldPath)
+		}
+
+	case *types.Interface:
+		g.needManager = true
+		g.printf("if %s != nil {\n", src)
+		g.printf("%s = deepcopyGenManager.CopyValue(%s).(%s)\n", dst, src, g.typeString(t))
+		g.printf("}\n")
+	}
+}
+
+// needsCopy 判断类型是否不能通过直接赋值完成深拷贝
+// 通道、函数和 unsafe.Pointer 与反射版 Copy 一致，直接共享
+func (g *generator) needsCopy(t types.Type, seen map[*types.Named]bool) bool {
+	if n, ok := t.(*types.Named); ok {
+		if isTime(n) || seen[n] {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[*types.Named]bool)
+		}
+		seen[n] = true
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+		return true
+	case *types.Array:
+		return g.needsCopy(u.Elem(), seen)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if g.needsCopy(u.Field(i).Type(), seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deepCopyResult 如果类型（非本次生成的目标）已有 DeepCopy() 方法，返回其结果类型
+func deepCopyResult(t types.Type) types.Type {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, "DeepCopy")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return nil
+	}
+	return sig.Results().At(0).Type()
+}
+
+// isTime 判断是否为 time.Time，与反射版 Copy 一致直接按值复制
+func isTime(n *types.Named) bool {
+	obj := n.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time"
+}
+
+// typeString 返回类型在生成文件中的写法，并记录所需导入
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		g.imports[p.Path()] = p.Name()
+		return p.Name()
+	})
+}
+
+// temp 生成唯一的临时变量名
+func (g *generator) temp(prefix string) string {
+	g.tmp++
+	return fmt.Sprintf("%s%d", prefix, g.tmp)
+}
+
+// fail 记录第一个错误
+func (g *generator) fail(format string, args ...interface{}) {
+	if g.err == nil {
+		g.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// paren 为解引用表达式加括号，使其可以继续取字段或下标
+func paren(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
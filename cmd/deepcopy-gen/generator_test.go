@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateRefusesUnexportedReferenceFields(t *testing.T) {
+	_, err := generate(config{
+		dir:       "testdata/unexported",
+		output:    defaultOutput,
+		typeNames: []string{"Secret"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "Secret.cache") {
+		t.Fatalf("expected error about Secret.cache, got %v", err)
+	}
+
+	src, err := generate(config{
+		dir:             "testdata/unexported",
+		output:          defaultOutput,
+		typeNames:       []string{"Secret"},
+		allowUnexported: true,
+	})
+	if err != nil {
+		t.Fatalf("generate with -unexported: %v", err)
+	}
+	if !strings.Contains(string(src), "out.cache = make(map[string]int") {
+		t.Errorf("expected cache to be deep-copied, got:\n%s", src)
+	}
+}
+
+func TestGenerateUnknownType(t *testing.T) {
+	_, err := generate(config{
+		dir:       "testdata/unexported",
+		output:    defaultOutput,
+		typeNames: []string{"Missing"},
+	})
+	if err == nil {
+		t.Fatal("expected error for unknown type")
+	}
+}
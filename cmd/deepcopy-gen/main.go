@@ -0,0 +1,57 @@
+// deepcopy-gen 为指定类型生成 DeepCopy() T 方法
+//
+// 生成的方法会被 deepcopy.Copy 通过 hasDeepCopyMethod 自动识别，调用方无需任何改动。
+// 典型用法（写在类型所在包的任意源文件中）：
+//
+//	//go:generate go run github.com/wsqun/deepcopy/cmd/deepcopy-gen -type=Node,Tree
+//
+// 默认拒绝包含未导出引用类型字段（指针、切片、映射等）的类型，
+// 因为反射版 Copy 会跳过这些字段，传入 -unexported 后会对其进行深拷贝。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("deepcopy-gen: ")
+
+	typeNames := flag.String("type", "", "逗号分隔的类型名列表（必填）")
+	output := flag.String("output", defaultOutput, "输出文件名，相对于包目录")
+	unexported := flag.Bool("unexported", false, "允许并深拷贝未导出的引用类型字段")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: deepcopy-gen -type=T[,T...] [flags] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+
+	src, err := generate(config{
+		dir:             dir,
+		output:          *output,
+		typeNames:       strings.Split(*typeNames, ","),
+		allowUnexported: *unexported,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, *output), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,8 @@
+package unexported
+
+// Secret 包含未导出的引用类型字段
+type Secret struct {
+	Name  string
+	cache map[string]int
+	count int
+}
@@ -0,0 +1,91 @@
+package deepcopy
+
+import (
+	"math"
+	"reflect"
+)
+
+// 拷贝代价估算的权重
+const (
+	scalarCost  = 1  // 基础值、接口、通道、函数
+	pointerCost = 5  // 指针本身，另加指向的值
+	sliceCost   = 10 // 切片按元素摊销：元素代价的倍数
+	mapCost     = 15 // 映射按条目摊销：键和值代价之和的倍数
+)
+
+// CopyComplexity 估算拷贝该类型的值的相对代价：基础值计 1，指针计 5 加指向的值，
+// 切片计元素代价的 10 倍，映射计键和值代价之和的 15 倍，数组按长度累加，结构体累加会被拷贝的字段。
+// 递归引用自身的部分不重复计入。结果只用于比较不同类型，例如决定热点类型是否值得生成 DeepCopy 方法
+func (r *TypeAnalysisResult) CopyComplexity() float64 {
+	return r.complexity
+}
+
+// copyComplexity 计算类型 t 的拷贝代价
+func (m *DeepCopyManager) copyComplexity(t reflect.Type) float64 {
+	w := complexityWalker{m: m, onPath: make(map[reflect.Type]int), memo: make(map[reflect.Type]float64)}
+	cost, _ := w.cost(t)
+	return cost
+}
+
+// noCycle complexityWalker.cost 的结果不依赖路径上的类型
+const noCycle = math.MaxInt
+
+// complexityWalker 计算拷贝代价，onPath 记录当前路径上的类型及其深度，遇到时按 0 计算；
+// 不依赖路径上其他类型的结果缓存在 memo 中，共享的子类型（如多个字段引用同一类型）只计算一次
+type complexityWalker struct {
+	m      *DeepCopyManager
+	onPath map[reflect.Type]int
+	memo   map[reflect.Type]float64
+}
+
+// cost 返回类型 t 的代价，以及结果所依赖的路径上类型的最小深度，不依赖时为 noCycle
+func (w *complexityWalker) cost(t reflect.Type) (float64, int) {
+	if depth, ok := w.onPath[t]; ok {
+		return 0, depth
+	}
+	if cost, ok := w.memo[t]; ok {
+		return cost, noCycle
+	}
+	if !w.m.disableBuiltins && immutableValueTypes[t] {
+		return scalarCost, noCycle
+	}
+
+	depth := len(w.onPath)
+	w.onPath[t] = depth
+	defer delete(w.onPath, t)
+
+	low := noCycle
+	sub := func(t reflect.Type) float64 {
+		cost, l := w.cost(t)
+		low = min(low, l)
+		return cost
+	}
+
+	cost := float64(scalarCost)
+	switch t.Kind() {
+	case reflect.Array:
+		cost = float64(t.Len()) * sub(t.Elem())
+	case reflect.Struct:
+		cost = 0
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			// 未导出字段和脱敏字段不会被拷贝
+			if field.PkgPath == "" && !w.m.isRedacted(field) {
+				cost += sub(field.Type)
+			}
+		}
+	case reflect.Ptr:
+		cost = pointerCost + sub(t.Elem())
+	case reflect.Slice:
+		cost = sliceCost * sub(t.Elem())
+	case reflect.Map:
+		cost = mapCost * (sub(t.Key()) + sub(t.Elem()))
+	}
+
+	// 只依赖自身（或不依赖路径）时结果与经由哪条路径到达无关
+	if low >= depth {
+		w.memo[t] = cost
+		low = noCycle
+	}
+	return cost, low
+}
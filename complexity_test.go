@@ -0,0 +1,91 @@
+package deepcopy
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCopyComplexity(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want float64
+	}{
+		{"int", reflect.TypeOf(0), 1},
+		{"time", reflect.TypeOf(time.Time{}), 1},
+		{"pointer", reflect.TypeOf((*int)(nil)), 6},
+		{"slice", reflect.TypeOf([]int{}), 10},
+		{"map", reflect.TypeOf(map[string]int{}), 30},
+		{"array", reflect.TypeOf([3]string{}), 3},
+		// 递归字段只计指针本身
+		{"cycle", reflect.TypeOf(CircularStruct{}), 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultManager.getOrAnalyzeType(tt.typ).CopyComplexity(); got != tt.want {
+				t.Errorf("CopyComplexity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := AnalyzeType(Basics{}).CopyComplexity(); math.Abs(got-200) > 20 {
+		t.Errorf("Basics complexity = %v, want about 200", got)
+	}
+	if got := AnalyzeType(map[string]*ComplexStruct{}).CopyComplexity(); got <= 500 {
+		t.Errorf("map[string]*ComplexStruct complexity = %v, want > 500", got)
+	}
+	// 脱敏字段不计入
+	if got := AnalyzeType(AuditCredentials{}).CopyComplexity(); got != 1 {
+		t.Errorf("AuditCredentials complexity = %v, want 1", got)
+	}
+}
+
+// DagLevel0 ~ DagLevel24 每层两个字段引用同一个下层类型
+type (
+	DagLevel0  struct{ A, B int }
+	DagLevel1  struct{ A, B *DagLevel0 }
+	DagLevel2  struct{ A, B *DagLevel1 }
+	DagLevel3  struct{ A, B *DagLevel2 }
+	DagLevel4  struct{ A, B *DagLevel3 }
+	DagLevel5  struct{ A, B *DagLevel4 }
+	DagLevel6  struct{ A, B *DagLevel5 }
+	DagLevel7  struct{ A, B *DagLevel6 }
+	DagLevel8  struct{ A, B *DagLevel7 }
+	DagLevel9  struct{ A, B *DagLevel8 }
+	DagLevel10 struct{ A, B *DagLevel9 }
+	DagLevel11 struct{ A, B *DagLevel10 }
+	DagLevel12 struct{ A, B *DagLevel11 }
+	DagLevel13 struct{ A, B *DagLevel12 }
+	DagLevel14 struct{ A, B *DagLevel13 }
+	DagLevel15 struct{ A, B *DagLevel14 }
+	DagLevel16 struct{ A, B *DagLevel15 }
+	DagLevel17 struct{ A, B *DagLevel16 }
+	DagLevel18 struct{ A, B *DagLevel17 }
+	DagLevel19 struct{ A, B *DagLevel18 }
+	DagLevel20 struct{ A, B *DagLevel19 }
+	DagLevel21 struct{ A, B *DagLevel20 }
+	DagLevel22 struct{ A, B *DagLevel21 }
+	DagLevel23 struct{ A, B *DagLevel22 }
+	DagLevel24 struct{ A, B *DagLevel23 }
+)
+
+// 共享的子类型只计算一次，分析时间随层数线性增长
+func TestCopyComplexityDAG(t *testing.T) {
+	want := 2.0
+	for i := 0; i < 24; i++ {
+		want = 2 * (pointerCost + want)
+	}
+
+	done := make(chan float64)
+	go func() { done <- NewDeepCopyManager().getOrAnalyzeType(reflect.TypeOf(DagLevel24{})).CopyComplexity() }()
+	select {
+	case got := <-done:
+		if got != want {
+			t.Errorf("CopyComplexity() = %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("analysis of a DAG-shaped type did not finish")
+	}
+}
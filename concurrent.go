@@ -0,0 +1,177 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// lockerFuncs 某个类型的加锁、解锁函数，参数为指向该类型值的指针
+type lockerFuncs struct {
+	lock   func(reflect.Value)
+	unlock func(reflect.Value)
+}
+
+// 注册的锁类型，key: reflect.Type, value: lockerFuncs
+var lockerRegistry sync.Map
+
+// 默认识别的锁接口
+var (
+	lockerType   = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+	rLockerType  = reflect.TypeOf((*rLocker)(nil)).Elem()
+	rLockerFuncs = lockerFuncs{
+		lock:   func(v reflect.Value) { v.Interface().(rLocker).RLock() },
+		unlock: func(v reflect.Value) { v.Interface().(rLocker).RUnlock() },
+	}
+	plainLockerFuncs = lockerFuncs{
+		lock:   func(v reflect.Value) { v.Interface().(sync.Locker).Lock() },
+		unlock: func(v reflect.Value) { v.Interface().(sync.Locker).Unlock() },
+	}
+)
+
+// rLocker 支持读锁的类型，如 sync.RWMutex
+type rLocker interface {
+	RLock()
+	RUnlock()
+}
+
+// RegisterLocker 为类型 t 注册加锁、解锁函数，ConcurrentCopy 遇到该类型的字段时使用
+// lockFn 和 unlockFn 收到的是指向字段的指针（*t）。
+// 未注册时，实现了 RLock/RUnlock 的类型使用读锁，实现了 sync.Locker 的类型使用 Lock/Unlock
+func RegisterLocker(t reflect.Type, lockFn, unlockFn func(reflect.Value)) {
+	lockerRegistry.Store(t, lockerFuncs{lock: lockFn, unlock: unlockFn})
+}
+
+// lockerFor 返回类型的加锁函数，类型不是锁时返回 false
+func lockerFor(t reflect.Type) (lockerFuncs, bool) {
+	if cached, ok := lockerRegistry.Load(t); ok {
+		return cached.(lockerFuncs), true
+	}
+
+	ptrType := reflect.PointerTo(t)
+	switch {
+	case ptrType.Implements(rLockerType):
+		return rLockerFuncs, true
+	case ptrType.Implements(lockerType):
+		return plainLockerFuncs, true
+	}
+	return lockerFuncs{}, false
+}
+
+// heldLock 已获取的锁
+type heldLock struct {
+	ptr    reflect.Value
+	unlock func(reflect.Value)
+}
+
+// lockVisitKey 已遍历的地址，结构体与其第一个字段地址相同，因此同时以类型区分
+type lockVisitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// lockCollector 遍历源值并按字段顺序获取其中的锁
+type lockCollector struct {
+	manager *DeepCopyManager      // 拷贝所用的管理器，带 lock 标签的锁由拷贝过程获取
+	visited map[lockVisitKey]bool // 已遍历的地址，同时避免对同一把锁重复加锁
+	held    []heldLock
+}
+
+// newLockCollector 创建锁收集器
+func newLockCollector(m *DeepCopyManager) *lockCollector {
+	return &lockCollector{manager: m, visited: make(map[lockVisitKey]bool)}
+}
+
+// visit 标记地址已遍历，已遍历过时返回 false
+func (c *lockCollector) visit(ptr reflect.Value) bool {
+	key := lockVisitKey{ptr: ptr.Pointer(), typ: ptr.Type()}
+	if c.visited[key] {
+		return false
+	}
+	c.visited[key] = true
+	return true
+}
+
+// acquire 遍历 v（必须可寻址）并获取所有锁
+func (c *lockCollector) acquire(v reflect.Value) {
+	if lf, ok := lockerFor(v.Type()); ok && v.CanAddr() {
+		ptr := v.Addr()
+		if !c.visit(ptr) {
+			return
+		}
+		lf.lock(ptr)
+		c.held = append(c.held, heldLock{ptr: ptr, unlock: lf.unlock})
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		// 指向锁本身的指针在 acquire(v.Elem()) 中按锁处理
+		if _, isLock := lockerFor(v.Type().Elem()); !isLock && !c.visit(v) {
+			return
+		}
+		c.acquire(v.Elem())
+
+	case reflect.Interface:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Ptr {
+			c.acquire(v.Elem())
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			// 带 lock 标签的锁在拷贝所在结构体时获取，这里再加锁会重复加锁
+			if c.manager.isLockField(v.Type().Field(i)) {
+				continue
+			}
+			field := v.Field(i)
+			// 锁通常是未导出字段，需要通过地址重新构造才能调用其方法
+			if !field.CanInterface() && field.CanAddr() {
+				field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+			}
+			c.acquire(field)
+		}
+	}
+}
+
+// release 按获取的相反顺序释放所有锁
+func (c *lockCollector) release() {
+	for i := len(c.held) - 1; i >= 0; i-- {
+		c.held[i].unlock(c.held[i].ptr)
+	}
+	c.held = nil
+}
+
+// ConcurrentCopy 在持有源值中所有锁的情况下进行深拷贝，避免并发写入导致拷贝到不一致的状态
+// 沿结构体字段和指针遍历，按字段顺序获取锁（读写锁使用读锁），拷贝完成后按相反顺序释放。
+// 切片和映射中的元素不会被遍历加锁。源值必须通过指针传入，按值传入时锁住的只是副本中的锁，起不到保护作用
+func ConcurrentCopy[T any](src *T) *T {
+	if src == nil {
+		return nil
+	}
+
+	collector := newLockCollector(defaultManager)
+	collector.acquire(reflect.ValueOf(src))
+	defer collector.release()
+
+	return Copy(src)
+}
+
+// ConcurrentCopyValue 在持有源值中所有锁的情况下进行深拷贝（非泛型方法），src 不是指针时 panic
+func (m *DeepCopyManager) ConcurrentCopyValue(src interface{}) interface{} {
+	if src == nil {
+		return nil
+	}
+	if reflect.TypeOf(src).Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("deepcopy: ConcurrentCopyValue needs a pointer so that the source locks are held, got %T", src))
+	}
+
+	collector := newLockCollector(m)
+	collector.acquire(reflect.ValueOf(src))
+	defer collector.release()
+
+	return m.CopyValue(src)
+}
@@ -0,0 +1,219 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type GuardedStore struct {
+	mu    sync.RWMutex
+	Data  map[string]int
+	Child *GuardedChild
+}
+
+type GuardedChild struct {
+	mu    sync.Mutex
+	Items []int
+}
+
+func (s *GuardedStore) set(key string, value int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Data[key] = value
+}
+
+func (c *GuardedChild) push(value int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Items = append(c.Items, value)
+}
+
+func TestConcurrentCopy(t *testing.T) {
+	store := &GuardedStore{Data: map[string]int{}, Child: &GuardedChild{}}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				store.set("k", i)
+				store.Child.push(i)
+			}
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		copied := ConcurrentCopy(store)
+		if copied == store || copied.Child == store.Child {
+			t.Fatal("copy should not share pointers with the original")
+		}
+		if _, ok := NewDeepCopyManager().ConcurrentCopyValue(store).(*GuardedStore); !ok {
+			t.Fatal("ConcurrentCopyValue should return *GuardedStore")
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	copied := ConcurrentCopy(store)
+	if !reflect.DeepEqual(copied.Data, store.Data) || !reflect.DeepEqual(copied.Child.Items, store.Child.Items) {
+		t.Error("final copy should equal the original")
+	}
+}
+
+// 按值传入的源值无法加锁，ConcurrentCopyValue 直接拒绝
+func TestConcurrentCopyNonPointer(t *testing.T) {
+	if ConcurrentCopy[GuardedStore](nil) != nil {
+		t.Error("ConcurrentCopy(nil) should return nil")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("ConcurrentCopyValue with a struct value should panic")
+		}
+	}()
+	NewDeepCopyManager().ConcurrentCopyValue(GuardedStore{})
+}
+
+// customLock 通过 RegisterLocker 注册的锁类型
+type customLock struct {
+	ch chan struct{}
+}
+
+type CustomLocked struct {
+	lock  *customLock
+	Value []int
+}
+
+func TestRegisterLocker(t *testing.T) {
+	var locks, unlocks int
+	RegisterLocker(reflect.TypeOf(customLock{}),
+		func(v reflect.Value) { locks++ },
+		func(v reflect.Value) { unlocks++ },
+	)
+	defer lockerRegistry.Delete(reflect.TypeOf(customLock{}))
+
+	original := &CustomLocked{lock: &customLock{}, Value: []int{1}}
+	copied := ConcurrentCopy(original)
+
+	if locks != 1 || unlocks != 1 {
+		t.Errorf("locks=%d unlocks=%d, want 1 and 1", locks, unlocks)
+	}
+	if copied.Value[0] != 1 {
+		t.Errorf("Value: got %v, want [1]", copied.Value)
+	}
+}
+
+// 拷贝只读取源映射，与其他读取方（包括其他拷贝）并发是安全的
+func TestCopyMapConcurrentRead(t *testing.T) {
+	src := map[string][]int{}
+	for i := 0; i < 100; i++ {
+		src[string(rune('a'+i%26))+string(rune('a'+i/26))] = []int{i}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				var copied map[string][]int
+				if i%2 == 0 {
+					copied = Copy(src)
+				} else {
+					copied = CopyWithOptions(src, WithSnapshotMap())
+				}
+				if !reflect.DeepEqual(copied, src) {
+					t.Error("copy differs from source")
+					return
+				}
+				for _, v := range src {
+					_ = v[0]
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// LockedLedger 由带 lock 标签的读写锁保护，写入方保持 len(Entries) == Totals["count"]
+type LockedLedger struct {
+	mu      sync.RWMutex `deepcopy:"lock"`
+	Entries []int
+	Totals  map[string]int
+}
+
+func (l *LockedLedger) add(v int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Entries = append(l.Entries, v)
+	l.Totals["count"]++
+	l.Totals["sum"] += v
+}
+
+// LockedLedgerBroken 拷贝锁保护的字段时 panic
+type LockedLedgerBroken struct {
+	mu  sync.Mutex `deepcopy:"lock"`
+	Bad ExplodingCopier
+}
+
+func TestLockTag(t *testing.T) {
+	ledger := &LockedLedger{Totals: map[string]int{}}
+	books := []*LockedLedger{ledger, ledger}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	started := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				ledger.add(i)
+			}
+			if i == 0 {
+				close(started)
+			}
+		}
+	}()
+	<-started
+
+	for i := 0; i < 300; i++ {
+		var copies []*LockedLedger
+		switch i % 3 {
+		case 0:
+			copies = []*LockedLedger{Copy(ledger)}
+		case 1:
+			copies = Copy(books)
+		default:
+			// ConcurrentCopy 不重复获取带标签的锁
+			copies = []*LockedLedger{ConcurrentCopy(ledger)}
+		}
+		for _, c := range copies {
+			if len(c.Entries) != c.Totals["count"] {
+				t.Fatalf("inconsistent snapshot: %d entries, count %d", len(c.Entries), c.Totals["count"])
+			}
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	// 拷贝中 panic 时锁同样被释放
+	broken := &LockedLedgerBroken{}
+	var panicErr *PanicError
+	if _, err := CopyE(broken); !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+	if !broken.mu.TryLock() {
+		t.Error("lock should be released after a panic")
+	}
+}
@@ -0,0 +1,63 @@
+package deepcopy
+
+import (
+	"container/list"
+	"container/ring"
+	"reflect"
+)
+
+// 通过公开 API 重建的标准库容器类型。它们只有未导出字段，逐字段拷贝只会得到空的结构；
+// container/heap 没有自己的数据结构，堆化的切片按普通切片拷贝，元素顺序即堆序
+var (
+	listPtrType = reflect.TypeOf((*list.List)(nil))
+	ringPtrType = reflect.TypeOf((*ring.Ring)(nil))
+)
+
+// copyContainer 重建 *list.List 和 *ring.Ring，元素的值逐个深拷贝，返回是否已处理
+func (s *copyState) copyContainer(original, cpy reflect.Value) bool {
+	switch original.Type() {
+	case listPtrType:
+		src := original.Interface().(*list.List)
+		dst := list.New()
+		cpy.Set(reflect.ValueOf(dst))
+		s.markVisited(original.Pointer(), cpy)
+		i := 0
+		for e := src.Front(); e != nil; e = e.Next() {
+			s.pushIndex(i)
+			copied := dst.PushBack(s.copyAny(e.Value))
+			s.popPath()
+			// 其他地方引用的元素指向副本中对应的元素
+			s.markVisited(reflect.ValueOf(e).Pointer(), reflect.ValueOf(copied))
+			i++
+		}
+		return true
+
+	case ringPtrType:
+		src := original.Interface().(*ring.Ring)
+		n := src.Len()
+		dst := ring.New(n)
+		cpy.Set(reflect.ValueOf(dst))
+		// 先记录环中的所有元素，值中对环内元素的引用指向副本中对应的元素
+		for i, r, d := 0, src, dst; i < n; i, r, d = i+1, r.Next(), d.Next() {
+			s.markVisited(reflect.ValueOf(r).Pointer(), reflect.ValueOf(d))
+		}
+		for i, r, d := 0, src, dst; i < n; i, r, d = i+1, r.Next(), d.Next() {
+			s.pushIndex(i)
+			d.Value = s.copyAny(r.Value)
+			s.popPath()
+		}
+		return true
+	}
+	return false
+}
+
+// copyAny 深拷贝容器元素中保存的值
+func (s *copyState) copyAny(v any) any {
+	if v == nil {
+		return nil
+	}
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	s.copyRecursive(src, dst)
+	return dst.Interface()
+}
@@ -0,0 +1,152 @@
+package deepcopy
+
+import (
+	"container/heap"
+	"container/list"
+	"container/ring"
+	"reflect"
+	"testing"
+)
+
+// listValues 按顺序返回链表中的值，同时检查前后链接一致
+func listValues(t *testing.T, l *list.List) []any {
+	t.Helper()
+	var values []any
+	var prev *list.Element
+	for e := l.Front(); e != nil; e = e.Next() {
+		if e.Prev() != prev {
+			t.Fatalf("element %v has broken Prev link", e.Value)
+		}
+		values = append(values, e.Value)
+		prev = e
+	}
+	if l.Back() != prev || len(values) != l.Len() {
+		t.Fatalf("Back/Len mismatch: len %d, values %v", l.Len(), values)
+	}
+	return values
+}
+
+func TestCopyList(t *testing.T) {
+	original := list.New()
+	for i := 1; i <= 5; i++ {
+		original.PushBack(i)
+	}
+
+	copied := Copy(original)
+	if copied == original {
+		t.Fatal("copy should be a new list")
+	}
+
+	original.Front().Value = 100
+	original.Remove(original.Back())
+	original.PushFront(0)
+
+	want := []any{1, 2, 3, 4, 5}
+	if got := listValues(t, copied); !reflect.DeepEqual(got, want) {
+		t.Errorf("copied values = %v, want %v", got, want)
+	}
+	// 副本可以继续正常使用
+	copied.PushBack(6)
+	if copied.Len() != 6 || copied.Back().Value != 6 {
+		t.Errorf("copied list broken after PushBack: len %d", copied.Len())
+	}
+}
+
+// TaskQueue 链表中的值和其他字段引用同一元素
+type TaskQueue struct {
+	Pending *list.List
+	Current *list.Element
+}
+
+type queuedTask struct {
+	Name string
+	Args []string
+}
+
+func TestCopyListNested(t *testing.T) {
+	q := TaskQueue{Pending: list.New()}
+	q.Pending.PushBack(&queuedTask{Name: "a", Args: []string{"x"}})
+	q.Current = q.Pending.PushBack(&queuedTask{Name: "b"})
+
+	copied := Copy(q)
+	first := copied.Pending.Front().Value.(*queuedTask)
+	if first == q.Pending.Front().Value.(*queuedTask) || first.Name != "a" {
+		t.Errorf("list values should be deep copied: %+v", first)
+	}
+	first.Args[0] = "changed"
+	if q.Pending.Front().Value.(*queuedTask).Args[0] != "x" {
+		t.Error("modifying copied value affected the original")
+	}
+	if copied.Current != copied.Pending.Back() {
+		t.Error("Current should point to the copied element")
+	}
+}
+
+func TestCopyRing(t *testing.T) {
+	original := ring.New(4)
+	for i := 0; i < 4; i++ {
+		original.Value = i
+		original = original.Next()
+	}
+
+	copied := Copy(original)
+	original.Value = 100
+
+	if copied.Len() != 4 {
+		t.Fatalf("Len = %d, want 4", copied.Len())
+	}
+	var got []any
+	copied.Do(func(v any) { got = append(got, v) })
+	if want := []any{0, 1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ring values = %v, want %v", got, want)
+	}
+	if copied.Prev().Next() != copied || copied.Move(4) != copied {
+		t.Error("copied ring is not correctly linked")
+	}
+}
+
+// ringHolder 两个字段引用同一环中的不同元素
+type ringHolder struct {
+	Head, Tail *ring.Ring
+}
+
+func TestCopyRingSharedElements(t *testing.T) {
+	r := ring.New(3)
+	h := ringHolder{Head: r, Tail: r.Prev()}
+	copied := Copy(h)
+	if copied.Head == r || copied.Tail != copied.Head.Prev() {
+		t.Error("Tail should point into the copied ring")
+	}
+}
+
+// minHeap 用于 container/heap 的小顶堆
+type minHeap []int
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h minHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *minHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// 堆化的切片按普通切片拷贝，副本仍满足堆性质
+func TestCopyHeap(t *testing.T) {
+	original := &minHeap{5, 2, 8, 1, 9}
+	heap.Init(original)
+
+	copied := Copy(original)
+	var popped []int
+	for copied.Len() > 0 {
+		popped = append(popped, heap.Pop(copied).(int))
+	}
+	if want := []int{1, 2, 5, 8, 9}; !reflect.DeepEqual(popped, want) {
+		t.Errorf("popped = %v, want %v", popped, want)
+	}
+	if original.Len() != 5 || (*original)[0] != 1 {
+		t.Errorf("original heap modified: %v", *original)
+	}
+}
@@ -3,6 +3,7 @@ package deepcopy
 import (
 	"reflect"
 	"runtime"
+	"sync"
 	"testing"
 )
 
@@ -318,6 +319,27 @@ func TestCopyRecursive_CustomCopy(t *testing.T) {
 	}
 }
 
+// 切片元素可寻址，只有指针接收者的 DeepCopy 也逐个调用
+func TestCopyRecursive_SlicePtrReceiverDeepCopy(t *testing.T) {
+	original := []CustomCopyPtrStruct{{Value: 1}, {Value: 2}}
+	want := []CustomCopyPtrStruct{{Value: 201}, {Value: 202}}
+	if got := Copy(original); !reflect.DeepEqual(got, want) {
+		t.Errorf("Copy = %v, want %v", got, want)
+	}
+
+	// 经由 reflect.ValueOf 进入（外层值不可寻址）时同样生效
+	holder := struct{ Items []CustomCopyPtrStruct }{Items: original}
+	originalVal := reflect.ValueOf(holder)
+	cpy := reflect.New(originalVal.Type()).Elem()
+	copyRecursive(originalVal, cpy, make(map[uintptr]reflect.Value))
+	if got := cpy.Field(0).Interface(); !reflect.DeepEqual(got, want) {
+		t.Errorf("copyRecursive = %v, want %v", got, want)
+	}
+	if original[0].Value != 1 {
+		t.Error("original should be unchanged")
+	}
+}
+
 // 7. 内存使用分析
 func TestCopyRecursive_MemoryUsage(t *testing.T) {
 	// 创建一个大的结构体
@@ -405,3 +427,213 @@ func TestCopyRecursive_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+// 嵌入锁的结构体
+type SafeCounter struct {
+	sync.Mutex
+	Data map[string]int
+}
+
+type SafeCache struct {
+	sync.RWMutex
+	Items []string
+}
+
+// 以命名字段持有锁的结构体
+type GuardedCounter struct {
+	mu    sync.Mutex
+	Count map[string]int
+}
+
+// 9. 嵌入锁测试
+func TestCopyRecursive_EmbeddedMutex(t *testing.T) {
+	analysis := AnalyzeType(SafeCounter{})
+	if len(analysis.MutexFieldIndices) != 1 || analysis.MutexFieldIndices[0] != 0 {
+		t.Errorf("MutexFieldIndices: got %v, want [0]", analysis.MutexFieldIndices)
+	}
+
+	counter := &SafeCounter{Data: map[string]int{"a": 1}}
+	counter.Lock()
+	defer counter.Unlock()
+
+	copiedCounter := Copy(counter)
+	if !copiedCounter.TryLock() {
+		t.Error("copied Mutex should be unlocked")
+	}
+	if copiedCounter.Data["a"] != 1 {
+		t.Errorf("Data: got %v, want map[a:1]", copiedCounter.Data)
+	}
+
+	cache := &SafeCache{Items: []string{"x"}}
+	cache.RLock()
+	defer cache.RUnlock()
+
+	copiedCache := Copy(cache)
+	if !copiedCache.TryLock() {
+		t.Error("copied RWMutex should be unlocked")
+	}
+
+	// 拷贝期间源值的锁一直被持有，副本的锁仍可获取，源值的锁不受影响
+	guarded := &GuardedCounter{Count: map[string]int{"a": 1}}
+	guarded.mu.Lock()
+	defer guarded.mu.Unlock()
+
+	copiedGuarded := Copy(guarded)
+	if !copiedGuarded.mu.TryLock() {
+		t.Error("copied mu should be unlocked")
+	}
+	copiedGuarded.mu.Unlock()
+	if guarded.mu.TryLock() {
+		t.Error("source mu should stay locked")
+	}
+	if copiedGuarded.Count["a"] != 1 {
+		t.Errorf("Count: got %v, want map[a:1]", copiedGuarded.Count)
+	}
+}
+
+// 包含异构接口元素的容器
+type HeteroContainer struct {
+	Name  string
+	Items []interface{}
+}
+
+// 10. 异构接口切片中的共享指针与循环引用
+func TestCopyRecursive_HeterogeneousInterfaceCycles(t *testing.T) {
+	shared := &CircularStruct{Name: "shared"}
+	container := &HeteroContainer{Name: "root"}
+	container.Items = []interface{}{
+		42,
+		"text",
+		shared,
+		shared,
+		container,
+		map[string]interface{}{"back": container},
+	}
+	// 切片经由接口引用自身
+	container.Items = append(container.Items, nil)
+	container.Items[len(container.Items)-1] = container.Items
+
+	copied := Copy(container)
+
+	if copied == container {
+		t.Fatal("copy should be a new pointer")
+	}
+	if copied.Items[0] != 42 || copied.Items[1] != "text" {
+		t.Errorf("scalars: got %v, %v", copied.Items[0], copied.Items[1])
+	}
+
+	s1 := copied.Items[2].(*CircularStruct)
+	s2 := copied.Items[3].(*CircularStruct)
+	if s1 != s2 {
+		t.Error("shared pointer should be deduplicated across interface elements")
+	}
+	if s1 == shared {
+		t.Error("shared pointer should be copied")
+	}
+
+	if copied.Items[4].(*HeteroContainer) != copied {
+		t.Error("back-reference should point to the copied container")
+	}
+	if copied.Items[5].(map[string]interface{})["back"].(*HeteroContainer) != copied {
+		t.Error("back-reference inside map should point to the copied container")
+	}
+
+	self := copied.Items[6].([]interface{})
+	if &self[0] != &copied.Items[0] {
+		t.Error("self-referencing slice should point to the copied slice")
+	}
+
+	// 映射经由接口引用自身
+	m := map[string]interface{}{"n": 1}
+	m["self"] = m
+	copiedMap := Copy(m)
+	if reflect.ValueOf(copiedMap["self"]).Pointer() != reflect.ValueOf(copiedMap).Pointer() {
+		t.Error("self-referencing map should point to the copied map")
+	}
+	if reflect.ValueOf(copiedMap).Pointer() == reflect.ValueOf(m).Pointer() {
+		t.Error("map should be copied")
+	}
+}
+
+// CompositeEnvelope 的 Data 经由 map -> slice -> 指针引用自身
+type CompositeEnvelope struct {
+	Name string
+	Data any
+}
+
+// 11. 接口 -> 映射 -> 切片 -> 指针组成的环
+func TestCopyRecursive_InterfaceMapSliceCycle(t *testing.T) {
+	root := &CompositeEnvelope{Name: "root"}
+	items := []any{"x", root}
+	data := map[string]any{"items": items}
+	items = append(items, data) // 切片同时经由接口指回外层映射
+	data["items"] = items
+	root.Data = data
+
+	for name, copyFn := range map[string]func(*CompositeEnvelope) (*CompositeEnvelope, error){
+		"Copy":  func(v *CompositeEnvelope) (*CompositeEnvelope, error) { return Copy(v), nil },
+		"CopyE": func(v *CompositeEnvelope) (*CompositeEnvelope, error) { return CopyE(v) },
+	} {
+		copied, err := copyFn(root)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if copied == root {
+			t.Fatalf("%s: root should be copied", name)
+		}
+
+		copiedData := copied.Data.(map[string]any)
+		copiedItems := copiedData["items"].([]any)
+		if back := copiedItems[1].(*CompositeEnvelope); back != copied {
+			t.Errorf("%s: nested back-pointer should point at the copied root", name)
+		}
+		if reflect.ValueOf(copiedItems[2]).Pointer() != reflect.ValueOf(copiedData).Pointer() {
+			t.Errorf("%s: slice should point back at the copied map", name)
+		}
+		if reflect.ValueOf(copiedData).Pointer() == reflect.ValueOf(data).Pointer() ||
+			&copiedItems[0] == &items[0] {
+			t.Errorf("%s: map and slice should be copied", name)
+		}
+	}
+}
+
+func TestExportedFieldIndices(t *testing.T) {
+	analysis := AnalyzeType(TestStruct{})
+	if !reflect.DeepEqual(analysis.ExportedFieldIndices, []int{0, 1, 2}) {
+		t.Errorf("ExportedFieldIndices = %v, want [0 1 2]", analysis.ExportedFieldIndices)
+	}
+
+	// 未导出字段仍然被跳过
+	copied := Copy([]TestStruct{{Int: 1, String: "a", unexported: "secret"}})
+	if copied[0].Int != 1 || copied[0].String != "a" || copied[0].unexported != "" {
+		t.Errorf("unexpected copy %+v", copied[0])
+	}
+}
+
+// resetTarget 用于验证分析结果重置的类型
+type resetTarget struct {
+	Items []int
+}
+
+func TestTypedCopyManagerReset(t *testing.T) {
+	original := resetTarget{Items: []int{1, 2}}
+	manager := getTypedManager[resetTarget]()
+
+	// 预热后模拟分析结果过期：被错误地标记为只包含值类型，拷贝会共享切片
+	stale := *manager.getOrAnalyzeType()
+	stale.IsOnlyValues = true
+	manager.analysis.Store(&stale)
+	if copied := Copy(original); &copied.Items[0] != &original.Items[0] {
+		t.Fatal("stale analysis should be used before Reset")
+	}
+
+	ResetTypeCache[resetTarget]()
+
+	copied := Copy(original)
+	if &copied.Items[0] == &original.Items[0] {
+		t.Error("Copy should re-analyze the type after Reset")
+	}
+	if getTypedManager[resetTarget]().getOrAnalyzeType().IsOnlyValues {
+		t.Error("re-analysis should report reference fields")
+	}
+}
@@ -1,8 +1,19 @@
 package deepcopy
 
 import (
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/netip"
+	"os"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -11,24 +22,75 @@ type Copier[T any] interface {
 	DeepCopy() T
 }
 
+// FallibleCopier 拷贝可能失败的自定义拷贝接口，返回的错误由 CopyE 以 *CopierError 返回，Copy 中以其 panic。
+// 一个类型只能有一个 DeepCopy 方法，两种签名不会同时出现；实现了 CycleAwareCopier 时以 DeepCopyWith 为准
+type FallibleCopier[T any] interface {
+	DeepCopy() (T, error)
+}
+
 // DeepCopyManager 深拷贝管理器，提供类型分析和深拷贝功能
 // 使用缓存机制优化性能，避免重复的反射分析
 type DeepCopyManager struct {
 	// 类型分析结果缓存，key: reflect.Type, value: *TypeAnalysisResult
 	analysisCache sync.Map
+	lru           *analysisLRU // 设置了 WithCacheSize 时代替 analysisCache
+
+	disableBuiltins bool                             // 是否关闭内置的特殊处理
+	tagKey          string                           // 结构体标签名
+	logger          *slog.Logger                     // 警告输出
+	customCopiers   map[reflect.Type]CopyFunc        // 按类型注册的自定义拷贝函数
+	ifaceCopiers    []interfaceCopier                // 按接口注册的自定义拷贝函数，按注册顺序匹配
+	postCopyHooks   map[reflect.Type][]*postCopyHook // 按类型注册的拷贝后钩子
+	generation      atomic.Uint64                    // 配置的版本，修改配置后递增，之前缓存的分析结果随之失效
+	shortcutOff     atomic.Bool                      // 为基础类型或 JSON 值的类型注册了自定义拷贝方式，Copy 不能绕过管理器直接处理这些类型
+
+	// CopyWithMapping 编译后的字段映射，key: fieldMappingKey, value: *fieldMapping，条目数超过 maxFieldMappings 时清空
+	fieldMappings     sync.Map
+	fieldMappingCount atomic.Int64
 }
 
 // TypeAnalysisResult 类型分析结果，包含所有必要的信息
 type TypeAnalysisResult struct {
-	IsOnlyValues  bool                           // 是否只包含值类型
-	ContainsPtr   bool                           // 是否包含指针
-	ContainsSlice bool                           // 是否包含切片
-	ContainsMap   bool                           // 是否包含映射
-	ContainsChan  bool                           // 是否包含通道
-	ContainsFunc  bool                           // 是否包含函数
-	ContainsIface bool                           // 是否包含接口
-	FieldAnalysis map[string]*TypeAnalysisResult // 结构体字段分析（仅当类型为结构体时）
-	TypeName      string                         // 类型名称
+	IsOnlyValues              bool                           // 是否只包含值类型
+	ContainsPtr               bool                           // 是否包含指针
+	ContainsSlice             bool                           // 是否包含切片
+	ContainsMap               bool                           // 是否包含映射
+	ContainsChan              bool                           // 是否包含通道
+	ContainsFunc              bool                           // 是否包含函数
+	ContainsIface             bool                           // 是否包含接口
+	ContainsUnsafePointer     bool                           // 是否包含 unsafe.Pointer
+	ContainsIOResource        bool                           // 是否包含实现了 io.Reader、io.Writer 或 io.Closer 的值（如网络连接、文件）
+	ExportedFieldIndices      []int                          // 导出字段的下标，拷贝时只遍历这些字段（不含脱敏字段）
+	RedactedFieldIndices      []int                          // 带 redact 标签的导出字段下标，副本中为零值
+	MutexFieldIndices         []int                          // 匿名嵌入的 sync.Mutex / sync.RWMutex 字段下标
+	ImplementsTextMarshaler   bool                           // 类型（或其指针）是否实现 encoding.TextMarshaler
+	ImplementsBinaryMarshaler bool                           // 类型（或其指针）是否实现 encoding.BinaryMarshaler
+	HasDeepCopyMethod         bool                           // 类型的方法集中是否有 DeepCopy 方法（实现 Copier 或 CycleAwareCopier）
+	HasCycles                 bool                           // 值中是否可能存在循环引用（类型递归引用自身或包含接口）
+	ChanPaths                 []string                       // 包含通道的字段路径，如 Workers[].Done（仅 ContainsChan 为 true 时记录）
+	FieldAnalysis             map[string]*TypeAnalysisResult // 结构体字段分析（仅当类型为结构体时）
+	TypeName                  string                         // 类型名称
+
+	hasCustomCopier bool         // 管理器中为该类型注册了自定义拷贝函数
+	ifaceCopier     CopyFunc     // 类型实现了注册过拷贝函数的接口时为该函数（没有按类型注册的函数时使用）
+	ptrDeepCopy     bool         // 只有指向该类型的指针有 DeepCopy 方法（指针接收者），切片元素通过地址调用
+	redactFastPath  bool         // 除脱敏字段外只包含值类型，可以整体复制后清零脱敏字段
+	lockFields      []int        // 带 lock 标签的锁字段下标，拷贝其余字段期间持有
+	opaque          bool         // 结构体只有未导出字段，且没有 DeepCopy 方法、自定义拷贝函数或序列化接口，逐字段拷贝只能得到零值
+	viaMarshaler    bool         // 实现了序列化接口且包含未导出字段，逐字段拷贝会丢失未导出的状态，通过序列化往返拷贝
+	whitelists      *sync.Map    // 结构体在白名单模式下拷贝的字段下标，map[string][]int，按标签名缓存
+	complexity      float64      // 拷贝代价估算，见 CopyComplexity
+	funcWarning     *sync.Once   // 包含函数的结构体只输出一次共享函数值的警告
+	ioWarning       *sync.Once   // 包含 io 资源的结构体只输出一次共享底层资源的警告
+	unsafeWarning   *sync.Once   // unsafe.Pointer 类型只输出一次原样复制地址的警告
+	isIOResource    bool         // 类型本身（或其指针）实现了 io.Reader、io.Writer 或 io.Closer
+	valueElemKind   reflect.Kind // 切片的元素只包含值类型时为元素的种类，Copy 对基础类型的元素不经反射直接复制
+	generation      uint64       // 分析时管理器配置的版本
+}
+
+// useDeepCopy 入口处是否调用类型自身的 DeepCopy 方法（注册了自定义拷贝函数时优先使用后者）
+func (r *TypeAnalysisResult) useDeepCopy() bool {
+	return r.HasDeepCopyMethod && !r.hasCustomCopier
 }
 
 // BusinessCopyInfo 业务拷贝信息，基于配置 key 缓存的优化信息
@@ -41,11 +103,56 @@ type BusinessCopyInfo struct {
 
 // TypedCopyManager 泛型层面的拷贝管理器，为每个具体类型缓存分析结果
 type TypedCopyManager[T any] struct {
-	analysis *TypeAnalysisResult // 类型分析结果
-	rtype    reflect.Type        // 反射类型信息
-	once     sync.Once           // 确保只分析一次
+	analysis atomic.Pointer[TypeAnalysisResult] // 类型分析结果，为 nil 时在下次使用时分析
+	rtype    reflect.Type                       // 反射类型信息
+}
+
+// 需要在副本中直接共享的反射类型
+var (
+	reflectTypeType  = reflect.TypeOf((*reflect.Type)(nil)).Elem()
+	reflectValueType = reflect.TypeOf(reflect.Value{})
+)
+
+// 序列化接口类型，实现了这些接口的类型通过序列化往返拷贝
+var (
+	textMarshalerType     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// immutableValueTypes 不可变的值类型：只有未导出字段，内部引用的数据（时区、IPv6 zone）不会被修改，
+// 按值复制即可得到独立的副本，类型分析中视为只包含值类型
+var immutableValueTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):      true,
+	reflect.TypeOf(netip.Addr{}):     true,
+	reflect.TypeOf(netip.AddrPort{}): true,
+	reflect.TypeOf(netip.Prefix{}):   true,
 }
 
+// io 资源接口类型，实现了这些接口的值按 WithIOResourcePolicy 处理
+var (
+	ioReaderType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	ioWriterType = reflect.TypeOf((*io.Writer)(nil)).Elem()
+	ioCloserType = reflect.TypeOf((*io.Closer)(nil)).Elem()
+)
+
+// 默认共享而不复制的句柄类型，见 isHandleType
+var (
+	osFileType   = reflect.TypeOf((*os.File)(nil))
+	netConnType  = reflect.TypeOf((*net.Conn)(nil)).Elem()
+	tcpConnType  = reflect.TypeOf((*net.TCPConn)(nil))
+	udpConnType  = reflect.TypeOf((*net.UDPConn)(nil))
+	unixConnType = reflect.TypeOf((*net.UnixConn)(nil))
+	ipConnType   = reflect.TypeOf((*net.IPConn)(nil))
+)
+
+// 嵌入时需要在副本中重置的锁类型
+var (
+	mutexType   = reflect.TypeOf(sync.Mutex{})
+	rwMutexType = reflect.TypeOf(sync.RWMutex{})
+)
+
 // 全局默认管理器实例
 var defaultManager = NewDeepCopyManager()
 
@@ -56,23 +163,18 @@ var businessCopyCache sync.Map // map[string]*BusinessCopyInfo
 var typedManagers sync.Map // map[reflect.Type]*TypedCopyManager[any]
 
 // NewDeepCopyManager 创建新的深拷贝管理器
-func NewDeepCopyManager() *DeepCopyManager {
-	return &DeepCopyManager{}
+func NewDeepCopyManager(opts ...ManagerOption) *DeepCopyManager {
+	m := &DeepCopyManager{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 // getTypedManager 获取或创建特定类型的管理器
 func getTypedManager[T any]() *TypedCopyManager[T] {
-	var zero T
-	rtype := reflect.TypeOf(zero)
-
-	// 处理 nil 类型的特殊情况
-	if rtype == nil {
-		// 为 nil 类型创建一个特殊的管理器
-		manager := &TypedCopyManager[T]{
-			rtype: nil,
-		}
-		return manager
-	}
+	// 使用静态类型 T：T 为接口类型时 reflect.TypeOf(zero) 为 nil，需要通过指针取得接口类型本身
+	rtype := reflect.TypeOf((*T)(nil)).Elem()
 
 	// 尝试从缓存获取
 	if cached, ok := typedManagers.Load(rtype); ok {
@@ -90,25 +192,33 @@ func getTypedManager[T any]() *TypedCopyManager[T] {
 	return manager
 }
 
-// getOrAnalyzeType 获取或分析类型结果（使用 sync.Once 确保只分析一次）
+// getOrAnalyzeType 获取或分析类型结果，结果缓存在原子指针中
+// 并发首次调用时可能重复分析，但结果相同，不影响正确性
 func (tm *TypedCopyManager[T]) getOrAnalyzeType() *TypeAnalysisResult {
-	tm.once.Do(func() {
-		// 处理 nil 类型的特殊情况
-		if tm.rtype == nil {
-			tm.analysis = &TypeAnalysisResult{
-				TypeName:     "nil",
-				IsOnlyValues: true,
-			}
-			return
-		}
+	if analysis := tm.analysis.Load(); analysis != nil && analysis.generation == defaultManager.generation.Load() {
+		return analysis
+	}
 
-		// 分析类型
-		tm.analysis = defaultManager.getOrAnalyzeType(tm.rtype)
-	})
-	return tm.analysis
+	analysis := defaultManager.getOrAnalyzeType(tm.rtype)
+	tm.analysis.Store(analysis)
+	return analysis
+}
+
+// Reset 清除缓存的分析结果，下次使用时重新分析（用于测试中类型相关配置发生变化的场景）
+// 同时移除全局缓存中该类型的分析结果、字段映射和管理器，嵌套类型的分析结果不受影响
+func (tm *TypedCopyManager[T]) Reset() {
+	tm.analysis.Store(nil)
+	defaultManager.forgetAnalysis(tm.rtype)
+	defaultManager.forgetFieldMappings(tm.rtype)
+	typedManagers.Delete(tm.rtype)
 }
 
-// hasDeepCopyMethod 检查值是否有 DeepCopy 方法
+// ResetTypeCache 清除类型 T 缓存的分析结果，下次拷贝时重新分析
+func ResetTypeCache[T any]() {
+	getTypedManager[T]().Reset()
+}
+
+// hasDeepCopyMethod 检查值是否有 DeepCopy 方法，同时有 DeepCopyWith 方法时以后者为准，返回 false
 func hasDeepCopyMethod(v reflect.Value) (reflect.Method, bool) {
 	if !v.IsValid() {
 		return reflect.Method{}, false
@@ -116,9 +226,10 @@ func hasDeepCopyMethod(v reflect.Value) (reflect.Method, bool) {
 
 	method, found := v.Type().MethodByName("DeepCopy")
 	if found && method.Func.IsValid() {
-		// 检查方法签名：应该没有参数（除了接收者）且有一个返回值
+		// 检查方法签名：应该没有参数（除了接收者），返回 T 或 (T, error)
 		methodType := method.Type
-		if methodType.NumIn() == 1 && methodType.NumOut() == 1 {
+		if isDeepCopySignature(methodType) && !isPromotedMethod(v.Type(), methodType.Out(0), "DeepCopy") &&
+			!typeHasDeepCopyWithMethod(v.Type()) {
 			return method, true
 		}
 	}
@@ -126,19 +237,223 @@ func hasDeepCopyMethod(v reflect.Value) (reflect.Method, bool) {
 	return reflect.Method{}, false
 }
 
-// callDeepCopy 调用 DeepCopy 方法
-func callDeepCopy(v reflect.Value, method reflect.Method) reflect.Value {
+// typeHasDeepCopyMethod 检查类型（非接口）的方法集中是否有 DeepCopy 或 DeepCopyWith 方法
+func typeHasDeepCopyMethod(t reflect.Type) bool {
+	if typeHasDeepCopyWithMethod(t) {
+		return true
+	}
+	method, found := t.MethodByName("DeepCopy")
+	return found && isDeepCopySignature(method.Type) && !isPromotedMethod(t, method.Type.Out(0), "DeepCopy")
+}
+
+// isDeepCopySignature 方法类型（包括接收者）是否为 DeepCopy() T 或 DeepCopy() (T, error)
+func isDeepCopySignature(methodType reflect.Type) bool {
+	return methodType.NumIn() == 1 &&
+		(methodType.NumOut() == 1 || methodType.NumOut() == 2 && methodType.Out(1) == errorType)
+}
+
+// isPromotedMethod 判断返回 out 的拷贝方法 name（DeepCopy 或 DeepCopyWith）是否从嵌入字段（包括嵌入接口）提升而来：
+// 提升的方法拷贝的只是嵌入的值，不能当作外层结构体的拷贝方法，外层结构体应逐字段拷贝
+func isPromotedMethod(t, out reflect.Type, name string) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || out == t || out == reflect.PointerTo(t) {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		if _, ok := reflect.PointerTo(field.Type).MethodByName(name); ok {
+			return true
+		}
+		if _, ok := field.Type.MethodByName(name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// errorType error 接口的类型，用于识别 DeepCopy() (T, error)
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// CopierError DeepCopy() (T, error) 方法返回错误时由 CopyE 等返回错误的函数返回，Copy 中以其 panic
+type CopierError struct {
+	Type reflect.Type // DeepCopy 方法的接收者类型
+	Path string       // 该值在源值中的位置，如 Items[2].Inner，根值或不记录路径的 Copy 中为空
+	Err  error        // 方法返回的错误
+}
+
+func (e *CopierError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("deepcopy: %s.DeepCopy failed: %v", e.Type, e.Err)
+	}
+	return fmt.Sprintf("deepcopy: %s.DeepCopy failed at %s: %v", e.Type, e.Path, e.Err)
+}
+
+func (e *CopierError) Unwrap() error {
+	return e.Err
+}
+
+// callDeepCopy 调用 DeepCopy 方法，方法返回的错误包装为 *CopierError
+func callDeepCopy(v reflect.Value, method reflect.Method) (reflect.Value, *CopierError) {
 	results := method.Func.Call([]reflect.Value{v})
+	if len(results) == 2 && !results[1].IsNil() {
+		return reflect.Value{}, &CopierError{Type: v.Type(), Err: results[1].Interface().(error)}
+	}
 	if len(results) > 0 {
-		return results[0]
+		return results[0], nil
 	}
-	return reflect.Value{}
+	return reflect.Value{}, nil
+}
+
+// callDeepCopy 在遍历中调用 DeepCopy 方法，方法返回错误时以带路径的 *CopierError panic，由 run 转为返回的错误
+func (s *copyState) callDeepCopy(v reflect.Value, method reflect.Method) reflect.Value {
+	s.report.customCopy()
+	result, err := callDeepCopy(v, method)
+	if err != nil {
+		err.Path = s.pathString()
+		panic(err)
+	}
+	return result
+}
+
+// hasPtrDeepCopyMethod 非指针类型 t 本身没有 DeepCopy 方法，但 *t 有返回 t 或 *t 的 DeepCopy 方法（指针接收者）
+func hasPtrDeepCopyMethod(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return false
+	}
+	ptrType := reflect.PointerTo(t)
+	if typeHasDeepCopyWithMethod(ptrType) {
+		return false
+	}
+	method, found := ptrType.MethodByName("DeepCopy")
+	return found && isDeepCopySignature(method.Type) &&
+		(method.Type.Out(0) == t || method.Type.Out(0) == ptrType)
+}
+
+// deepCopyMethod 同 hasDeepCopyMethod，WithIgnoreCustomCopiers 时总是返回 false
+func (s *copyState) deepCopyMethod(v reflect.Value) (reflect.Method, bool) {
+	if s.cfg.ignoreMethods {
+		return reflect.Method{}, false
+	}
+	return hasDeepCopyMethod(v)
+}
+
+// deepCopyWithMethod 同 hasDeepCopyWithMethod，WithIgnoreCustomCopiers 时总是返回 false
+func (s *copyState) deepCopyWithMethod(v reflect.Value) (reflect.Method, bool) {
+	if s.cfg.ignoreMethods {
+		return reflect.Method{}, false
+	}
+	return hasDeepCopyWithMethod(v)
+}
+
+// copyViaAddr 通过可寻址的 original 的地址调用指针接收者的 DeepCopy 方法，结果类型不符时返回 false
+func (s *copyState) copyViaAddr(original, cpy reflect.Value) bool {
+	addr := original.Addr()
+	method, found := s.deepCopyMethod(addr)
+	if !found {
+		return false
+	}
+	result := s.callDeepCopy(addr, method)
+	switch {
+	case !result.IsValid():
+		return false
+	case result.Type() == original.Type():
+		cpy.Set(result)
+	case result.Type() == addr.Type() && !result.IsNil():
+		cpy.Set(result.Elem())
+	default:
+		return false
+	}
+	return true
+}
+
+// tryDeepCopy 在入口处调用值自身的 DeepCopy 方法，并把结果转换为源值的类型，方法返回错误时以 *CopierError panic
+func tryDeepCopy(srcVal reflect.Value) (reflect.Value, bool) {
+	result, ok, err := tryDeepCopyE(srcVal)
+	if err != nil {
+		panic(err)
+	}
+	return result, ok
+}
+
+// tryDeepCopyE 同 tryDeepCopy，方法返回的错误作为返回值
+// 指针的方法集包含值接收者的 DeepCopy，此时返回的是值，需要包装成新指针
+func tryDeepCopyE(srcVal reflect.Value) (reflect.Value, bool, error) {
+	if srcVal.Kind() == reflect.Ptr && srcVal.IsNil() {
+		return reflect.Value{}, false, nil
+	}
+
+	method, found := hasDeepCopyMethod(srcVal)
+	if !found {
+		return reflect.Value{}, false, nil
+	}
+
+	result, err := callDeepCopy(srcVal, method)
+	switch {
+	case err != nil:
+		return reflect.Value{}, false, err
+	case !result.IsValid():
+		return reflect.Value{}, false, nil
+	case result.Type() == srcVal.Type():
+		return result, true, nil
+	case srcVal.Kind() == reflect.Ptr && result.Type() == srcVal.Type().Elem():
+		newPtr := reflect.New(result.Type())
+		newPtr.Elem().Set(result)
+		return newPtr, true, nil
+	}
+	return reflect.Value{}, false, nil
 }
 
 // Copy 创建任意值的深拷贝并返回副本
 // 如果类型实现了 DeepCopy 方法，将使用其自定义的拷贝方法
 // 使用类型分析优化：对于只包含值类型的数据直接返回，避免昂贵的深拷贝操作
-func Copy[T any](src T) T {
+// 注意：参数和返回值都按值传递，对于很大的值类型（如 [1 << 20]int）会复制两次，
+// 这种情况下应传入指针，副本中只复制一次
+// 拷贝中的 panic（例如 DeepCopy 方法中的）默认向外传播，通过 SetRecoverMode 可改为调用钩子并返回零值
+func Copy[T any](src T) (result T) {
+	// 基础类型不可变，没有注册自定义拷贝方式时无需查找管理器，直接返回
+	if isBasicType[T]() && !defaultManager.shortcutOff.Load() && !fastPathDisabled.Load() {
+		return src
+	}
+	if onPanic := recoverHook.Load(); onPanic != nil {
+		defer recoverCopy(*onPanic, &result)
+	}
+	// 解码后的 JSON 值通过类型断言拷贝，不使用反射
+	if !defaultManager.shortcutOff.Load() {
+		if result, ok := copyJSONLike(src); ok {
+			return result
+		}
+	}
+
+	// 获取该类型的专用管理器
+	manager := getTypedManager[T]()
+
+	// T 为接口类型时没有静态类型信息，先按动态类型检查 DeepCopy 方法
+	if manager.rtype.Kind() == reflect.Interface {
+		if result, ok := tryDeepCopy(reflect.ValueOf(src)); ok {
+			return result.Interface().(T)
+		}
+	}
+
+	// 获取类型分析结果（只会分析一次）
+	analysis := manager.getOrAnalyzeType()
+
+	// 性能优化：如果只包含值类型，直接返回原值，不做任何反射调用
+	if analysis.IsOnlyValues && !fastPathDisabled.Load() {
+		return src
+	}
+
+	// 元素为基础类型的切片（如 []string、type Tags []string）直接复制元素，不经过反射
+	if analysis.valueElemKind != reflect.Invalid && !fastPathDisabled.Load() {
+		if result, ok := cloneValueSlice(src, analysis.valueElemKind); ok {
+			return result
+		}
+	}
+
 	// 处理零值情况
 	srcVal := reflect.ValueOf(src)
 	if !srcVal.IsValid() {
@@ -146,38 +461,150 @@ func Copy[T any](src T) T {
 		return zero
 	}
 
-	// 首先检查是否有 DeepCopy 方法
-	if method, found := hasDeepCopyMethod(srcVal); found {
-		result := callDeepCopy(srcVal, method)
-		if result.IsValid() {
+	// 分析时已记录类型是否有 DeepCopy 方法，避免每次都按名称查找
+	if analysis.useDeepCopy() {
+		if result, ok := tryDeepCopy(srcVal); ok {
 			return result.Interface().(T)
 		}
 	}
 
-	// 获取该类型的专用管理器
+	// 需要深拷贝的情况，使用反射方式
+	return copyToT[T](srcVal, newCopyState(&defaultCopyConfig))
+}
+
+// isBasicType T 是否恰好为 string、bool 或数值类型（不含命名类型），通过 *T 的类型判断，不会复制 src
+func isBasicType[T any]() bool {
+	switch any((*T)(nil)).(type) {
+	case *string, *bool, *int, *int8, *int16, *int32, *int64,
+		*uint, *uint8, *uint16, *uint32, *uint64, *uintptr,
+		*float32, *float64, *complex64, *complex128:
+		return true
+	}
+	return false
+}
+
+// overridesShortcutTypes 是否为 Copy 入口处直接处理的类型（基础类型、解码后的 JSON 值）
+// 注册了自定义拷贝函数（按类型或接口）或拷贝后钩子
+func (m *DeepCopyManager) overridesShortcutTypes() bool {
+	for t := range m.customCopiers {
+		if isShortcutType(t) {
+			return true
+		}
+	}
+	for t := range m.postCopyHooks {
+		if isShortcutType(t) {
+			return true
+		}
+	}
+	for _, c := range m.ifaceCopiers {
+		// 基础类型没有方法，只实现空接口
+		if c.iface.NumMethod() == 0 || jsonNumberType.Implements(c.iface) {
+			return true
+		}
+	}
+	return false
+}
+
+// isShortcutType t 是否为 isBasicType 判断的基础类型之一，或 copyJSONLike 处理的类型
+func isShortcutType(t reflect.Type) bool {
+	return (t.PkgPath() == "" && t.Name() != "" && t.Kind() != reflect.UnsafePointer) ||
+		t == jsonObjectType || t == jsonArrayType || t == jsonNumberType
+}
+
+// CopyOrDefault src 不为 nil 时返回 *src 的深拷贝，否则返回 defaultValue 的深拷贝
+// 返回的总是新副本，修改结果不会影响 defaultValue
+func CopyOrDefault[T any](src *T, defaultValue T) T {
+	if src == nil {
+		return Copy(defaultValue)
+	}
+	return Copy(*src)
+}
+
+// CopyOrZero src 不为 nil 时返回 *src 的深拷贝，否则返回零值
+func CopyOrZero[T any](src *T) T {
+	var zero T
+	return CopyOrDefault(src, zero)
+}
+
+// CloneNew 深拷贝 src 并返回指向副本的指针
+// 副本直接分配在堆上并就地填充，避免 p := Copy(v); use(&p) 写法中副本的额外复制
+func CloneNew[T any](src T) *T {
+	dst := new(T)
+	cloneInto(dst, &src)
+	return dst
+}
+
+// ClonePtr 深拷贝 *src 并返回指向新副本的指针，src 为 nil 时返回 nil
+func ClonePtr[T any](src *T) *T {
+	if src == nil {
+		return nil
+	}
+	dst := new(T)
+	cloneInto(dst, src)
+	return dst
+}
+
+// cloneInto 把 *src 深拷贝到新分配的 *dst，与 Copy 使用相同的快速路径和分析缓存
+func cloneInto[T any](dst, src *T) {
 	manager := getTypedManager[T]()
 
-	// 获取类型分析结果（只会分析一次）
+	// T 为接口类型时按动态类型拷贝，与 Copy 相同
+	if manager.rtype.Kind() == reflect.Interface {
+		*dst = Copy(*src)
+		return
+	}
+
 	analysis := manager.getOrAnalyzeType()
+	if analysis.IsOnlyValues && !fastPathDisabled.Load() {
+		*dst = *src
+		return
+	}
 
-	// 性能优化：如果只包含值类型，直接返回原值
-	if analysis.IsOnlyValues {
-		return src
+	// 通过指针取得源值，避免 reflect.ValueOf 对大结构体装箱
+	srcVal := reflect.ValueOf(src).Elem()
+	if analysis.useDeepCopy() {
+		if result, ok := tryDeepCopy(srcVal); ok {
+			reflect.ValueOf(dst).Elem().Set(result)
+			return
+		}
 	}
 
-	// 需要深拷贝的情况，使用反射方式
-	result := defaultManager.CopyValue(src)
-	return result.(T)
+	newCopyState(&defaultCopyConfig).copyRecursive(srcVal, reflect.ValueOf(dst).Elem())
+}
+
+// copyToT 把 srcVal 深拷贝为 T 类型的值
+// T 为具体类型时目标直接分配为 *T，避免 Interface() 装箱以及断言时对大结构体的二次拷贝
+func copyToT[T any](srcVal reflect.Value, state *copyState) T {
+	if srcVal.Type() == reflect.TypeOf((*T)(nil)).Elem() {
+		dst := new(T)
+		state.copyRecursive(srcVal, reflect.ValueOf(dst).Elem())
+		return *dst
+	}
+
+	// T 为接口类型时，只能按动态类型创建目标再装箱
+	cpy := reflect.New(srcVal.Type()).Elem()
+	state.copyRecursive(srcVal, cpy)
+	return cpy.Interface().(T)
 }
 
 // CopyWithKey 基于业务 key 的优化拷贝，避免重复反射调用
 // 这个函数的核心目的是缓存反射类型信息，减少每次调用时的反射开销
+// Copy 本身已按类型缓存分析结果，只需要按类型缓存时直接使用 Copy 即可；
+// CopyWithKey 按 key 缓存的条目不会被清除，key 应取自有限的常量集合
 func CopyWithKey[T any](src T, key string) T {
 	// 获取或创建业务拷贝信息
-	copyInfo := getOrCreateBusinessCopyInfo[T](key)
+	return copyWithInfo(src, getOrCreateBusinessCopyInfo[T](key))
+}
+
+// copyWithInfo 使用已初始化的业务拷贝信息进行深拷贝
+func copyWithInfo[T any](src T, copyInfo *BusinessCopyInfo) T {
+	// 缓存后默认管理器的配置发生了变化（例如注册了自定义拷贝函数），缓存的信息已过期
+	if copyInfo.analysisResult.generation != defaultManager.generation.Load() {
+		return Copy(src)
+	}
 
 	// 性能优化：如果只包含值类型，直接返回原值，完全避免反射
-	if copyInfo.IsOnlyValues {
+	if copyInfo.IsOnlyValues && !fastPathDisabled.Load() {
 		return src
 	}
 
@@ -188,20 +615,15 @@ func CopyWithKey[T any](src T, key string) T {
 		return zero
 	}
 
-	// 首先检查是否有自定义 DeepCopy 方法（这个检查很快，不影响缓存效果）
-	if method, found := hasDeepCopyMethod(srcVal); found {
-		result := callDeepCopy(srcVal, method)
-		if result.IsValid() {
+	// 检查是否有自定义 DeepCopy 方法（使用缓存的分析结果，T 为接口时按动态类型检查）
+	if copyInfo.rtype.Kind() == reflect.Interface || copyInfo.analysisResult.useDeepCopy() {
+		if result, ok := tryDeepCopy(srcVal); ok {
 			return result.Interface().(T)
 		}
 	}
 
 	// 使用缓存的类型信息进行深拷贝
-	cpy := reflect.New(srcVal.Type()).Elem()
-	visited := make(map[uintptr]reflect.Value)
-	copyRecursiveWithCache(srcVal, cpy, visited, copyInfo.analysisResult)
-
-	return cpy.Interface().(T)
+	return copyToT[T](srcVal, newCopyState(&defaultCopyConfig))
 }
 
 // AnalyzeType 使用默认管理器分析类型
@@ -209,6 +631,81 @@ func AnalyzeType[T any](src T) *TypeAnalysisResult {
 	return defaultManager.AnalyzeValue(src)
 }
 
+// MustBeCopyable 断言类型 T 不包含通道、函数和 unsafe.Pointer（这些值在副本中会被共享），否则 panic
+// 用于在包初始化时尽早发现问题：var _ = deepcopy.MustBeCopyable[Message]()
+// 接口字段的动态值在运行时才能确定，不在检查范围内
+func MustBeCopyable[T any]() bool {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	analysis := defaultManager.getOrAnalyzeType(t)
+
+	var reasons []string
+	if analysis.ContainsChan {
+		reasons = append(reasons, "chan")
+	}
+	if analysis.ContainsFunc {
+		reasons = append(reasons, "func")
+	}
+	if analysis.ContainsUnsafePointer {
+		reasons = append(reasons, "unsafe.Pointer")
+	}
+	if len(reasons) > 0 {
+		panic(fmt.Sprintf("deepcopy: %s is not copyable: contains %s", t, strings.Join(reasons, ", ")))
+	}
+	return true
+}
+
+// ErrContainsChan ValidateNoChannels 检查的类型包含通道时返回
+var ErrContainsChan = errors.New("deepcopy: type contains channels")
+
+// ValidateNoChannels 检查类型 T 的导出字段中（任意深度）没有通道，副本会与原始值共享通道，
+// 消息传递框架可在注册消息类型时调用。包含通道时返回 ErrContainsChan，错误中列出所有通道字段的路径。
+// 接口字段的动态值在运行时才能确定，不在检查范围内
+func ValidateNoChannels[T any]() error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	analysis := defaultManager.getOrAnalyzeType(t)
+	if !analysis.ContainsChan {
+		return nil
+	}
+	return fmt.Errorf("%w: %s at %s", ErrContainsChan, t, strings.Join(analysis.ChanPaths, ", "))
+}
+
+// chanPaths 收集类型中通道所在的路径，prefix 为当前路径，onPath 避免递归类型无限展开
+func chanPaths(t reflect.Type, prefix string, onPath map[reflect.Type]bool, paths []string) []string {
+	if onPath[t] {
+		return paths
+	}
+	onPath[t] = true
+	defer delete(onPath, t)
+
+	switch t.Kind() {
+	case reflect.Chan:
+		if prefix == "" {
+			prefix = "(root)"
+		}
+		paths = append(paths, prefix)
+	case reflect.Ptr:
+		paths = chanPaths(t.Elem(), prefix, onPath, paths)
+	case reflect.Slice, reflect.Array:
+		paths = chanPaths(t.Elem(), prefix+"[]", onPath, paths)
+	case reflect.Map:
+		paths = chanPaths(t.Key(), prefix+"[key]", onPath, paths)
+		paths = chanPaths(t.Elem(), prefix+"[]", onPath, paths)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			paths = chanPaths(field.Type, name, onPath, paths)
+		}
+	}
+	return paths
+}
+
 // CopyValue 执行深拷贝操作（非泛型方法）
 func (m *DeepCopyManager) CopyValue(src interface{}) interface{} {
 	// 获取源数据的反射值对象
@@ -223,14 +720,13 @@ func (m *DeepCopyManager) CopyValue(src interface{}) interface{} {
 	analysis := m.getOrAnalyzeType(srcVal.Type())
 
 	// 性能优化：如果只包含值类型，直接返回原值
-	if analysis.IsOnlyValues {
+	if analysis.IsOnlyValues && !fastPathDisabled.Load() {
 		return src
 	}
 
-	// 首先检查是否有 DeepCopy 方法
-	if method, found := hasDeepCopyMethod(srcVal); found {
-		result := callDeepCopy(srcVal, method)
-		if result.IsValid() {
+	// 检查是否有 DeepCopy 方法
+	if analysis.useDeepCopy() {
+		if result, ok := tryDeepCopy(srcVal); ok {
 			return result.Interface()
 		}
 	}
@@ -238,11 +734,10 @@ func (m *DeepCopyManager) CopyValue(src interface{}) interface{} {
 	// 创建目标反射值对象
 	cpy := reflect.New(srcVal.Type()).Elem()
 
-	// 创建访问记录映射，处理循环引用
-	visited := make(map[uintptr]reflect.Value)
-
-	// 执行深拷贝
-	copyRecursive(srcVal, cpy, visited)
+	// 执行深拷贝（访问记录用于处理循环引用），使用本管理器的分析结果和自定义拷贝函数
+	state := newCopyState(&defaultCopyConfig)
+	state.manager = m
+	state.copyRecursive(srcVal, cpy)
 
 	// 返回结果
 	return cpy.Interface()
@@ -264,15 +759,17 @@ func (m *DeepCopyManager) AnalyzeValue(src interface{}) *TypeAnalysisResult {
 // getOrAnalyzeType 获取或分析类型，使用缓存机制
 func (m *DeepCopyManager) getOrAnalyzeType(t reflect.Type) *TypeAnalysisResult {
 	// 尝试从缓存获取
-	if cached, ok := m.analysisCache.Load(t); ok {
-		return cached.(*TypeAnalysisResult)
+	if cached, ok := m.loadAnalysis(t); ok {
+		return cached
 	}
 
-	// 缓存未命中，进行分析
+	// 缓存未命中或已过期，进行分析（先读取版本，分析期间配置变化时结果按旧版本记录，下次重新分析）
+	generation := m.generation.Load()
 	result := m.analyzeTypeRecursive(t, make(map[reflect.Type]*TypeAnalysisResult))
+	result.generation = generation
 
 	// 存入缓存
-	m.analysisCache.Store(t, result)
+	m.storeAnalysis(t, result)
 
 	return result
 }
@@ -292,6 +789,13 @@ func (m *DeepCopyManager) analyzeTypeRecursive(t reflect.Type, visited map[refle
 	// 先放入visited，防止循环引用
 	visited[t] = result
 
+	// 不可变值类型按值复制即可
+	if !m.disableBuiltins && immutableValueTypes[t] {
+		result.IsOnlyValues = true
+		result.complexity = scalarCost
+		return result
+	}
+
 	// 根据类型进行分析
 	switch t.Kind() {
 	// 基础值类型
@@ -310,11 +814,14 @@ func (m *DeepCopyManager) analyzeTypeRecursive(t reflect.Type, visited map[refle
 		result.ContainsChan = elemResult.ContainsChan
 		result.ContainsFunc = elemResult.ContainsFunc
 		result.ContainsIface = elemResult.ContainsIface
+		result.ContainsUnsafePointer = elemResult.ContainsUnsafePointer
+		result.ContainsIOResource = elemResult.ContainsIOResource
 
 	// 结构体类型
 	case reflect.Struct:
 		result.IsOnlyValues = true // 假设是值类型，遇到引用类型时修改
 		result.FieldAnalysis = make(map[string]*TypeAnalysisResult)
+		result.whitelists = new(sync.Map)
 
 		// 检查是否有未导出字段，如果有则不能使用值拷贝优化
 		hasUnexportedFields := false
@@ -334,10 +841,28 @@ func (m *DeepCopyManager) analyzeTypeRecursive(t reflect.Type, visited map[refle
 		for i := 0; i < t.NumField(); i++ {
 			field := t.Field(i)
 
+			// 带 lock 标签的锁（通常是未导出字段）
+			if m.isLockField(field) {
+				result.lockFields = append(result.lockFields, i)
+			} else if m.hasTagOption(field, lockOption) {
+				m.warn("deepcopy: %s.%s is tagged %q but %s is not a lock, the tag is ignored", t, field.Name, lockOption, field.Type)
+			}
+
 			// 跳过未导出字段
 			if field.PkgPath != "" {
 				continue
 			}
+			// 脱敏字段不拷贝，也不影响结构体的分析结果
+			if m.isRedacted(field) {
+				result.RedactedFieldIndices = append(result.RedactedFieldIndices, i)
+				continue
+			}
+			result.ExportedFieldIndices = append(result.ExportedFieldIndices, i)
+
+			// 记录匿名嵌入的锁，副本中需要重置为未加锁状态
+			if field.Anonymous && (field.Type == mutexType || field.Type == rwMutexType) {
+				result.MutexFieldIndices = append(result.MutexFieldIndices, i)
+			}
 
 			// 分析字段类型
 			fieldResult := m.analyzeTypeRecursive(field.Type, visited)
@@ -365,6 +890,22 @@ func (m *DeepCopyManager) analyzeTypeRecursive(t reflect.Type, visited map[refle
 			if fieldResult.ContainsIface {
 				result.ContainsIface = true
 			}
+			if fieldResult.ContainsUnsafePointer {
+				result.ContainsUnsafePointer = true
+			}
+			if fieldResult.ContainsIOResource {
+				result.ContainsIOResource = true
+			}
+		}
+
+		// 存在脱敏字段时不能直接返回原值，但其余字段仍可整体复制
+		if len(result.RedactedFieldIndices) > 0 && result.IsOnlyValues {
+			result.redactFastPath = true
+			result.IsOnlyValues = false
+		}
+		// 需要加锁后再拷贝，不能直接返回原值
+		if len(result.lockFields) > 0 {
+			result.IsOnlyValues = false
 		}
 
 	// 引用类型
@@ -378,17 +919,24 @@ func (m *DeepCopyManager) analyzeTypeRecursive(t reflect.Type, visited map[refle
 		result.ContainsChan = elemResult.ContainsChan
 		result.ContainsFunc = elemResult.ContainsFunc
 		result.ContainsIface = elemResult.ContainsIface
+		result.ContainsUnsafePointer = elemResult.ContainsUnsafePointer
+		result.ContainsIOResource = elemResult.ContainsIOResource
 
 	case reflect.Slice:
 		result.IsOnlyValues = false
 		result.ContainsSlice = true
 		// 递归分析切片元素类型
 		elemResult := m.analyzeTypeRecursive(t.Elem(), visited)
+		if elemResult.IsOnlyValues {
+			result.valueElemKind = t.Elem().Kind()
+		}
 		result.ContainsPtr = elemResult.ContainsPtr
 		result.ContainsMap = elemResult.ContainsMap
 		result.ContainsChan = elemResult.ContainsChan
 		result.ContainsFunc = elemResult.ContainsFunc
 		result.ContainsIface = elemResult.ContainsIface
+		result.ContainsUnsafePointer = elemResult.ContainsUnsafePointer
+		result.ContainsIOResource = elemResult.ContainsIOResource
 
 	case reflect.Map:
 		result.IsOnlyValues = false
@@ -401,6 +949,8 @@ func (m *DeepCopyManager) analyzeTypeRecursive(t reflect.Type, visited map[refle
 		result.ContainsChan = keyResult.ContainsChan || valueResult.ContainsChan
 		result.ContainsFunc = keyResult.ContainsFunc || valueResult.ContainsFunc
 		result.ContainsIface = keyResult.ContainsIface || valueResult.ContainsIface
+		result.ContainsUnsafePointer = keyResult.ContainsUnsafePointer || valueResult.ContainsUnsafePointer
+		result.ContainsIOResource = keyResult.ContainsIOResource || valueResult.ContainsIOResource
 
 	case reflect.Chan:
 		result.IsOnlyValues = false
@@ -414,11 +964,78 @@ func (m *DeepCopyManager) analyzeTypeRecursive(t reflect.Type, visited map[refle
 		result.IsOnlyValues = false
 		result.ContainsIface = true
 
+	case reflect.UnsafePointer:
+		result.IsOnlyValues = false
+		result.ContainsUnsafePointer = true
+		result.unsafeWarning = new(sync.Once)
+
 	// 其他未知类型
 	default:
 		result.IsOnlyValues = false
 	}
 
+	if result.ContainsChan {
+		result.ChanPaths = chanPaths(t, "", make(map[reflect.Type]bool), nil)
+	}
+	result.HasCycles = m.typeMayCycle(t, make(map[reflect.Type]bool), make(map[reflect.Type]bool))
+	result.complexity = m.copyComplexity(t)
+	if t.Kind() == reflect.Struct && result.ContainsFunc {
+		result.funcWarning = new(sync.Once)
+	}
+	// io 资源不能按值整体复制，否则 WithIOResourcePolicy 的处理会被跳过
+	if isIOResourceType(t) {
+		result.isIOResource = true
+		result.ContainsIOResource = true
+		result.IsOnlyValues = false
+	}
+	if t.Kind() == reflect.Struct && result.ContainsIOResource {
+		result.ioWarning = new(sync.Once)
+	}
+
+	// 记录序列化接口的实现情况
+	if t.Kind() != reflect.Interface {
+		ptrType := reflect.PointerTo(t)
+		result.ImplementsTextMarshaler = ptrType.Implements(textMarshalerType)
+		result.ImplementsBinaryMarshaler = ptrType.Implements(binaryMarshalerType)
+	}
+	// 只有包含未导出字段的类型需要序列化往返，其余类型逐字段拷贝已能得到完整的副本
+	if result.ImplementsTextMarshaler || result.ImplementsBinaryMarshaler {
+		result.viaMarshaler = hasUnexportedFields(t, make(map[reflect.Type]bool))
+	}
+
+	// 自定义了 DeepCopy 的类型不能走快速路径，否则其拷贝逻辑会被跳过；
+	// 包含它的结构体、数组会通过字段/元素的分析结果一并标记
+	if t.Kind() != reflect.Interface && typeHasDeepCopyMethod(t) {
+		result.HasDeepCopyMethod = true
+		result.IsOnlyValues = false
+	}
+	// 只有指针接收者的 DeepCopy 时，切片元素需要逐个通过地址调用，不能整体复制
+	if !result.HasDeepCopyMethod && hasPtrDeepCopyMethod(t) {
+		result.ptrDeepCopy = true
+		result.IsOnlyValues = false
+	}
+	// 注册了自定义拷贝函数的类型同理
+	if t.Kind() != reflect.Interface {
+		result.ifaceCopier = m.interfaceCopierFor(t)
+	}
+	if m.customCopiers[t] != nil || result.ifaceCopier != nil {
+		result.hasCustomCopier = true
+		result.IsOnlyValues = false
+	}
+	// 注册了拷贝后钩子的类型需要逐个拷贝，才能找到每个副本
+	if m.postCopyHooks[t] != nil {
+		result.IsOnlyValues = false
+	}
+	// 切片本身自定义了拷贝方式时不能直接复制元素
+	if result.HasDeepCopyMethod || result.hasCustomCopier || m.postCopyHooks[t] != nil {
+		result.valueElemKind = reflect.Invalid
+	}
+	if t.Kind() == reflect.Struct && t.NumField() > 0 && len(result.ExportedFieldIndices) == 0 &&
+		len(result.RedactedFieldIndices) == 0 && !result.HasDeepCopyMethod && !result.hasCustomCopier &&
+		m.postCopyHooks[t] == nil && t != reflectValueType && (m.disableBuiltins || !result.viaMarshaler) {
+		result.opaque = true
+	}
+
 	return result
 }
 
@@ -430,8 +1047,7 @@ func getOrCreateBusinessCopyInfo[T any](key string) *BusinessCopyInfo {
 	}
 
 	// 创建新的业务拷贝信息
-	var zero T
-	rtype := reflect.TypeOf(zero)
+	rtype := reflect.TypeOf((*T)(nil)).Elem()
 
 	copyInfo := &BusinessCopyInfo{
 		rtype: rtype,
@@ -450,19 +1066,163 @@ func getOrCreateBusinessCopyInfo[T any](key string) *BusinessCopyInfo {
 
 // initializeCopyInfo 初始化拷贝信息
 func (info *BusinessCopyInfo) initializeCopyInfo() {
-	// 处理 nil 类型
-	if info.rtype == nil {
-		info.IsOnlyValues = true
-		return
-	}
-
 	// 分析类型
 	info.analysisResult = defaultManager.getOrAnalyzeType(info.rtype)
 	info.IsOnlyValues = info.analysisResult.IsOnlyValues
 }
 
-// copyRecursive 使用反射递归地复制值
+// copyState 单次拷贝过程中的状态：访问记录、拷贝配置以及遇到的错误
+type copyState struct {
+	visited     map[uintptr]reflect.Value // 已复制的指针，处理循环引用
+	refs        map[refKey]reflect.Value  // 已复制的切片和映射，处理经由接口形成的循环引用
+	cfg         *copyConfig               // 拷贝配置
+	manager     *DeepCopyManager          // 提供类型分析和自定义拷贝函数的管理器
+	err         error                     // 遍历过程中遇到的第一个错误
+	reuse       bool                      // 是否复用目标中已有的切片、映射存储（CopyInto）
+	reused      map[refKey]bool           // CopyInto 中已复用过的目标切片、映射
+	acyclic     bool                      // 类型不会形成循环引用，跳过已复制指针和切片、映射的记录（CopyNoCycles）
+	funcChecked bool                      // 是否已检查过是否需要警告共享的函数值，只在最外层的结构体检查
+	ioChecked   bool                      // 是否已检查过是否需要警告共享的 io 资源，同样只在最外层的结构体检查
+	// 以下用于在返回错误的入口中报告 panic 发生的位置
+	trackPath bool          // 是否记录当前路径
+	path      []pathSegment // 当前遍历到的路径
+	issues    []string      // 严格模式下记录的问题
+	// 以下用于拷贝后钩子
+	depth int           // copyRecursive 的嵌套深度，仅在注册了钩子时维护
+	hooks []pendingHook // 等待执行的钩子
+	// 以下用于 CopyWithReport
+	report *Report // 遍历统计，为 nil 时不统计
+}
+
+// claimReuse CopyInto 中目标的切片、映射存储只复用一次：多个目标字段引用同一存储时，
+// 之后遇到的字段分配新的存储，否则后面的拷贝会覆盖前面已写入的内容
+func (s *copyState) claimReuse(cpy reflect.Value) bool {
+	key := refKey{ptr: cpy.Pointer(), typ: cpy.Type()}
+	if s.reused[key] {
+		return false
+	}
+	if s.reused == nil {
+		s.reused = make(map[refKey]bool)
+	}
+	s.reused[key] = true
+	return true
+}
+
+// refKey 切片或映射的标识：底层地址、类型以及切片的长度和容量
+type refKey struct {
+	ptr      uintptr
+	typ      reflect.Type
+	len, cap int
+}
+
+// lookupVisited 查找已复制的指针
+func (s *copyState) lookupVisited(ptr uintptr) (reflect.Value, bool) {
+	if s.acyclic {
+		return reflect.Value{}, false
+	}
+	v, ok := s.visited[ptr]
+	return v, ok
+}
+
+// markVisited 记录已复制的指针
+func (s *copyState) markVisited(ptr uintptr, v reflect.Value) {
+	if s.acyclic {
+		return
+	}
+	if s.visited == nil {
+		s.visited = make(map[uintptr]reflect.Value)
+	}
+	s.visited[ptr] = v
+}
+
+// lookupRef 查找已复制的切片或映射
+func (s *copyState) lookupRef(key refKey) (reflect.Value, bool) {
+	v, ok := s.refs[key]
+	return v, ok
+}
+
+// markRef 记录已复制的切片或映射
+func (s *copyState) markRef(key refKey, v reflect.Value) {
+	if s.refs == nil {
+		s.refs = make(map[refKey]reflect.Value)
+	}
+	s.refs[key] = v
+}
+
+// mayFormCycle 判断切片或映射能否经由元素引用自身（元素为接口、指针、切片、映射或复合类型时）
+// 元素为基本类型时无需记录，避免额外开销
+func mayFormCycle(t reflect.Type) bool {
+	switch t.Elem().Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Slice, reflect.Map, reflect.Struct, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// canBulkCopy 判断元素类型为 t 的切片能否整体复制：元素只包含值类型（自定义了 DeepCopy 的类型不属于此类），
+// 且没有需要逐字段处理的字段过滤、类型转换
+func (s *copyState) canBulkCopy(t reflect.Type) bool {
+	if s.cfg.fieldFilter != nil || s.cfg.typeConverters != nil || s.cfg.whitelist {
+		return false
+	}
+	return s.manager.getOrAnalyzeType(t).IsOnlyValues
+}
+
+// newCopyState 创建新的拷贝状态
+func newCopyState(cfg *copyConfig) *copyState {
+	return &copyState{
+		cfg:     cfg,
+		manager: defaultManager,
+	}
+}
+
+// copyRecursive 使用反射递归地复制值（使用默认配置）
 func copyRecursive(original, cpy reflect.Value, visited map[uintptr]reflect.Value) {
+	state := &copyState{visited: visited, cfg: &defaultCopyConfig, manager: defaultManager}
+	state.copyRecursive(original, cpy)
+}
+
+// copyRecursive 使用反射递归地复制值
+func (s *copyState) copyRecursive(original, cpy reflect.Value) {
+	// 已出错则停止继续遍历
+	if s.err != nil {
+		return
+	}
+	// 注册了拷贝后钩子时记录嵌套深度，在最外层返回时执行钩子
+	if s.manager.postCopyHooks != nil {
+		s.depth++
+		defer s.leaveHooked(cpy)
+	}
+	if s.report != nil {
+		s.report.enter()
+		defer s.report.leave()
+	}
+	// 注册了类型转换时先检查是否需要转换
+	if s.cfg.typeConverters != nil && s.convertType(original, cpy) {
+		return
+	}
+	// 管理器中注册的自定义拷贝函数
+	if s.manager.customCopiers != nil {
+		if fn, ok := s.manager.customCopiers[original.Type()]; ok {
+			s.report.customCopy()
+			s.applyConverter(original, cpy, typeConverter{to: original.Type(), conv: fn})
+			return
+		}
+	}
+	// 按接口注册的自定义拷贝函数，接口类型的值按其动态值匹配
+	if s.manager.ifaceCopiers != nil && original.Kind() != reflect.Interface {
+		if fn := s.manager.getOrAnalyzeType(original.Type()).ifaceCopier; fn != nil {
+			s.report.customCopy()
+			s.applyConverter(original, cpy, typeConverter{to: original.Type(), conv: fn})
+			return
+		}
+	}
+
+	// 实现了 io.Reader、io.Writer 或 io.Closer 的值按策略共享、置零或报错，默认策略按普通值拷贝
+	if s.cfg.ioResourcePolicy != IOResourceWarn && s.copyIOResource(original, cpy) {
+		return
+	}
+
 	// 处理不同的类型
 	switch original.Kind() {
 	case reflect.Ptr:
@@ -473,24 +1233,42 @@ func copyRecursive(original, cpy reflect.Value, visited map[uintptr]reflect.Valu
 
 		// 检查是否已经复制过这个指针
 		ptr := original.Pointer()
-		if v, ok := visited[ptr]; ok {
+		if v, ok := s.lookupVisited(ptr); ok {
+			s.report.cycleHit()
 			cpy.Set(v)
 			return
 		}
+		// 文件、网络连接等句柄直接共享，复制其结构体只会得到失效的句柄
+		if isHandleType(original.Type()) {
+			cpy.Set(original)
+			return
+		}
+		s.report.pointer(original.Type().Elem())
+
+		// container/list、container/ring 通过公开 API 重建
+		if !s.manager.disableBuiltins && s.copyContainer(original, cpy) {
+			return
+		}
+
+		// 实现了 CycleAwareCopier 的类型优先，其中通过 Memo 拷贝的子值与外层共享已复制指针的记录
+		if method, found := s.deepCopyWithMethod(original); found && s.copyWithMemo(original, cpy, method) {
+			s.markVisited(ptr, cpy)
+			return
+		}
 
 		// 首先检查指针本身是否有 DeepCopy 方法
-		if method, found := hasDeepCopyMethod(original); found {
-			result := callDeepCopy(original, method)
+		if method, found := s.deepCopyMethod(original); found {
+			result := s.callDeepCopy(original, method)
 			if result.IsValid() {
 				// 如果DeepCopy返回的是值类型，需要创建新指针
 				if result.Type() != original.Type() {
-					newPtr := reflect.New(result.Type())
+					newPtr := s.cfg.allocator.New(result.Type())
 					newPtr.Elem().Set(result)
 					cpy.Set(newPtr)
 				} else {
 					cpy.Set(result)
 				}
-				visited[ptr] = cpy
+				s.markVisited(ptr, cpy)
 				return
 			}
 		}
@@ -498,21 +1276,21 @@ func copyRecursive(original, cpy reflect.Value, visited map[uintptr]reflect.Valu
 		originalValue := original.Elem()
 
 		// 然后检查指针指向的值是否有 DeepCopy 方法
-		if method, found := hasDeepCopyMethod(originalValue); found {
-			result := callDeepCopy(originalValue, method)
+		if method, found := s.deepCopyMethod(originalValue); found {
+			result := s.callDeepCopy(originalValue, method)
 			if result.IsValid() {
-				newPtr := reflect.New(result.Type())
+				newPtr := s.cfg.allocator.New(result.Type())
 				newPtr.Elem().Set(result)
 				cpy.Set(newPtr)
-				visited[ptr] = cpy
+				s.markVisited(ptr, cpy)
 				return
 			}
 		}
 
-		cpy.Set(reflect.New(originalValue.Type()))
+		cpy.Set(s.cfg.allocator.New(originalValue.Type()))
 		// 保存新创建的指针
-		visited[ptr] = cpy
-		copyRecursive(originalValue, cpy.Elem(), visited)
+		s.markVisited(ptr, cpy)
+		s.copyRecursive(originalValue, cpy.Elem())
 
 	case reflect.Interface:
 		if original.IsNil() {
@@ -520,214 +1298,556 @@ func copyRecursive(original, cpy reflect.Value, visited map[uintptr]reflect.Valu
 			return
 		}
 		originalValue := original.Elem()
-		copyValue := reflect.New(originalValue.Type()).Elem()
-		copyRecursive(originalValue, copyValue, visited)
+		// reflect.Type 是不可变的类型描述，直接共享；net.Conn 中的连接无论具体类型都是句柄，同样共享；
+		// WithShallowInterfaces 时所有接口值都直接共享
+		if s.cfg.shallowInterfaces || original.Type() == netConnType || originalValue.Type().Implements(reflectTypeType) {
+			cpy.Set(original)
+			return
+		}
+		// 只有未导出字段的结构体（如其他包的不可变值类型）逐字段拷贝会丢失全部状态；
+		// 接口中的结构体值不能被原地修改，直接共享，严格模式下报错
+		if originalValue.Kind() == reflect.Struct && s.cfg.typeConverters == nil &&
+			s.manager.getOrAnalyzeType(originalValue.Type()).opaque {
+			if s.cfg.strict && !originalValue.IsZero() {
+				s.recordIssue(fmt.Sprintf("%s has only unexported fields and no DeepCopy method", originalValue.Type()))
+				return
+			}
+			cpy.Set(original)
+			return
+		}
+		copyType := originalValue.Type()
+		if s.cfg.typeConverters != nil {
+			copyType = s.interfaceElemType(copyType, original.Type())
+		}
+		copyValue := reflect.New(copyType).Elem()
+		mark := len(s.hooks)
+		s.copyRecursive(originalValue, copyValue)
+		s.runInlineHooks(mark, copyValue)
 		cpy.Set(copyValue)
 
 	case reflect.Struct:
-		// 特殊处理 time.Time
-		if t, ok := original.Interface().(time.Time); ok {
-			cpy.Set(reflect.ValueOf(t))
+		// 特殊处理 time.Time、netip 等不可变值类型，直接共享
+		if !s.manager.disableBuiltins && immutableValueTypes[original.Type()] {
+			cpy.Set(original)
+			return
+		}
+
+		// reflect.Value 只有未导出字段，逐字段复制会得到零值，直接共享
+		if original.Type() == reflectValueType {
+			cpy.Set(original)
 			return
 		}
 
-		// 检查结构体是否有 DeepCopy 方法
-		if method, found := hasDeepCopyMethod(original); found {
-			result := callDeepCopy(original, method)
+		// 检查结构体是否有 DeepCopyWith 或 DeepCopy 方法
+		if method, found := s.deepCopyWithMethod(original); found && s.copyWithMemo(original, cpy, method) {
+			return
+		}
+		if method, found := s.deepCopyMethod(original); found {
+			result := s.callDeepCopy(original, method)
 			if result.IsValid() {
 				cpy.Set(result)
 				return
 			}
 		}
 
-		// 复制结构体的每个导出字段
-		for i := 0; i < original.NumField(); i++ {
-			field := original.Type().Field(i)
-			// 跳过未导出字段 (PkgPath 不为空表示未导出)
-			if field.PkgPath != "" {
-				continue
+		// 实现了序列化接口的类型通过序列化往返拷贝，保留未导出字段中的状态；往返会带上脱敏字段，随后清零
+		if s.copyViaMarshaler(original, cpy) {
+			for _, idx := range s.manager.getOrAnalyzeType(original.Type()).RedactedFieldIndices {
+				cpy.Field(idx).Set(reflect.Zero(cpy.Field(idx).Type()))
+			}
+			return
+		}
+
+		// 目标不可寻址时字段无法设置（例如 CopyReflectValueInto 传入 reflect.ValueOf(v)），返回错误而不是 panic
+		if !cpy.CanSet() {
+			path := s.pathString()
+			if path == "" {
+				path = "(root)"
+			}
+			s.err = fmt.Errorf("%w: %s at %s, pass an addressable value such as reflect.ValueOf(&v).Elem()",
+				ErrNotSettable, cpy.Type(), path)
+			return
+		}
+
+		analysis := s.manager.getOrAnalyzeType(original.Type())
+		// 带 lock 标签的锁在拷贝其余字段期间持有，panic 时同样释放
+		if len(analysis.lockFields) > 0 && original.CanAddr() {
+			defer holdLocks(original, analysis.lockFields)()
+		}
+		if analysis.funcWarning != nil && !s.funcChecked {
+			s.funcChecked = true
+			if !s.cfg.zeroFuncs {
+				analysis.funcWarning.Do(func() {
+					s.manager.warn("deepcopy: %s contains func fields, closures in the copy share captured state with the original", original.Type())
+				})
+			}
+		}
+		if analysis.ioWarning != nil && !s.ioChecked {
+			s.ioChecked = true
+			if s.cfg.ioResourcePolicy == IOResourceWarn {
+				analysis.ioWarning.Do(func() {
+					s.manager.warn("deepcopy: %s contains io.Reader/io.Writer/io.Closer values, the copy shares the underlying streams with the original", original.Type())
+				})
+			}
+		}
+		if analysis.redactFastPath && s.cfg.useFastPath() {
+			// 除脱敏字段外只包含值类型：整体复制，随后清零脱敏字段
+			cpy.Set(original)
+		} else {
+			// 复制结构体的每个导出字段（下标在类型分析时预先计算，未导出字段和脱敏字段已被排除）
+			fields := analysis.ExportedFieldIndices
+			if s.cfg.whitelist {
+				fields = analysis.whitelistFields(s.manager, original.Type(), s.cfg.whitelistKey)
+			}
+			for _, i := range fields {
+				// 被字段过滤器排除的字段在副本中保持零值
+				if s.cfg.fieldFilter != nil && !s.cfg.fieldFilter(original.Type().Field(i)) {
+					continue
+				}
+				// WithSkipZeroSource 时零值字段保留目标中原有的值
+				if s.cfg.skipZeroSource && original.Field(i).IsZero() {
+					continue
+				}
+				s.pushField(original.Type(), i)
+				s.copyRecursive(original.Field(i), cpy.Field(i))
+				s.popPath()
+			}
+
+			if s.cfg.strict && len(analysis.ExportedFieldIndices)+len(analysis.RedactedFieldIndices) < original.NumField() {
+				s.checkDroppedFields(original)
+			}
+		}
+
+		// 脱敏字段在副本中总是零值，CopyInto 复用目标时同样清零
+		for _, idx := range analysis.RedactedFieldIndices {
+			cpy.Field(idx).Set(reflect.Zero(cpy.Field(idx).Type()))
+		}
+
+		// 嵌入的锁可能处于加锁状态，副本总是从未加锁的零值开始
+		for _, idx := range analysis.MutexFieldIndices {
+			cpy.Field(idx).Set(reflect.Zero(cpy.Field(idx).Type()))
+		}
+		for _, idx := range analysis.lockFields {
+			if field := cpy.Field(idx); field.CanSet() {
+				field.Set(reflect.Zero(field.Type()))
 			}
-			copyRecursive(original.Field(i), cpy.Field(i), visited)
 		}
 
 	case reflect.Slice:
-		if original.IsNil() {
-			cpy.Set(reflect.Zero(original.Type()))
+		if s.copyNilOrEmpty(original, cpy) {
 			return
 		}
-		cpy.Set(reflect.MakeSlice(original.Type(), original.Len(), original.Cap()))
+		capacity := original.Cap()
+		if s.cfg.trimCapacity {
+			capacity = original.Len()
+		}
+		if s.copyViaMarshaler(original, cpy) {
+			return
+		}
+
+		// 同一个切片（例如经由 []interface{} 引用自身）只复制一次
+		trackRef := !s.acyclic && original.Cap() > 0 && mayFormCycle(original.Type())
+		key := refKey{ptr: original.Pointer(), typ: original.Type(), len: original.Len(), cap: original.Cap()}
+		if trackRef {
+			if v, ok := s.lookupRef(key); ok {
+				s.report.cycleHit()
+				cpy.Set(v)
+				return
+			}
+		}
+
+		// CopyInto 时长度相同的目标切片直接复用其底层数组
+		newSlice := cpy
+		if !s.reuse || cpy.IsNil() || cpy.Len() != original.Len() || cpy.Pointer() == original.Pointer() || !s.claimReuse(cpy) {
+			newSlice = s.cfg.allocator.NewSlice(original.Type(), original.Len(), capacity)
+			cpy.Set(newSlice)
+			s.report.slice(original.Type(), original.Len(), capacity)
+		} else {
+			s.report.slice(original.Type(), original.Len(), 0)
+		}
+		if trackRef {
+			s.markRef(key, newSlice)
+		}
+		// 元素只包含值类型时整体复制，无需逐个元素递归
+		if s.canBulkCopy(original.Type().Elem()) {
+			reflect.Copy(newSlice, original)
+			return
+		}
+		// 切片元素总是可寻址的，只有指针接收者的 DeepCopy 方法时通过元素地址调用
+		ptrDeepCopy := s.manager.getOrAnalyzeType(original.Type().Elem()).ptrDeepCopy
 		for i := 0; i < original.Len(); i++ {
-			copyRecursive(original.Index(i), cpy.Index(i), visited)
+			s.pushIndex(i)
+			if !ptrDeepCopy || !s.copyViaAddr(original.Index(i), cpy.Index(i)) {
+				s.copyRecursive(original.Index(i), cpy.Index(i))
+			}
+			s.popPath()
 		}
 
 	case reflect.Map:
-		if original.IsNil() {
-			cpy.Set(reflect.Zero(original.Type()))
+		if s.copyNilOrEmpty(original, cpy) {
 			return
 		}
-		cpy.Set(reflect.MakeMap(original.Type()))
-		for _, key := range original.MapKeys() {
-			originalValue := original.MapIndex(key)
-			copyValue := reflect.New(originalValue.Type()).Elem()
-			copyRecursive(originalValue, copyValue, visited)
-			// 对 map 的键也进行深拷贝
-			copyKey := reflect.New(key.Type()).Elem()
-			copyRecursive(key, copyKey, visited)
+		if s.copyViaMarshaler(original, cpy) {
+			return
+		}
+
+		// 同一个映射（例如经由 map[string]interface{} 引用自身）只复制一次
+		trackRef := !s.acyclic && mayFormCycle(original.Type())
+		key := refKey{ptr: original.Pointer(), typ: original.Type()}
+		if trackRef {
+			if v, ok := s.lookupRef(key); ok {
+				s.report.cycleHit()
+				cpy.Set(v)
+				return
+			}
+		}
+
+		// CopyInto 时清空并复用目标中已有的映射
+		newMap := cpy
+		if s.reuse && !cpy.IsNil() && cpy.Pointer() != original.Pointer() && s.claimReuse(cpy) {
+			cpy.Clear()
+		} else {
+			newMap = s.cfg.allocator.NewMap(original.Type(), original.Len())
+			cpy.Set(newMap)
+		}
+		if trackRef {
+			s.markRef(key, newMap)
+		}
+		// 以 NaN 为键的条目无法通过键再次取到，使用 MapRange 同时遍历键和值，
+		// 默认原样保留（副本的长度与原始值一致），可通过 WithNaNKeyPolicy 丢弃或报错
+		// 只包含值类型的键、值取出时已是独立的副本，直接写入，无需分配和递归
+		valueOnly := s.canBulkCopy(original.Type().Elem())
+		keyOnly := s.cfg.sharedMapKeys || s.canBulkCopy(original.Type().Key())
+		// copyEntry 拷贝一个条目，出错时返回 false
+		copyEntry := func(key, value reflect.Value) bool {
+			if s.cfg.nanKeyPolicy != NaNKeyPreserve && containsNaN(key) {
+				if s.cfg.nanKeyPolicy == NaNKeyError {
+					s.err = fmt.Errorf("%w: %s", ErrNaNMapKey, original.Type())
+					return false
+				}
+				return true
+			}
+			s.pushKey(key)
+			s.report.mapEntry(original.Type())
+			copyValue := value
+			mark := len(s.hooks)
+			if !valueOnly {
+				copyValue = reflect.New(value.Type()).Elem()
+				s.copyRecursive(value, copyValue)
+				s.runInlineHooks(mark, copyValue)
+			}
+			// 默认对 map 的键也进行深拷贝，WithSharedMapKeys 时键原样保留
+			copyKey := key
+			if !keyOnly {
+				copyKey = reflect.New(key.Type()).Elem()
+				s.copyRecursive(key, copyKey)
+				s.runInlineHooks(mark, copyKey)
+			}
 			cpy.SetMapIndex(copyKey, copyValue)
+			s.popPath()
+			return true
+		}
+		if s.cfg.snapshotMaps {
+			s.copyMapSnapshot(original, copyEntry)
+			return
+		}
+		// 通过可复用的变量读取键和值，避免 MapRange 每个条目分配
+		keyHolder := reflect.New(original.Type().Key()).Elem()
+		value := reflect.New(original.Type().Elem()).Elem()
+		iter := original.MapRange()
+		for iter.Next() {
+			keyHolder.SetIterKey(iter)
+			value.SetIterValue(iter)
+			if !copyEntry(keyHolder, value) {
+				return
+			}
 		}
 
 	case reflect.Array:
+		if s.copyViaMarshaler(original, cpy) {
+			return
+		}
+		// 元素只包含值类型时整体赋值（例如经由指针拷贝的大数组）
+		if s.canBulkCopy(original.Type().Elem()) {
+			cpy.Set(original)
+			return
+		}
 		// 数组需要逐个元素进行深拷贝
 		for i := 0; i < original.Len(); i++ {
-			copyRecursive(original.Index(i), cpy.Index(i), visited)
+			s.pushIndex(i)
+			s.copyRecursive(original.Index(i), cpy.Index(i))
+			s.popPath()
 		}
 
-	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
-		// 这些类型直接复制（浅拷贝）
-		// Chan: 通道是引用类型，通常需要共享
-		// Func: 函数是不可变的，可以安全共享
-		// UnsafePointer: 直接复制指针值
-		cpy.Set(original)
+	case reflect.UnsafePointer:
+		// UnsafePointer: 按配置的策略处理原始地址
+		s.copyUnsafePointer(original, cpy)
 
-	default:
-		// 对于基本类型（int, string, bool, float等），直接设置值
-		cpy.Set(original)
-	}
-}
-
-// copyRecursiveWithCache 使用缓存的类型分析结果进行深拷贝，避免重复反射分析
-func copyRecursiveWithCache(original, cpy reflect.Value, visited map[uintptr]reflect.Value, typeInfo *TypeAnalysisResult) {
-	// 处理不同的类型
-	switch original.Kind() {
-	case reflect.Ptr:
-		if original.IsNil() {
+	case reflect.Func:
+		// Go 中无法深拷贝函数：函数值本身不可变，但闭包捕获的变量在副本与原值之间共享。
+		// 默认直接共享函数值，WithShareFuncs(false) 时副本中置为 nil，由调用方显式重新设置
+		if s.cfg.zeroFuncs {
+			if s.cfg.strict && !original.IsNil() {
+				s.recordIssue("func not copied")
+			}
 			cpy.Set(reflect.Zero(original.Type()))
 			return
 		}
-
-		// 检查是否已经复制过这个指针
-		ptr := original.Pointer()
-		if v, ok := visited[ptr]; ok {
-			cpy.Set(v)
+		if s.cfg.funcWrapper != nil && !original.IsNil() {
+			s.wrapFunc(original, cpy)
 			return
 		}
+		if s.cfg.strict && !original.IsNil() {
+			s.recordIssue("func shared")
+		}
+		cpy.Set(original)
 
-		// 首先检查指针本身是否有 DeepCopy 方法
-		if method, found := hasDeepCopyMethod(original); found {
-			result := callDeepCopy(original, method)
-			if result.IsValid() {
-				if result.Type() != original.Type() {
-					newPtr := reflect.New(result.Type())
-					newPtr.Elem().Set(result)
-					cpy.Set(newPtr)
-				} else {
+	case reflect.Chan:
+		// 通道是引用类型，通常需要共享，直接复制（浅拷贝）
+		if s.cfg.strict && !original.IsNil() {
+			s.recordIssue("chan shared")
+		}
+		cpy.Set(original)
+
+	default:
+		// 带方法的命名基本类型（如 type Celsius float64）可能自定义了 DeepCopy
+		if original.Type().NumMethod() > 0 {
+			if method, found := s.deepCopyMethod(original); found {
+				result := s.callDeepCopy(original, method)
+				if result.IsValid() && result.Type() == original.Type() {
 					cpy.Set(result)
+					return
 				}
-				visited[ptr] = cpy
-				return
 			}
 		}
 
-		originalValue := original.Elem()
+		// 对于基本类型（int, string, bool, float等），直接设置值
+		cpy.Set(original)
+	}
+}
 
-		// 然后检查指针指向的值是否有 DeepCopy 方法
-		if method, found := hasDeepCopyMethod(originalValue); found {
-			result := callDeepCopy(originalValue, method)
-			if result.IsValid() {
-				newPtr := reflect.New(result.Type())
-				newPtr.Elem().Set(result)
-				cpy.Set(newPtr)
-				visited[ptr] = cpy
-				return
+// hasUnexportedFields 类型中（沿字段、指针和元素）是否存在未导出的结构体字段，接口的动态类型不计入
+func hasUnexportedFields(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasUnexportedFields(t.Elem(), visited)
+	case reflect.Map:
+		return hasUnexportedFields(t.Key(), visited) || hasUnexportedFields(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if field := t.Field(i); !field.IsExported() || hasUnexportedFields(field.Type, visited) {
+				return true
 			}
 		}
+	}
+	return false
+}
 
-		cpy.Set(reflect.New(originalValue.Type()))
-		visited[ptr] = cpy
-		copyRecursiveWithCache(originalValue, cpy.Elem(), visited, nil) // 子类型分析信息暂时为nil
+// copyViaMarshaler 对包含未导出字段、实现了 encoding.BinaryMarshaler 或 encoding.TextMarshaler
+// （且指针实现了对应 Unmarshaler）的类型，通过序列化再反序列化完成拷贝，优先使用二进制格式。
+// 返回 true 表示已处理；序列化失败时退回逐字段拷贝
+func (s *copyState) copyViaMarshaler(original, cpy reflect.Value) bool {
+	t := original.Type()
+	// 未命名类型没有方法，避免对每个节点查询分析缓存
+	if t.Name() == "" {
+		return false
+	}
+	if s.manager.disableBuiltins {
+		return false
+	}
+	analysis := s.manager.getOrAnalyzeType(t)
+	if !analysis.viaMarshaler {
+		return false
+	}
 
-	case reflect.Interface:
-		if original.IsNil() {
-			cpy.Set(reflect.Zero(original.Type()))
-			return
+	// 复制到新指针上以便调用指针接收者的方法
+	src := reflect.New(t)
+	src.Elem().Set(original)
+	dst := reflect.New(t)
+
+	var err error
+	ptrType := src.Type()
+	switch {
+	case analysis.ImplementsBinaryMarshaler && ptrType.Implements(binaryUnmarshalerType):
+		var data []byte
+		if data, err = src.Interface().(encoding.BinaryMarshaler).MarshalBinary(); err == nil {
+			err = dst.Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(data)
 		}
-		originalValue := original.Elem()
-		copyValue := reflect.New(originalValue.Type()).Elem()
-		copyRecursiveWithCache(originalValue, copyValue, visited, nil)
-		cpy.Set(copyValue)
-
-	case reflect.Struct:
-		// 特殊处理 time.Time
-		if t, ok := original.Interface().(time.Time); ok {
-			cpy.Set(reflect.ValueOf(t))
-			return
+	case analysis.ImplementsTextMarshaler && ptrType.Implements(textUnmarshalerType):
+		var data []byte
+		if data, err = src.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			err = dst.Interface().(encoding.TextUnmarshaler).UnmarshalText(data)
 		}
+	default:
+		return false
+	}
+	if err != nil {
+		return false
+	}
 
-		// 检查结构体是否有 DeepCopy 方法
-		if method, found := hasDeepCopyMethod(original); found {
-			result := callDeepCopy(original, method)
-			if result.IsValid() {
-				cpy.Set(result)
-				return
-			}
-		}
+	cpy.Set(dst.Elem())
+	return true
+}
 
-		// 复制结构体的每个导出字段
-		// 这里可以利用缓存的字段分析信息来优化
-		for i := 0; i < original.NumField(); i++ {
-			field := original.Type().Field(i)
-			// 跳过未导出字段 (PkgPath 不为空表示未导出)
-			if field.PkgPath != "" {
+// copyMapSnapshot WithSnapshotMap 时先取出映射的全部条目，再逐个交给 copyEntry。
+// 拷贝前重新查找键，已被删除的条目跳过；NaN 键无法查找，使用取出时的值
+func (s *copyState) copyMapSnapshot(original reflect.Value, copyEntry func(key, value reflect.Value) bool) {
+	keys := make([]reflect.Value, 0, original.Len())
+	values := make([]reflect.Value, 0, original.Len())
+	iter := original.MapRange()
+	for iter.Next() {
+		keys = append(keys, iter.Key())
+		values = append(values, iter.Value())
+	}
+	for i, key := range keys {
+		value := values[i]
+		if !containsNaN(key) {
+			if value = original.MapIndex(key); !value.IsValid() {
 				continue
 			}
+		}
+		if !copyEntry(key, value) {
+			return
+		}
+	}
+}
 
-			// 如果有字段分析信息，可以进一步优化
-			var fieldTypeInfo *TypeAnalysisResult
-			if typeInfo != nil && typeInfo.FieldAnalysis != nil {
-				fieldTypeInfo = typeInfo.FieldAnalysis[field.Name]
+// containsNaN 判断 map 键中是否包含 NaN（浮点数、复数以及其所在的结构体、数组和接口）
+func containsNaN(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return math.IsNaN(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return math.IsNaN(real(c)) || math.IsNaN(imag(c))
+	case reflect.Interface:
+		return !v.IsNil() && containsNaN(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if containsNaN(v.Field(i)) {
+				return true
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if containsNaN(v.Index(i)) {
+				return true
 			}
-
-			copyRecursiveWithCache(original.Field(i), cpy.Field(i), visited, fieldTypeInfo)
 		}
+	}
+	return false
+}
 
-	case reflect.Slice:
-		if original.IsNil() {
+// copyNilOrEmpty 按配置处理 nil 或空的切片、映射，返回 true 表示已处理完毕
+func (s *copyState) copyNilOrEmpty(original, cpy reflect.Value) bool {
+	switch {
+	case original.IsNil():
+		if s.cfg.nilCollections == emptyForNil {
+			if original.Kind() == reflect.Slice {
+				cpy.Set(s.cfg.allocator.NewSlice(original.Type(), 0, 0))
+			} else {
+				cpy.Set(s.cfg.allocator.NewMap(original.Type(), 0))
+			}
+		} else {
 			cpy.Set(reflect.Zero(original.Type()))
-			return
-		}
-		cpy.Set(reflect.MakeSlice(original.Type(), original.Len(), original.Cap()))
-		for i := 0; i < original.Len(); i++ {
-			copyRecursiveWithCache(original.Index(i), cpy.Index(i), visited, nil)
 		}
+		return true
+	case original.Len() == 0 && s.cfg.nilCollections == nilForEmpty:
+		cpy.Set(reflect.Zero(original.Type()))
+		return true
+	}
+	return false
+}
 
-	case reflect.Map:
+// wrapFunc 使用 WithFuncWrapper 设置的函数替换副本中的函数值
+func (s *copyState) wrapFunc(original, cpy reflect.Value) {
+	wrapped := s.cfg.funcWrapper(original)
+	switch {
+	case !wrapped.IsValid():
+		s.err = fmt.Errorf("%w: func wrapper for %s returned an invalid value", ErrTypeConversion, original.Type())
+	case wrapped.Type() != original.Type():
+		s.err = fmt.Errorf("%w: func wrapper for %s returned %s", ErrTypeConversion, original.Type(), wrapped.Type())
+	default:
+		cpy.Set(wrapped)
+	}
+}
+
+// copyIOResource 按 WithIOResourcePolicy 处理 io 资源，original 不是 io 资源时返回 false。
+// 接口值按其动态值判断（IOResourceNil 时整个接口置为 nil），nil 值按普通值拷贝
+func (s *copyState) copyIOResource(original, cpy reflect.Value) bool {
+	dynamic := original
+	switch original.Kind() {
+	case reflect.Interface:
 		if original.IsNil() {
-			cpy.Set(reflect.Zero(original.Type()))
-			return
+			return false
 		}
-		cpy.Set(reflect.MakeMap(original.Type()))
-		for _, key := range original.MapKeys() {
-			originalValue := original.MapIndex(key)
-			copyValue := reflect.New(originalValue.Type()).Elem()
-			copyRecursiveWithCache(originalValue, copyValue, visited, nil)
-			// 对 map 的键也进行深拷贝
-			copyKey := reflect.New(key.Type()).Elem()
-			copyRecursiveWithCache(key, copyKey, visited, nil)
-			cpy.SetMapIndex(copyKey, copyValue)
+		dynamic = original.Elem()
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		if original.IsNil() {
+			return false
 		}
+	}
+	if !s.manager.getOrAnalyzeType(dynamic.Type()).isIOResource {
+		return false
+	}
 
-	case reflect.Array:
-		// 数组需要逐个元素进行深拷贝
-		for i := 0; i < original.Len(); i++ {
-			copyRecursiveWithCache(original.Index(i), cpy.Index(i), visited, nil)
+	switch s.cfg.ioResourcePolicy {
+	case IOResourceShare:
+		cpy.Set(original)
+	case IOResourceNil:
+		cpy.Set(reflect.Zero(original.Type()))
+	case IOResourceError:
+		s.err = fmt.Errorf("%w: %s", ErrIOResource, dynamic.Type())
+	}
+	return true
+}
+
+// isHandleType 是否为拷贝时总是共享的句柄类型：*os.File 和标准库中 net.Conn 的实现。
+// 复制它们的结构体只会得到失效的文件描述符，IOResourceNil、IOResourceError 策略下仍按策略处理
+func isHandleType(t reflect.Type) bool {
+	switch t {
+	case osFileType, tcpConnType, udpConnType, unixConnType, ipConnType:
+		return true
+	}
+	return false
+}
+
+// isIOResourceType 类型是否实现了 io.Reader、io.Writer 或 io.Closer，非指针类型同时检查其指针（方法为指针接收者时）
+func isIOResourceType(t reflect.Type) bool {
+	for _, iface := range []reflect.Type{ioReaderType, ioWriterType, ioCloserType} {
+		if t.Implements(iface) {
+			return true
 		}
+		if t.Kind() != reflect.Interface && t.Kind() != reflect.Ptr && reflect.PointerTo(t).Implements(iface) {
+			return true
+		}
+	}
+	return false
+}
 
-	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
-		// 这些类型直接复制（浅拷贝）
-		cpy.Set(original)
+// copyUnsafePointer 根据 UnsafePointerPolicy 处理 unsafe.Pointer
+func (s *copyState) copyUnsafePointer(original, cpy reflect.Value) {
+	if s.cfg.strict && !original.IsNil() && s.cfg.unsafePointerPolicy != UnsafePointerError {
+		s.recordIssue("unsafe.Pointer not copied")
+	}
 
+	switch s.cfg.unsafePointerPolicy {
+	case UnsafePointerZero:
+		cpy.Set(reflect.Zero(original.Type()))
+	case UnsafePointerError:
+		s.err = fmt.Errorf("%w: %s", ErrUnsafePointer, original.Type())
 	default:
-		// 对于基本类型（int, string, bool, float等），直接设置值
+		// nil 不共享任何内存，不需要警告；同一类型只警告一次，避免热点路径刷屏
+		if !original.IsNil() {
+			s.manager.getOrAnalyzeType(original.Type()).unsafeWarning.Do(func() {
+				s.manager.warn("deepcopy: copying %s as-is, the copy shares the raw address with the original", original.Type())
+			})
+		}
 		cpy.Set(original)
 	}
 }
@@ -0,0 +1,447 @@
+package deepcopy
+
+import (
+	"strconv"
+	"testing"
+)
+
+// benchBasics 基准测试使用的 Basics 样例
+var benchBasics = Basics{
+	String:      "kimchi",
+	Strings:     []string{"uni", "ika"},
+	StringArr:   [4]string{"malort", "barenjager", "fernet", "salmiakki"},
+	Bool:        true,
+	Bools:       []bool{true, false, true},
+	Byte:        'z',
+	Bytes:       []byte("abc"),
+	Int:         42,
+	Ints:        []int{0, 1, 3, 4},
+	Int8:        8,
+	Int8s:       []int8{8, 9, 10},
+	Int16:       16,
+	Int16s:      []int16{16, 17, 18, 19},
+	Int32:       32,
+	Int32s:      []int32{32, 33},
+	Int64:       64,
+	Int64s:      []int64{64},
+	Uint:        420,
+	Uints:       []uint{11, 12, 13},
+	Uint8:       81,
+	Uint8s:      []uint8{81, 82},
+	Uint16:      160,
+	Uint16s:     []uint16{160, 161, 162, 163, 164},
+	Uint32:      320,
+	Uint32s:     []uint32{320, 321},
+	Uint64:      640,
+	Uint64s:     []uint64{6400, 6401, 6402, 6403},
+	Float32:     32.32,
+	Float32s:    []float32{32.32, 33},
+	Float64:     64.1,
+	Float64s:    []float64{64, 65, 66},
+	Complex64:   complex64(-64 + 12i),
+	Complex64s:  []complex64{complex64(-65 + 11i), complex64(66 + 10i)},
+	Complex128:  complex128(-128 + 12i),
+	Complex128s: []complex128{complex128(-128 + 11i), complex128(129 + 10i)},
+	Interfaces:  []interface{}{42, true, "pan-galactic"},
+}
+
+func BenchmarkCopyBasics(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchBasics)
+	}
+}
+
+func BenchmarkCopyEBasics(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = CopyE(benchBasics)
+	}
+}
+
+func BenchmarkCopyWithKeyBasics(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = CopyWithKey(benchBasics, "bench.basics")
+	}
+}
+
+// BenchmarkCopyAddrBasics 拷贝后取地址的写法，作为 CloneNew 的对照
+func BenchmarkCopyAddrBasics(b *testing.B) {
+	b.ReportAllocs()
+	var sink *Basics
+	for i := 0; i < b.N; i++ {
+		p := Copy(benchBasics)
+		sink = &p
+	}
+	_ = sink
+}
+
+func BenchmarkCloneNewBasics(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = CloneNew(benchBasics)
+	}
+}
+
+func BenchmarkClonePtrBasics(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = ClonePtr(&benchBasics)
+	}
+}
+
+// benchLargeMap 基准测试使用的大 map
+var benchLargeMap = func() map[int]string {
+	m := make(map[int]string, 100000)
+	for i := 0; i < 100000; i++ {
+		m[i] = "value"
+	}
+	return m
+}()
+
+func BenchmarkCopyLargeMap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchLargeMap)
+	}
+}
+
+// BenchmarkCopyLargeMapRefresh 每次刷新都分配新 map，作为 CopyMapInto 的对照
+func BenchmarkCopyLargeMapRefresh(b *testing.B) {
+	b.ReportAllocs()
+	var cache map[int]string
+	for i := 0; i < b.N; i++ {
+		cache = Copy(benchLargeMap)
+	}
+	_ = cache
+}
+
+func BenchmarkCopyMapIntoLargeMapRefresh(b *testing.B) {
+	cache := make(map[int]string, len(benchLargeMap))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = CopyMapInto(cache, benchLargeMap)
+	}
+}
+
+// benchFrame 每帧快照的游戏状态
+var benchFrame = func() []benchPoint {
+	frame := make([]benchPoint, 10000)
+	for i := range frame {
+		frame[i] = benchPoint{X: float64(i), Y: -float64(i)}
+	}
+	return frame
+}()
+
+func BenchmarkCopyFrame(b *testing.B) {
+	b.ReportAllocs()
+	var snapshot []benchPoint
+	for i := 0; i < b.N; i++ {
+		snapshot = Copy(benchFrame)
+	}
+	_ = snapshot
+}
+
+func BenchmarkCopySliceIntoFrame(b *testing.B) {
+	var snapshot []benchPoint
+	_ = CopySliceInto(&snapshot, benchFrame)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = CopySliceInto(&snapshot, benchFrame)
+	}
+}
+
+// BenchmarkCopySliceIntoSnapshots 元素包含切片和映射，稳态下复用元素中的存储
+func BenchmarkCopySliceIntoSnapshots(b *testing.B) {
+	src := make([]Snapshot, 100)
+	for i := range src {
+		src[i] = Snapshot{Values: []int{i, i + 1}, Index: map[string]int{"i": i}}
+	}
+	var dst []Snapshot
+	_ = CopySliceInto(&dst, src)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = CopySliceInto(&dst, src)
+	}
+}
+
+// benchWindow 从大缓冲区切出的小切片
+var benchWindow = make([]int64, 1<<20)[:16]
+
+func BenchmarkCopySmallWindow(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchWindow)
+	}
+}
+
+func BenchmarkCopySmallWindowTrimmed(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = CopyWithOptions(benchWindow, WithTrimCapacity())
+	}
+}
+
+func BenchmarkCopyOnlyValues(b *testing.B) {
+	src := OnlyValueStruct{Name: "bench", Age: 42}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(src)
+	}
+}
+
+// wideRecord 字段较多的结构体，包含一个切片字段使其不能走快速路径
+type wideRecord struct {
+	F00, F01, F02, F03, F04, F05, F06, F07, F08, F09 int
+	F10, F11, F12, F13, F14, F15, F16, F17, F18, F19 string
+	F20, F21, F22, F23, F24, F25, F26, F27, F28      float64
+	Tags                                             []string
+}
+
+// benchWideRecords 基准测试使用的 10 万个宽结构体
+var benchWideRecords = make([]wideRecord, 100000)
+
+func BenchmarkCopyWideStructSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchWideRecords)
+	}
+}
+
+// benchPoint 只包含值类型的小结构体
+type benchPoint struct {
+	X, Y float64
+}
+
+// benchPoints 基准测试使用的 100 万个点
+var benchPoints = make([]benchPoint, 1<<20)
+
+func BenchmarkCopyValueStructSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchPoints)
+	}
+}
+
+// benchStrings / benchInts / benchFloats 基础类型切片，与手写的 append 对比
+var (
+	benchStrings = func() []string {
+		s := make([]string, 1000)
+		for i := range s {
+			s[i] = strconv.Itoa(i)
+		}
+		return s
+	}()
+	benchInts   = make([]int, 1000)
+	benchFloats = make([]float64, 1000)
+)
+
+func BenchmarkCopyStringSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchStrings)
+	}
+}
+
+func BenchmarkAppendStringSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = append([]string(nil), benchStrings...)
+	}
+}
+
+func BenchmarkCopyIntSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchInts)
+	}
+}
+
+func BenchmarkAppendIntSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = append([]int(nil), benchInts...)
+	}
+}
+
+func BenchmarkCopyFloat64Slice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchFloats)
+	}
+}
+
+func BenchmarkAppendFloat64Slice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = append([]float64(nil), benchFloats...)
+	}
+}
+
+// benchValueMap 值为值类型结构体的大 map
+var benchValueMap = func() map[string]OnlyValueStruct {
+	m := make(map[string]OnlyValueStruct, 100000)
+	for i := 0; i < 100000; i++ {
+		m[strconv.Itoa(i)] = OnlyValueStruct{Name: "v", Age: i}
+	}
+	return m
+}()
+
+func BenchmarkCopyValueStructMap(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchValueMap)
+	}
+}
+
+// benchLargeArray 只包含值类型的大数组（8 MiB）
+var benchLargeArray = new([1 << 20]int)
+
+// 按值传入：进入 Copy 时复制一次参数，快速路径返回时再复制一次
+func BenchmarkCopyLargeArrayByValue(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(*benchLargeArray)
+	}
+}
+
+// 按指针传入：只在副本中复制一次
+func BenchmarkCopyLargeArrayByPointer(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchLargeArray)
+	}
+}
+
+// 每层类型不同的树，类型层面不会形成循环引用
+type (
+	benchTreeRoot struct {
+		Name     string
+		Children []*benchTreeBranch
+	}
+	benchTreeBranch struct {
+		Name     string
+		Children []*benchTreeTwig
+	}
+	benchTreeTwig struct {
+		Name   string
+		Leaves []*benchTreeLeaf
+	}
+	benchTreeLeaf struct {
+		ID    int
+		Value *float64
+	}
+)
+
+// benchTree 扇出为 20 的四层树，共 8000 个叶子
+var benchTree = func() *benchTreeRoot {
+	root := &benchTreeRoot{Name: "root"}
+	for i := 0; i < 20; i++ {
+		branch := &benchTreeBranch{Name: strconv.Itoa(i)}
+		for j := 0; j < 20; j++ {
+			twig := &benchTreeTwig{Name: strconv.Itoa(j)}
+			for k := 0; k < 20; k++ {
+				v := float64(k)
+				twig.Leaves = append(twig.Leaves, &benchTreeLeaf{ID: k, Value: &v})
+			}
+			branch.Children = append(branch.Children, twig)
+		}
+		root.Children = append(root.Children, branch)
+	}
+	return root
+}()
+
+func BenchmarkCopyTree(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(benchTree)
+	}
+}
+
+func BenchmarkCopyNoCyclesTree(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = CopyNoCycles(benchTree)
+	}
+}
+
+var benchTypedBasics = NewTypedBusinessCopy[Basics]("bench.basics")
+
+func BenchmarkTypedBusinessCopyBasics(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = benchTypedBasics.Copy(benchBasics)
+	}
+}
+
+func BenchmarkCopyWithKeyOnlyValues(b *testing.B) {
+	src := OnlyValueStruct{Name: "bench", Age: 42}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = CopyWithKey(src, "bench.only_values")
+	}
+}
+
+func BenchmarkTypedBusinessCopyOnlyValues(b *testing.B) {
+	src := OnlyValueStruct{Name: "bench", Age: 42}
+	copier := NewTypedBusinessCopy[OnlyValueStruct]("bench.only_values")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = copier.Copy(src)
+	}
+}
+
+func BenchmarkCopyBetween(b *testing.B) {
+	src := APIUserV1{ID: 1, Name: "bench", Tags: []string{"a", "b"}, Address: APIAddressV1{City: "Paris"}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = CopyBetween[APIUserV2](src)
+	}
+}
+
+func BenchmarkCopyString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy("kimchi")
+	}
+}
+
+func BenchmarkCopyInt(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Copy(i)
+	}
+}
+
+// 约 50KB 的解码后 JSON：类型断言路径与反射拷贝对比
+func BenchmarkCopyJSONLike(b *testing.B) {
+	payload := decodeJSONPayload(b, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = CopyJSONLike(payload)
+	}
+}
+
+func BenchmarkCopyJSONLikeReflect(b *testing.B) {
+	payload := decodeJSONPayload(b, 200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = CopyWithOptions(payload)
+	}
+}
+
+// CopyValue 反复拷贝同一类型的值
+func BenchmarkManagerCopyValue(b *testing.B) {
+	m := NewDeepCopyManager()
+	src := any(benchBasics)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = m.CopyValue(src)
+	}
+}
@@ -351,6 +351,32 @@ func TestCopyWithKeyCorrectness(t *testing.T) {
 	fmt.Printf("CopyWithKey 正确性测试通过\n")
 }
 
+func TestTypedBusinessCopy(t *testing.T) {
+	profiles := NewTypedBusinessCopy[OnlyValueStruct]("user.profile")
+	profiles.WarmUp()
+	if profiles.Key() != "user.profile" {
+		t.Errorf("Key: got %q", profiles.Key())
+	}
+	if copied := profiles.Copy(OnlyValueStruct{Name: "Config1", Age: 25}); copied.Name != "Config1" || copied.Age != 25 {
+		t.Error("值类型拷贝结果不正确")
+	}
+
+	// 同一个 key 用于不同类型时互不影响
+	snapshots := NewTypedBusinessCopy[Snapshot]("user.profile")
+	original := Snapshot{Values: []int{1}, Index: map[string]int{"a": 1}}
+	copied := snapshots.Copy(original)
+	copied.Values[0] = 999
+	copied.Index["a"] = 999
+	if original.Values[0] != 1 || original.Index["a"] != 1 {
+		t.Error("修改拷贝数据不应该影响原数据")
+	}
+
+	var iface = NewTypedBusinessCopy[any]("any.value")
+	if got := iface.Copy(CustomCopyStruct{Value: 1}).(CustomCopyStruct); got.Value != 101 {
+		t.Errorf("接口类型应使用动态类型的 DeepCopy 方法, got %d", got.Value)
+	}
+}
+
 func TestComplexNestedStructures(t *testing.T) {
 	fmt.Printf("\n=== 复杂嵌套结构体测试 ===\n")
 
@@ -1,8 +1,16 @@
 package deepcopy
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -279,6 +287,103 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+// Wrapper 嵌入实现了 Copier[I] 的 *I，DeepCopy 方法被提升到 Wrapper 上，但返回的类型是 I
+type Wrapper struct {
+	*I
+}
+
+func TestEmbeddedCopier(t *testing.T) {
+	i := &I{A: "test"}
+	original := Wrapper{I: i}
+
+	copied := Copy[Wrapper](original)
+	if copied.I == nil || copied.I == i || copied.I.A != "test_copy" {
+		t.Errorf("Custom copy of embedded field failed, got %+v", copied.I)
+	}
+	if ptr := Copy(&original); ptr == &original || ptr.I == i || ptr.I.A != "test_copy" {
+		t.Errorf("Custom copy of embedded field via pointer failed, got %+v", ptr.I)
+	}
+	if elems := Copy([]Wrapper{original}); elems[0].I == i || elems[0].I.A != "test_copy" {
+		t.Errorf("Custom copy of embedded field in slice failed, got %+v", elems[0].I)
+	}
+	if iface := Copy[any](original).(Wrapper); iface.I == i || iface.I.A != "test_copy" {
+		t.Errorf("Custom copy of embedded field in interface failed, got %+v", iface.I)
+	}
+	if empty := Copy(Wrapper{}); empty.I != nil {
+		t.Errorf("nil embedded pointer should stay nil, got %+v", empty.I)
+	}
+	if i.A != "test" {
+		t.Errorf("original modified: %q", i.A)
+	}
+}
+
+var errSidecar = errors.New("sidecar unavailable")
+
+// SealedSecret 拷贝时需要重新加密，可能失败
+type SealedSecret struct {
+	Cipher string
+	fail   bool
+}
+
+func (s SealedSecret) DeepCopy() (SealedSecret, error) {
+	if s.fail {
+		return SealedSecret{}, errSidecar
+	}
+	return SealedSecret{Cipher: s.Cipher + "'"}, nil
+}
+
+// SecretTenant -> SecretAccount -> SecretVault -> SealedSecret 三层嵌套
+type SecretVault struct {
+	Secrets []SealedSecret
+}
+
+type SecretAccount struct {
+	Vault *SecretVault
+}
+
+type SecretTenant struct {
+	Accounts map[string]SecretAccount
+}
+
+func TestFallibleCopier(t *testing.T) {
+	newTenant := func(fail bool) SecretTenant {
+		vault := &SecretVault{Secrets: []SealedSecret{{Cipher: "a"}, {Cipher: "b", fail: fail}}}
+		return SecretTenant{Accounts: map[string]SecretAccount{"acme": {Vault: vault}}}
+	}
+
+	copied, err := CopyE(newTenant(false))
+	if err != nil || copied.Accounts["acme"].Vault.Secrets[1].Cipher != "b'" {
+		t.Fatalf("got %+v, %v", copied, err)
+	}
+
+	// CopyE 返回带路径的错误
+	_, err = CopyE(newTenant(true))
+	var copierErr *CopierError
+	if !errors.As(err, &copierErr) || !errors.Is(err, errSidecar) {
+		t.Fatalf("expected *CopierError wrapping errSidecar, got %v", err)
+	}
+	if copierErr.Path != `Accounts["acme"].Vault.Secrets[1]` || copierErr.Type != reflect.TypeOf(SealedSecret{}) {
+		t.Errorf("Path = %q, Type = %v", copierErr.Path, copierErr.Type)
+	}
+	var dst SecretTenant
+	if err := CopyInto(&dst, newTenant(true)); !errors.Is(err, errSidecar) {
+		t.Errorf("CopyInto: %v", err)
+	}
+	if _, err := CopyE(SealedSecret{fail: true}); !errors.As(err, &copierErr) || copierErr.Path != "" {
+		t.Errorf("top-level: %v", err)
+	}
+
+	// Copy 以 *CopierError panic
+	func() {
+		defer func() {
+			if err, ok := recover().(*CopierError); !ok || !errors.Is(err, errSidecar) {
+				t.Errorf("Copy should panic with *CopierError, got %v", err)
+			}
+		}()
+		Copy(newTenant(true))
+	}()
+}
+
 // not meant to be exhaustive
 func TestComplexSlices(t *testing.T) {
 	orig3Int := [][][]int{{{1, 2, 3}, {11, 22, 33}}, {{7, 8, 9}, {66, 77, 88, 99}}}
@@ -858,6 +963,49 @@ func TestCopyNilValues(t *testing.T) {
 	}
 }
 
+// NilUnsafeCopier 的 DeepCopy 不处理 nil 接收者，nil 指针不应调用它
+type NilUnsafeCopier struct{ Value int }
+
+func (c *NilUnsafeCopier) DeepCopy() *NilUnsafeCopier {
+	return &NilUnsafeCopier{Value: c.Value}
+}
+
+// 接口中保存的带类型 nil 指针拷贝后仍是同类型的 nil 指针，而不是 nil 接口
+func TestCopyTypedNilInInterface(t *testing.T) {
+	type holder struct {
+		Value  any
+		Values []any
+		ByKey  map[string]any
+	}
+
+	typedNils := []any{(*TestStruct)(nil), (*NilUnsafeCopier)(nil)}
+	for _, typedNil := range typedNils {
+		check := func(name string, got any) {
+			t.Helper()
+			if got == nil {
+				t.Errorf("%T %s: typed nil collapsed to nil interface", typedNil, name)
+				return
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(typedNil) || !reflect.ValueOf(got).IsNil() {
+				t.Errorf("%T %s: got %#v", typedNil, name, got)
+			}
+		}
+
+		check("top level", Copy(typedNil))
+		copied, err := CopyE(holder{
+			Value:  typedNil,
+			Values: []any{typedNil},
+			ByKey:  map[string]any{"k": typedNil},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check("field", copied.Value)
+		check("slice element", copied.Values[0])
+		check("map value", copied.ByKey["k"])
+	}
+}
+
 func TestCopyCircularReference(t *testing.T) {
 	type Node struct {
 		Next  *Node
@@ -905,6 +1053,77 @@ func TestCustomCopierInterface(t *testing.T) {
 	}
 }
 
+// Doubler 方法集与 CustomCopier 的 DeepCopy 相同，嵌入后 DeepCopy 被提升到外层结构体
+type Doubler interface {
+	DeepCopy() CustomCopier
+}
+
+// DoublerHolder 嵌入接口，其中保存 CustomCopier
+type DoublerHolder struct {
+	Doubler
+	Name string
+}
+
+// DoublerOuter 多层嵌入
+type DoublerOuter struct {
+	DoublerHolder
+	Next *DoublerHolder
+	Any  any
+}
+
+// 嵌入接口中的具体值使用自身的 DeepCopy，提升到外层的方法不用于拷贝外层结构体
+func TestCopyEmbeddedInterfaceCopier(t *testing.T) {
+	original := DoublerHolder{Doubler: CustomCopier{Value: 21}, Name: "holder"}
+	copied := Copy(original)
+	if copied.Name != "holder" {
+		t.Errorf("Name = %q, want holder", copied.Name)
+	}
+	if got := copied.Doubler.(CustomCopier).Value; got != 42 {
+		t.Errorf("embedded Value = %d, want 42", got)
+	}
+
+	outer := DoublerOuter{
+		DoublerHolder: original,
+		Next:          &DoublerHolder{Doubler: CustomCopier{Value: 1}},
+		Any:           DoublerHolder{Doubler: CustomCopier{Value: 5}},
+	}
+	copiedOuter := Copy(outer)
+	if got := copiedOuter.Doubler.(CustomCopier).Value; got != 42 {
+		t.Errorf("outer embedded Value = %d, want 42", got)
+	}
+	if copiedOuter.Next == outer.Next {
+		t.Error("Next should be a new pointer")
+	}
+	if got := copiedOuter.Next.Doubler.(CustomCopier).Value; got != 2 {
+		t.Errorf("Next embedded Value = %d, want 2", got)
+	}
+	if got := copiedOuter.Any.(DoublerHolder).Doubler.(CustomCopier).Value; got != 10 {
+		t.Errorf("Any embedded Value = %d, want 10", got)
+	}
+	if AnalyzeType(DoublerOuter{}).HasDeepCopyMethod {
+		t.Error("promoted DeepCopy should not count as the struct's own method")
+	}
+}
+
+// DeepCopy 方法在类型分析时记录，值类型快速路径不再逐次查找
+func TestAnalysisHasDeepCopyMethod(t *testing.T) {
+	if !AnalyzeType(CustomCopier{}).HasDeepCopyMethod {
+		t.Error("CustomCopier should have DeepCopy method")
+	}
+	if !AnalyzeType(&CustomCopier{}).HasDeepCopyMethod {
+		t.Error("*CustomCopier should have DeepCopy method")
+	}
+	if AnalyzeType(OnlyValueStruct{}).HasDeepCopyMethod {
+		t.Error("OnlyValueStruct should not have DeepCopy method")
+	}
+
+	// T 为接口时按动态类型调用 DeepCopy
+	copied := Copy[any](CustomCopier{Value: 1})
+	if copied.(CustomCopier).Value != 2 {
+		t.Errorf("DeepCopy not used through interface: %v", copied)
+	}
+}
+
 // 嵌套结构体测试
 func TestNestedStructCopy(t *testing.T) {
 	now := time.Now()
@@ -1036,3 +1255,619 @@ type Node struct {
 	Next  *Node
 	Value int
 }
+
+type ReflectHolder struct {
+	Name  string
+	Type  reflect.Type
+	Value reflect.Value
+	Any   interface{}
+}
+
+func TestReflectTypeAndValueShared(t *testing.T) {
+	original := ReflectHolder{
+		Name:  "meta",
+		Type:  reflect.TypeOf(Basics{}),
+		Value: reflect.ValueOf(42),
+		Any:   reflect.TypeOf(""),
+	}
+
+	copied := Copy(original)
+	if copied.Type != original.Type {
+		t.Errorf("Type: got %v, want %v", copied.Type, original.Type)
+	}
+	if copied.Any != original.Any {
+		t.Errorf("Any: got %v, want %v", copied.Any, original.Any)
+	}
+	if !copied.Value.IsValid() || copied.Value.Int() != 42 {
+		t.Errorf("Value: got %v, want 42", copied.Value)
+	}
+
+	copiedPtr := Copy(&original)
+	if copiedPtr.Type != original.Type {
+		t.Errorf("Type through pointer: got %v, want %v", copiedPtr.Type, original.Type)
+	}
+}
+
+// Temperature 带 DeepCopy 方法的命名基本类型，拷贝时保留一位小数
+type Temperature float64
+
+func (t Temperature) DeepCopy() Temperature {
+	return Temperature(math.Round(float64(t)*10) / 10)
+}
+
+type IntSlice []int
+
+type MyMap map[string]int
+
+type Reading struct {
+	Temp    Temperature
+	Samples IntSlice
+}
+
+func TestNamedBasicTypes(t *testing.T) {
+	if got := Copy(Temperature(21.46)); got != 21.5 {
+		t.Errorf("Copy[Temperature]: got %v, want 21.5", got)
+	}
+
+	// 作为字段时同样使用 DeepCopy
+	reading := Copy(Reading{Temp: 18.04, Samples: IntSlice{1, 2}})
+	if reading.Temp != 18 {
+		t.Errorf("Reading.Temp: got %v, want 18", reading.Temp)
+	}
+
+	// 命名切片和映射按底层类型深拷贝
+	slice := IntSlice{1, 2, 3}
+	copiedSlice := Copy(slice)
+	copiedSlice[0] = 100
+	if slice[0] != 1 {
+		t.Error("IntSlice copy should not share the backing array")
+	}
+
+	m := MyMap{"a": 1}
+	copiedMap := Copy(m)
+	copiedMap["a"] = 100
+	if m["a"] != 1 {
+		t.Error("MyMap copy should not share the map")
+	}
+}
+
+func TestCopyMapPresized(t *testing.T) {
+	var nilMap map[string]int
+	if Copy(nilMap) != nil {
+		t.Error("nil map should remain nil")
+	}
+
+	empty := map[string]int{}
+	copiedEmpty := Copy(empty)
+	if copiedEmpty == nil || len(copiedEmpty) != 0 {
+		t.Error("empty map should copy to an empty non-nil map")
+	}
+
+	large := make(map[int]int, 1000)
+	for i := 0; i < 1000; i++ {
+		large[i] = i * i
+	}
+	copiedLarge := Copy(large)
+	if !reflect.DeepEqual(copiedLarge, large) {
+		t.Error("large map should be copied entirely")
+	}
+}
+
+// 看起来只包含值类型，但字段自定义了 DeepCopy
+type ValueLookingStruct struct {
+	Name   string
+	Copier CustomCopier
+	Pair   [2]CustomCopier
+}
+
+func TestNestedCopierNotSkippedByFastPath(t *testing.T) {
+	if AnalyzeType(CustomCopier{}).IsOnlyValues {
+		t.Error("CustomCopier should not be value-only")
+	}
+	if AnalyzeType(ValueLookingStruct{}).IsOnlyValues {
+		t.Error("struct containing a CustomCopier should not be value-only")
+	}
+
+	original := ValueLookingStruct{
+		Name:   "outer",
+		Copier: CustomCopier{Value: 3},
+		Pair:   [2]CustomCopier{{Value: 1}, {Value: 2}},
+	}
+	copied := Copy(original)
+
+	if copied.Copier.Value != 6 {
+		t.Errorf("Copier.Value: got %d, want 6", copied.Copier.Value)
+	}
+	if copied.Pair[0].Value != 2 || copied.Pair[1].Value != 4 {
+		t.Errorf("Pair: got %+v, want [{2} {4}]", copied.Pair)
+	}
+	if copied.Name != "outer" {
+		t.Errorf("Name: got %q, want %q", copied.Name, "outer")
+	}
+}
+
+// opaqueID 只有未导出字段，通过 MarshalText/UnmarshalText 暴露其状态
+type opaqueID struct {
+	prefix string
+	seq    []int
+}
+
+func (o opaqueID) MarshalText() ([]byte, error) {
+	parts := []string{o.prefix}
+	for _, n := range o.seq {
+		parts = append(parts, strconv.Itoa(n))
+	}
+	return []byte(strings.Join(parts, ":")), nil
+}
+
+func (o *opaqueID) UnmarshalText(data []byte) error {
+	parts := strings.Split(string(data), ":")
+	o.prefix, o.seq = parts[0], nil
+	for _, p := range parts[1:] {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return err
+		}
+		o.seq = append(o.seq, n)
+	}
+	return nil
+}
+
+type MarshalerHolder struct {
+	IP  net.IP
+	ID  opaqueID
+	URL *url.URL
+}
+
+func TestCopyViaMarshaler(t *testing.T) {
+	analysis := AnalyzeType(net.IP{})
+	if !analysis.ImplementsTextMarshaler || analysis.ImplementsBinaryMarshaler {
+		t.Errorf("net.IP: text=%v binary=%v, want true/false", analysis.ImplementsTextMarshaler, analysis.ImplementsBinaryMarshaler)
+	}
+	if !AnalyzeType(url.URL{}).ImplementsBinaryMarshaler {
+		t.Error("url.URL should implement BinaryMarshaler")
+	}
+
+	original := MarshalerHolder{
+		IP:  net.ParseIP("192.168.1.10"),
+		ID:  opaqueID{prefix: "user", seq: []int{1, 2}},
+		URL: &url.URL{Scheme: "https", User: url.UserPassword("u", "p"), Host: "example.com", Path: "/a"},
+	}
+	copied := Copy(original)
+
+	if !copied.IP.Equal(original.IP) {
+		t.Errorf("IP: got %v, want %v", copied.IP, original.IP)
+	}
+	copied.IP[len(copied.IP)-1] = 99
+	if original.IP.String() != "192.168.1.10" {
+		t.Error("modifying the copied IP should not affect the original")
+	}
+
+	if copied.ID.prefix != "user" || !reflect.DeepEqual(copied.ID.seq, []int{1, 2}) {
+		t.Errorf("ID: got %+v, want unexported state preserved", copied.ID)
+	}
+	if &copied.ID.seq[0] == &original.ID.seq[0] {
+		t.Error("ID state should not be shared")
+	}
+
+	if copied.URL == original.URL || copied.URL.String() != original.URL.String() {
+		t.Errorf("URL: got %v, want an independent copy of %v", copied.URL, original.URL)
+	}
+}
+
+// Measurement 的字段全部导出，MarshalText 只保留数值
+type Measurement struct {
+	Degrees float64
+	Unit    string
+}
+
+func (c Measurement) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatFloat(c.Degrees, 'f', -1, 64)), nil
+}
+
+func (c *Measurement) UnmarshalText(data []byte) error {
+	d, err := strconv.ParseFloat(string(data), 64)
+	c.Degrees, c.Unit = d, ""
+	return err
+}
+
+// 不含未导出字段的类型逐字段拷贝，不经过序列化往返
+func TestCopyViaMarshalerOnlyUnexportedState(t *testing.T) {
+	if AnalyzeType(net.IP{}).viaMarshaler || AnalyzeType(Measurement{}).viaMarshaler {
+		t.Error("types without unexported fields should not be copied via marshaler")
+	}
+	if !AnalyzeType(url.URL{}).viaMarshaler {
+		t.Error("url.URL holds unexported state in Userinfo and should be copied via marshaler")
+	}
+
+	copied := Copy([]Measurement{{Degrees: 21.5, Unit: "C"}})
+	if copied[0].Unit != "C" || copied[0].Degrees != 21.5 {
+		t.Errorf("got %+v, want fields copied as is", copied[0])
+	}
+}
+
+func TestCopyOrDefault(t *testing.T) {
+	defaultValue := Snapshot{Values: []int{1, 2}, Index: map[string]int{"a": 1}}
+
+	copied := CopyOrDefault(nil, defaultValue)
+	if !reflect.DeepEqual(copied, defaultValue) {
+		t.Fatalf("got %+v, want %+v", copied, defaultValue)
+	}
+	copied.Values[0] = 100
+	copied.Index["a"] = 100
+	if defaultValue.Values[0] != 1 || defaultValue.Index["a"] != 1 {
+		t.Error("nil input should return a copy of the default, not the default itself")
+	}
+
+	src := &Snapshot{Values: []int{3}}
+	copied = CopyOrDefault(src, defaultValue)
+	if copied.Values[0] != 3 || copied.Index != nil {
+		t.Fatalf("unexpected copy %+v", copied)
+	}
+	copied.Values[0] = 100
+	if src.Values[0] != 3 {
+		t.Error("non-nil input should be deep copied")
+	}
+}
+
+func TestCopyOrZero(t *testing.T) {
+	if copied := CopyOrZero[TestStruct](nil); !reflect.DeepEqual(copied, TestStruct{}) {
+		t.Errorf("expected zero value, got %+v", copied)
+	}
+	if copied := CopyOrZero(&TestStruct{Int: 5}); copied.Int != 5 {
+		t.Errorf("unexpected copy %+v", copied)
+	}
+}
+
+func TestCopyPointerToArray(t *testing.T) {
+	original := &[3]int{1, 2, 3}
+	copied := Copy(original)
+	if copied == original || *copied != *original {
+		t.Fatalf("expected an independent copy, got %p %v", copied, *copied)
+	}
+	copied[0] = 100
+	if original[0] != 1 {
+		t.Error("modifying the copied array should not affect the original")
+	}
+
+	if Copy((*[3]int)(nil)) != nil {
+		t.Error("nil pointer to array should stay nil")
+	}
+
+	// 数组元素为引用类型时同样深拷贝
+	withSlices := &[2][]int{{1}, {2}}
+	copiedSlices := Copy(withSlices)
+	copiedSlices[0][0] = 100
+	if withSlices[0][0] != 1 {
+		t.Error("slices inside the pointed-to array should be deep copied")
+	}
+}
+
+func TestCopyPointerToMap(t *testing.T) {
+	original := &map[string]int{"a": 1}
+	copied := Copy(original)
+	if copied == original || !reflect.DeepEqual(*copied, *original) {
+		t.Fatalf("expected an independent copy, got %p %v", copied, *copied)
+	}
+	(*copied)["a"] = 100
+	(*copied)["b"] = 2
+	if (*original)["a"] != 1 || len(*original) != 1 {
+		t.Error("the pointed-to map should be allocated independently")
+	}
+
+	if Copy((*map[string]int)(nil)) != nil {
+		t.Error("nil pointer to map should stay nil")
+	}
+	nilMap := new(map[string]int)
+	if copiedNil := Copy(nilMap); copiedNil == nilMap || *copiedNil != nil {
+		t.Error("pointer to a nil map should copy to a new pointer to a nil map")
+	}
+
+	// 经由指针引用自身的映射
+	self := map[string]any{}
+	self["self"] = &self
+	copiedSelf := Copy(&self)
+	if (*copiedSelf)["self"].(*map[string]any) != copiedSelf {
+		t.Error("cycle through the map pointer should point to the copy")
+	}
+}
+
+func TestCloneNew(t *testing.T) {
+	src := Snapshot{Values: []int{1, 2}, Rows: [][]string{{"a"}}, Index: map[string]int{"a": 1}}
+	copied := CloneNew(src)
+	if !reflect.DeepEqual(*copied, src) {
+		t.Fatalf("got %+v, want %+v", *copied, src)
+	}
+	copied.Values[0] = 100
+	copied.Rows[0][0] = "changed"
+	copied.Index["a"] = 100
+	if src.Values[0] != 1 || src.Rows[0][0] != "a" || src.Index["a"] != 1 {
+		t.Error("CloneNew should deep copy")
+	}
+
+	// 快速路径、DeepCopy 方法和接口类型参数与 Copy 的行为一致
+	if p := CloneNew(OnlyValueStruct{Name: "x", Age: 1}); p.Name != "x" || p.Age != 1 {
+		t.Errorf("unexpected copy %+v", *p)
+	}
+	if p := CloneNew(CustomCopyStruct{Value: 1}); p.Value != 101 {
+		t.Errorf("DeepCopy method should be used, got %d", p.Value)
+	}
+	var iface any = CustomCopyStruct{Value: 2}
+	if p := CloneNew(iface); (*p).(CustomCopyStruct).Value != 102 {
+		t.Errorf("DeepCopy method of the dynamic type should be used, got %+v", *p)
+	}
+}
+
+func TestClonePtr(t *testing.T) {
+	if ClonePtr[Snapshot](nil) != nil {
+		t.Error("nil input should return nil")
+	}
+
+	src := &Snapshot{Values: []int{1}}
+	copied := ClonePtr(src)
+	if copied == src || !reflect.DeepEqual(copied, src) {
+		t.Fatalf("expected an independent copy, got %p %+v", copied, copied)
+	}
+	copied.Values[0] = 100
+	if src.Values[0] != 1 {
+		t.Error("ClonePtr should deep copy")
+	}
+}
+
+func TestMustBeCopyable(t *testing.T) {
+	if !MustBeCopyable[Snapshot]() {
+		t.Error("Snapshot should be copyable")
+	}
+
+	type withChan struct {
+		Name   string
+		Events chan int
+	}
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic for type with channel field")
+		}
+		if msg := fmt.Sprint(r); !strings.Contains(msg, "chan") {
+			t.Errorf("panic message should mention chan: %s", msg)
+		}
+	}()
+	MustBeCopyable[withChan]()
+}
+
+// 带通道的工作池，用于测试通道路径
+type WorkerPool struct {
+	Name    string
+	Workers []Worker
+	Control *struct {
+		Stop chan struct{}
+	}
+	Results map[string]chan int
+}
+
+type Worker struct {
+	ID   int
+	Done chan bool
+	Next *Worker
+}
+
+func TestValidateNoChannels(t *testing.T) {
+	if err := ValidateNoChannels[Snapshot](); err != nil {
+		t.Errorf("Snapshot has no channels, got %v", err)
+	}
+
+	err := ValidateNoChannels[WorkerPool]()
+	if !errors.Is(err, ErrContainsChan) {
+		t.Fatalf("expected ErrContainsChan, got %v", err)
+	}
+	for _, path := range []string{"Workers[].Done", "Control.Stop", "Results[]"} {
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("error should name %s: %v", path, err)
+		}
+	}
+
+	analysis := AnalyzeType(Worker{})
+	if !reflect.DeepEqual(analysis.ChanPaths, []string{"Done"}) {
+		t.Errorf("ChanPaths: got %v, want [Done]", analysis.ChanPaths)
+	}
+	if err := ValidateNoChannels[chan int](); err == nil || !strings.Contains(err.Error(), "(root)") {
+		t.Errorf("a channel type itself should be reported at the root, got %v", err)
+	}
+}
+
+// netip 的类型只有未导出字段，按值复制即可得到独立副本
+type NetipHolder struct {
+	Addr   netip.Addr
+	Port   netip.AddrPort
+	Prefix netip.Prefix
+	Peers  []netip.Addr
+}
+
+func TestCopyNetip(t *testing.T) {
+	for _, v := range []any{netip.Addr{}, netip.AddrPort{}, netip.Prefix{}} {
+		if !AnalyzeType(v).IsOnlyValues {
+			t.Errorf("%T should be value-only", v)
+		}
+	}
+
+	addr := netip.MustParseAddr("fe80::1%eth0")
+	if copied := Copy(addr); copied != addr || copied.Zone() != "eth0" {
+		t.Errorf("Copy(netip.Addr) = %v, want %v", copied, addr)
+	}
+	addrPort := netip.MustParseAddrPort("[2001:db8::1]:443")
+	if copied := Copy(addrPort); copied != addrPort {
+		t.Errorf("Copy(netip.AddrPort) = %v, want %v", copied, addrPort)
+	}
+	prefix := netip.MustParsePrefix("10.0.0.0/8")
+	if copied := Copy(prefix); copied != prefix {
+		t.Errorf("Copy(netip.Prefix) = %v, want %v", copied, prefix)
+	}
+
+	original := NetipHolder{Addr: addr, Port: addrPort, Prefix: prefix, Peers: []netip.Addr{addr}}
+	copied := Copy(original)
+	if !reflect.DeepEqual(copied, original) {
+		t.Fatalf("got %+v, want %+v", copied, original)
+	}
+	copied.Peers[0] = netip.MustParseAddr("127.0.0.1")
+	if original.Peers[0] != addr {
+		t.Error("copy should be independent of the original")
+	}
+}
+
+func TestCopyValueStructSliceBulk(t *testing.T) {
+	original := []OnlyValueStruct{{Name: "a", Age: 1}, {Name: "b", Age: 2}}
+	copied := Copy(original)
+	if !reflect.DeepEqual(copied, original) {
+		t.Fatalf("got %+v, want %+v", copied, original)
+	}
+	copied[0].Age = 100
+	if original[0].Age != 1 {
+		t.Error("bulk copy should not share the backing array")
+	}
+
+	// 自定义了 DeepCopy 的元素不能整体复制
+	custom := Copy([]CustomCopier{{Value: 1}, {Value: 2}})
+	if custom[0].Value != 2 || custom[1].Value != 4 {
+		t.Errorf("DeepCopy of elements should be used, got %+v", custom)
+	}
+}
+
+// ScalarTags 元素为基础类型的命名切片
+type ScalarTags []string
+
+// DoubledInts 自定义了 DeepCopy 的切片，不能直接复制元素
+type DoubledInts []int
+
+func (d DoubledInts) DeepCopy() DoubledInts {
+	c := make(DoubledInts, len(d))
+	for i, v := range d {
+		c[i] = v * 2
+	}
+	return c
+}
+
+func TestCopyScalarSlice(t *testing.T) {
+	original := make(ScalarTags, 2, 5)
+	original[0], original[1] = "a", "b"
+	copied := Copy(original)
+	if !reflect.DeepEqual(copied, original) || cap(copied) != 5 {
+		t.Fatalf("got %v (cap %d), want %v (cap 5)", copied, cap(copied), original)
+	}
+	copied[0] = "changed"
+	if original[0] != "a" {
+		t.Error("copy should not share the backing array")
+	}
+
+	if Copy([]float64(nil)) != nil {
+		t.Error("nil slice should stay nil")
+	}
+	if c := Copy([]complex128{}); c == nil || len(c) != 0 {
+		t.Errorf("empty slice should stay empty and non-nil, got %#v", c)
+	}
+	if c := Copy([]uintptr{1, 2}); !reflect.DeepEqual(c, []uintptr{1, 2}) {
+		t.Errorf("got %v", c)
+	}
+
+	// 切片类型自己的 DeepCopy 优先于直接复制
+	if c := Copy(DoubledInts{1, 2}); !reflect.DeepEqual(c, DoubledInts{2, 4}) {
+		t.Errorf("DeepCopy of the slice should be used, got %v", c)
+	}
+
+	SetDisableFastPath(true)
+	defer SetDisableFastPath(false)
+	if c := Copy([]int{1, 2}); !reflect.DeepEqual(c, []int{1, 2}) {
+		t.Errorf("without fast path: got %v", c)
+	}
+}
+
+// checkValueOnlyCopy 检查只包含值类型的 T 走快速路径且拷贝结果相等
+func checkValueOnlyCopy[T any](t *testing.T, v T) {
+	t.Helper()
+	if !AnalyzeType(v).IsOnlyValues {
+		t.Errorf("%T should be value-only", v)
+	}
+	if copied := Copy(v); !reflect.DeepEqual(copied, v) {
+		t.Errorf("Copy(%T) = %+v, want %+v", v, copied, v)
+	}
+}
+
+func TestCopySQLNullTypes(t *testing.T) {
+	now := time.Now()
+	checkValueOnlyCopy(t, sql.NullString{String: "s", Valid: true})
+	checkValueOnlyCopy(t, sql.NullInt64{Int64: 64, Valid: true})
+	checkValueOnlyCopy(t, sql.NullInt32{Int32: 32, Valid: true})
+	checkValueOnlyCopy(t, sql.NullInt16{Int16: 16, Valid: true})
+	checkValueOnlyCopy(t, sql.NullByte{Byte: 8, Valid: true})
+	checkValueOnlyCopy(t, sql.NullFloat64{Float64: 1.5, Valid: true})
+	checkValueOnlyCopy(t, sql.NullBool{Bool: true, Valid: true})
+	checkValueOnlyCopy(t, sql.NullTime{Time: now, Valid: true})
+
+	// 作为结构体字段时 NullTime 中的 time.Time 仍通过时间类型特殊处理
+	type row struct {
+		Name      sql.NullString
+		UpdatedAt sql.NullTime
+		Tags      []string
+	}
+	original := row{Name: sql.NullString{String: "n", Valid: true}, UpdatedAt: sql.NullTime{Time: now, Valid: true}, Tags: []string{"a"}}
+	copied := Copy(original)
+	if !copied.UpdatedAt.Time.Equal(now) || copied.UpdatedAt.Time.Location() != now.Location() || !copied.UpdatedAt.Valid {
+		t.Errorf("NullTime not copied correctly: %+v", copied.UpdatedAt)
+	}
+	if copied.Name != original.Name {
+		t.Errorf("NullString not copied correctly: %+v", copied.Name)
+	}
+}
+
+func TestCopyValueStructMapDirect(t *testing.T) {
+	original := map[string]OnlyValueStruct{"a": {Name: "a", Age: 1}}
+	copied := Copy(original)
+
+	original["a"] = OnlyValueStruct{Name: "changed"}
+	original["b"] = OnlyValueStruct{Name: "b"}
+	if len(copied) != 1 || copied["a"].Name != "a" || copied["a"].Age != 1 {
+		t.Errorf("copy should not be affected by later mutation: %+v", copied)
+	}
+
+	// 值含引用类型时仍逐个深拷贝
+	nested := map[OnlyValueStruct][]int{{Name: "k"}: {1}}
+	copiedNested := Copy(nested)
+	copiedNested[OnlyValueStruct{Name: "k"}][0] = 100
+	if nested[OnlyValueStruct{Name: "k"}][0] != 1 {
+		t.Error("slice values should still be deep copied")
+	}
+}
+
+// T 为接口类型时，按动态类型深拷贝而不是直接返回原值
+func TestCopyInterfaceTypeParam(t *testing.T) {
+	node := &Node{Value: 1}
+	node.Next = &Node{Value: 2, Next: node}
+	var src interface{} = node
+
+	copied, ok := Copy[any](src).(*Node)
+	if !ok {
+		t.Fatalf("expected *Node, got %T", Copy[any](src))
+	}
+	if copied == node || copied.Next == node.Next {
+		t.Error("Copy[any] should deep copy the concrete value")
+	}
+	if copied.Next.Next != copied || copied.Next.Value != 2 {
+		t.Error("cycle should be preserved in the copy")
+	}
+
+	slice := []int{1, 2}
+	copiedSlice := Copy[any](slice).([]int)
+	copiedSlice[0] = 100
+	if slice[0] != 1 {
+		t.Error("Copy[any] should not share the slice backing array")
+	}
+
+	if copiedE, err := CopyE[any](src); err != nil || copiedE.(*Node) == node {
+		t.Errorf("CopyE[any] should deep copy, got %v, %v", copiedE, err)
+	}
+	if CopyWithKey[any](src, "interface.node").(*Node) == node {
+		t.Error("CopyWithKey[any] should deep copy")
+	}
+	if Copy[any](nil) != nil {
+		t.Error("Copy[any](nil) should return nil")
+	}
+}
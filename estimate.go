@@ -0,0 +1,104 @@
+package deepcopy
+
+import "reflect"
+
+// EstimateCopySize 估算 Copy(src) 分配的字节数：src 本身的大小，加上指针指向的值、切片的底层数组（按容量）、
+// 映射的条目（按键和值的大小，不含哈希表的额外开销）以及接口中的动态值。与 Copy 一致，同一个指针只计一次，
+// 不计入未导出字段和脱敏字段引用的内存；字符串内容和 time.Time 等不可变值被副本共享，也不计入。
+// 实现了 DeepCopy 方法或注册了自定义拷贝函数的值按其结构估算。只遍历 src，不分配副本，
+// 用于在拷贝大对象图之前决定是否拷贝
+func EstimateCopySize[T any](src T) int {
+	v := reflect.ValueOf(&src).Elem()
+	e := &sizeEstimator{manager: defaultManager, visited: make(map[refKey]bool)}
+	return int(v.Type().Size() + e.indirect(v))
+}
+
+// sizeEstimator 累计值引用的、拷贝时需要另外分配的内存
+type sizeEstimator struct {
+	manager *DeepCopyManager
+	visited map[refKey]bool
+}
+
+// seen 记录引用，已计入过时返回 true
+func (e *sizeEstimator) seen(key refKey) bool {
+	if e.visited[key] {
+		return true
+	}
+	e.visited[key] = true
+	return false
+}
+
+// valueOnly 类型为 t 的值是否不引用其他内存，无需逐个元素遍历
+func (e *sizeEstimator) valueOnly(t reflect.Type) bool {
+	return e.manager.getOrAnalyzeType(t).IsOnlyValues
+}
+
+// indirect 返回 v 引用的内存大小，不含 v 本身
+func (e *sizeEstimator) indirect(v reflect.Value) uintptr {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || isHandleType(v.Type()) || e.seen(refKey{ptr: v.Pointer(), typ: v.Type()}) {
+			return 0
+		}
+		return v.Type().Elem().Size() + e.indirect(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() || v.Type() == netConnType || v.Elem().Type().Implements(reflectTypeType) {
+			return 0
+		}
+		return v.Elem().Type().Size() + e.indirect(v.Elem())
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+		// 与 Copy 一致，只有可能构成循环的切片才按同一底层数组去重
+		key := refKey{ptr: v.Pointer(), typ: v.Type(), len: v.Len(), cap: v.Cap()}
+		if mayFormCycle(v.Type()) && e.seen(key) {
+			return 0
+		}
+		return uintptr(v.Cap())*v.Type().Elem().Size() + e.elems(v)
+
+	case reflect.Array:
+		return e.elems(v)
+
+	case reflect.Map:
+		if v.IsNil() || mayFormCycle(v.Type()) && e.seen(refKey{ptr: v.Pointer(), typ: v.Type()}) {
+			return 0
+		}
+		t := v.Type()
+		size := uintptr(v.Len()) * (t.Key().Size() + t.Elem().Size())
+		if e.valueOnly(t.Key()) && e.valueOnly(t.Elem()) {
+			return size
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			size += e.indirect(iter.Key()) + e.indirect(iter.Value())
+		}
+		return size
+
+	case reflect.Struct:
+		t := v.Type()
+		if t == reflectValueType || immutableValueTypes[t] {
+			return 0
+		}
+		var size uintptr
+		for _, i := range e.manager.getOrAnalyzeType(t).ExportedFieldIndices {
+			size += e.indirect(v.Field(i))
+		}
+		return size
+	}
+	return 0
+}
+
+// elems 切片或数组的元素引用的内存大小
+func (e *sizeEstimator) elems(v reflect.Value) uintptr {
+	if e.valueOnly(v.Type().Elem()) {
+		return 0
+	}
+	var size uintptr
+	for i := 0; i < v.Len(); i++ {
+		size += e.indirect(v.Index(i))
+	}
+	return size
+}
@@ -0,0 +1,58 @@
+package deepcopy
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// EstimateBlob 包含字节切片的结构体
+type EstimateBlob struct {
+	ID      int
+	Name    string
+	Payload []byte
+	Shared  *EstimateChunk
+	Again   *EstimateChunk
+	Meta    map[string]int
+	secret  []byte
+}
+
+type EstimateChunk struct {
+	Data [64]byte
+}
+
+func TestEstimateCopySize(t *testing.T) {
+	chunk := &EstimateChunk{}
+	blob := EstimateBlob{
+		ID:      1,
+		Name:    "a fairly long name that the copy shares",
+		Payload: make([]byte, 100, 1024),
+		Shared:  chunk,
+		Again:   chunk, // 同一个指针只计一次
+		Meta:    map[string]int{"a": 1, "b": 2},
+		secret:  make([]byte, 1<<20), // 未导出字段不会被拷贝
+	}
+
+	structSize := int(unsafe.Sizeof(blob))
+	entry := int(unsafe.Sizeof("") + unsafe.Sizeof(0))
+	want := structSize + 1024 + 64 + 2*entry
+	if got := EstimateCopySize(blob); got != want {
+		t.Errorf("EstimateCopySize = %d, want %d", got, want)
+	}
+
+	// 指针多占指向的结构体本身
+	if got := EstimateCopySize(&blob); got != int(unsafe.Sizeof(&blob))+want {
+		t.Errorf("EstimateCopySize(pointer) = %d, want %d", got, int(unsafe.Sizeof(&blob))+want)
+	}
+
+	// 循环引用能结束，每个节点只计一次
+	type node struct {
+		Next *node
+		Buf  []byte
+	}
+	a := &node{Buf: make([]byte, 10)}
+	a.Next = &node{Next: a}
+	want = int(unsafe.Sizeof(a)) + 2*int(unsafe.Sizeof(node{})) + 10
+	if got := EstimateCopySize(a); got != want {
+		t.Errorf("cyclic: got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package deepcopy
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// cloneValueSlice 复制元素为基础类型的切片 src：按元素的种类把 src 视为 []E（命名类型与其底层类型内存布局相同），
+// 用 copy 复制元素。元素不是基础类型（如只包含值类型的结构体）时返回 false，由反射路径整体复制
+func cloneValueSlice[T any](src T, elem reflect.Kind) (T, bool) {
+	switch elem {
+	case reflect.String:
+		return cloneSliceAs[T, string](src), true
+	case reflect.Bool:
+		return cloneSliceAs[T, bool](src), true
+	case reflect.Int:
+		return cloneSliceAs[T, int](src), true
+	case reflect.Int8:
+		return cloneSliceAs[T, int8](src), true
+	case reflect.Int16:
+		return cloneSliceAs[T, int16](src), true
+	case reflect.Int32:
+		return cloneSliceAs[T, int32](src), true
+	case reflect.Int64:
+		return cloneSliceAs[T, int64](src), true
+	case reflect.Uint:
+		return cloneSliceAs[T, uint](src), true
+	case reflect.Uint8:
+		return cloneSliceAs[T, uint8](src), true
+	case reflect.Uint16:
+		return cloneSliceAs[T, uint16](src), true
+	case reflect.Uint32:
+		return cloneSliceAs[T, uint32](src), true
+	case reflect.Uint64:
+		return cloneSliceAs[T, uint64](src), true
+	case reflect.Uintptr:
+		return cloneSliceAs[T, uintptr](src), true
+	case reflect.Float32:
+		return cloneSliceAs[T, float32](src), true
+	case reflect.Float64:
+		return cloneSliceAs[T, float64](src), true
+	case reflect.Complex64:
+		return cloneSliceAs[T, complex64](src), true
+	case reflect.Complex128:
+		return cloneSliceAs[T, complex128](src), true
+	}
+	return src, false
+}
+
+// cloneSliceAs 把底层元素类型为 E 的切片 src 复制为新切片，nil 保持为 nil，长度和容量与原切片相同
+func cloneSliceAs[T, E any](src T) T {
+	s := *(*[]E)(unsafe.Pointer(&src))
+	if s == nil {
+		return src
+	}
+	c := make([]E, len(s), cap(s))
+	copy(c, s)
+	return *(*T)(unsafe.Pointer(&c))
+}
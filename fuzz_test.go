@@ -0,0 +1,254 @@
+package deepcopy
+
+import (
+	"reflect"
+	"testing"
+)
+
+// FuzzLeaf / FuzzTree FuzzCopy 从输入字节生成的值的类型，字段都能经 JSON 往返还原
+type FuzzLeaf struct {
+	Name  string
+	N     int
+	F     float64
+	Tags  []string
+	Attrs map[string]int
+}
+
+type FuzzTree struct {
+	Leaf   FuzzLeaf
+	Ptr    *FuzzLeaf
+	Kids   []*FuzzTree
+	ByName map[string]FuzzLeaf
+	Arr    [2]int
+	Bytes  []byte
+	Any    any // 只包含 JSON 解码得到的类型
+}
+
+// fuzzGen 按顺序读取输入字节做出选择，读完后总是返回 0，生成的值随之收敛为零值
+type fuzzGen struct {
+	data   []byte
+	leaves []*FuzzLeaf // 已生成的指针，用于制造共享
+}
+
+func (g *fuzzGen) byte() byte {
+	if len(g.data) == 0 {
+		return 0
+	}
+	b := g.data[0]
+	g.data = g.data[1:]
+	return b
+}
+
+// str 只使用固定字符，避免无效的 UTF-8 在 JSON 往返中被替换
+func (g *fuzzGen) str() string {
+	const alphabet = "abcxyz-_ 0"
+	n := g.byte() % 5
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphabet[int(g.byte())%len(alphabet)]
+	}
+	return string(b)
+}
+
+// count 返回 -1 表示 nil，其余为长度
+func (g *fuzzGen) count() int {
+	return int(g.byte()%5) - 1
+}
+
+func (g *fuzzGen) leaf() FuzzLeaf {
+	l := FuzzLeaf{Name: g.str(), N: int(int8(g.byte())), F: float64(int8(g.byte())) / 4}
+	if n := g.count(); n >= 0 {
+		l.Tags = make([]string, n)
+		for i := range l.Tags {
+			l.Tags[i] = g.str()
+		}
+	}
+	if n := g.count(); n >= 0 {
+		l.Attrs = make(map[string]int, n)
+		for i := 0; i < n; i++ {
+			l.Attrs[g.str()] = int(g.byte())
+		}
+	}
+	return l
+}
+
+func (g *fuzzGen) leafPtr() *FuzzLeaf {
+	switch b := g.byte(); {
+	case b%3 == 0:
+		return nil
+	case b%3 == 1 && len(g.leaves) > 0:
+		return g.leaves[int(b)%len(g.leaves)]
+	}
+	l := g.leaf()
+	g.leaves = append(g.leaves, &l)
+	return &l
+}
+
+func (g *fuzzGen) jsonValue(depth int) any {
+	switch g.byte() % 6 {
+	case 1:
+		return g.str()
+	case 2:
+		return float64(int8(g.byte()))
+	case 3:
+		return g.byte()%2 == 0
+	case 4:
+		if depth > 0 {
+			n := max(g.count(), 0)
+			arr := make([]any, n)
+			for i := range arr {
+				arr[i] = g.jsonValue(depth - 1)
+			}
+			return arr
+		}
+	case 5:
+		if depth > 0 {
+			n := max(g.count(), 0)
+			obj := make(map[string]any, n)
+			for i := 0; i < n; i++ {
+				obj[g.str()] = g.jsonValue(depth - 1)
+			}
+			return obj
+		}
+	}
+	return nil
+}
+
+func (g *fuzzGen) tree(depth int) *FuzzTree {
+	t := &FuzzTree{Leaf: g.leaf(), Ptr: g.leafPtr(), Arr: [2]int{int(g.byte()), int(g.byte())}, Any: g.jsonValue(2)}
+	if n := g.count(); n >= 0 && depth > 0 {
+		t.Kids = make([]*FuzzTree, n)
+		for i := range t.Kids {
+			if g.byte()%4 != 0 {
+				t.Kids[i] = g.tree(depth - 1)
+			}
+		}
+	}
+	if n := g.count(); n >= 0 {
+		t.ByName = make(map[string]FuzzLeaf, n)
+		for i := 0; i < n; i++ {
+			t.ByName[g.str()] = g.leaf()
+		}
+	}
+	if n := g.count(); n >= 0 {
+		t.Bytes = make([]byte, n)
+		for i := range t.Bytes {
+			t.Bytes[i] = g.byte()
+		}
+	}
+	return t
+}
+
+// fuzzLeafPtrs 按固定顺序收集树中的 Ptr 字段
+func fuzzLeafPtrs(t *FuzzTree, ptrs []*FuzzLeaf) []*FuzzLeaf {
+	if t == nil {
+		return ptrs
+	}
+	ptrs = append(ptrs, t.Ptr)
+	for _, kid := range t.Kids {
+		ptrs = fuzzLeafPtrs(kid, ptrs)
+	}
+	return ptrs
+}
+
+// mutateFuzzTree 修改原值中所有可经由引用到达的数据，副本不应随之变化
+func mutateFuzzTree(t *FuzzTree, seen map[any]bool) {
+	if t == nil || seen[t] {
+		return
+	}
+	seen[t] = true
+	mutateFuzzLeaf(&t.Leaf, seen)
+	mutateFuzzLeaf(t.Ptr, seen)
+	for _, kid := range t.Kids {
+		mutateFuzzTree(kid, seen)
+	}
+	for key, leaf := range t.ByName {
+		leaf.Name += "!"
+		t.ByName[key] = leaf
+		mutateFuzzLeaf(&leaf, seen)
+	}
+	if t.ByName != nil {
+		t.ByName["mutated"] = FuzzLeaf{}
+	}
+	for i := range t.Bytes {
+		t.Bytes[i]++
+	}
+	mutateJSONValue(t.Any)
+}
+
+func mutateFuzzLeaf(l *FuzzLeaf, seen map[any]bool) {
+	if l == nil || seen[l] {
+		return
+	}
+	seen[l] = true
+	l.Name += "!"
+	for i := range l.Tags {
+		l.Tags[i] += "!"
+	}
+	for key := range l.Attrs {
+		l.Attrs[key]++
+	}
+}
+
+func mutateJSONValue(v any) {
+	switch v := v.(type) {
+	case []any:
+		for i := range v {
+			mutateJSONValue(v[i])
+			v[i] = "mutated"
+		}
+	case map[string]any:
+		for key := range v {
+			mutateJSONValue(v[key])
+			v[key] = "mutated"
+		}
+	}
+}
+
+// FuzzCopy 差分测试：Copy 的结果与原值相等、与原值不共享内存，且与 CopyJSON、CopyJSONLike 的结果一致。
+// 运行 go test -fuzz=FuzzCopy 生成新输入，发现的问题输入保存在 testdata/fuzz/FuzzCopy 中
+func FuzzCopy(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte("\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f"))
+	f.Add([]byte("\x04\x03\x02\x01\x00\xff\xfe\xfd\x05\x04\x04\x04\x01\x01\x01\x02\x03\x04"))
+	f.Add([]byte("shared pointers and nested kids: \x01\x01\x01\x04\x04\x04\x04\x04"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g := &fuzzGen{data: data}
+		original := g.tree(3)
+
+		copied := Copy(original)
+		if !reflect.DeepEqual(copied, original) {
+			t.Fatalf("Copy differs:\n%+v\nwant\n%+v", copied, original)
+		}
+		if shared, path := SharesPointersWith(original, copied); shared {
+			t.Fatalf("copy shares memory with the original at %s", path)
+		}
+		if want, err := CopyJSON(original); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(copied, want) {
+			t.Fatalf("Copy differs from CopyJSON:\n%+v\nwant\n%+v", copied, want)
+		}
+		if any := CopyJSONLike(original.Any); !reflect.DeepEqual(any, original.Any) {
+			t.Fatalf("CopyJSONLike = %#v, want %#v", any, original.Any)
+		}
+
+		// 原值中共享的指针在副本中同样共享，不共享的仍然不共享
+		origPtrs, copyPtrs := fuzzLeafPtrs(original, nil), fuzzLeafPtrs(copied, nil)
+		for i := range origPtrs {
+			for j := i + 1; j < len(origPtrs); j++ {
+				if (origPtrs[i] == origPtrs[j]) != (copyPtrs[i] == copyPtrs[j]) {
+					t.Fatalf("sharing of Ptr %d and %d not preserved", i, j)
+				}
+			}
+		}
+
+		before, err := CopyJSON(copied)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mutateFuzzTree(original, make(map[any]bool))
+		if !reflect.DeepEqual(copied, before) {
+			t.Fatalf("mutating the original changed the copy:\n%+v\nwant\n%+v", copied, before)
+		}
+	})
+}
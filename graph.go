@@ -0,0 +1,112 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TypeGraph 类型依赖图：节点为拷贝中会遍历到的类型，边为类型之间的引用关系
+// 与 TypeAnalysisResult 的树形结构不同，同一类型只对应一个节点，递归类型形成环
+type TypeGraph struct {
+	Root  reflect.Type
+	Nodes map[reflect.Type]*TypeAnalysisResult
+	Edges []TypeEdge
+
+	order []reflect.Type // 节点的发现顺序，用于稳定输出
+}
+
+// TypeEdge 类型之间的引用：From 经由 Via 引用 To
+// Via 为结构体字段名，或 "elem"（指针、切片、数组的元素）、"key"、"value"（映射的键和值）
+type TypeEdge struct {
+	From, To reflect.Type
+	Via      string
+}
+
+// AnalyzeTypeGraph 返回类型 T 的依赖图，用于诊断和代码生成
+// 只包含拷贝时会遍历的部分：未导出字段、通道和函数的元素类型不计入
+func AnalyzeTypeGraph[T any]() *TypeGraph {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	g := &TypeGraph{Root: t, Nodes: make(map[reflect.Type]*TypeAnalysisResult)}
+	g.visit(defaultManager, t)
+	return g
+}
+
+// visit 添加类型节点及其出边，已添加的类型直接返回
+func (g *TypeGraph) visit(m *DeepCopyManager, t reflect.Type) {
+	if _, ok := g.Nodes[t]; ok {
+		return
+	}
+	g.Nodes[t] = m.getOrAnalyzeType(t)
+	g.order = append(g.order, t)
+
+	// 不可变值类型按值复制，不展开其内部
+	if !m.disableBuiltins && immutableValueTypes[t] {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		g.addEdge(m, t, t.Elem(), "elem")
+	case reflect.Map:
+		g.addEdge(m, t, t.Key(), "key")
+		g.addEdge(m, t, t.Elem(), "value")
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if field := t.Field(i); field.PkgPath == "" {
+				g.addEdge(m, t, field.Type, field.Name)
+			}
+		}
+	}
+}
+
+// addEdge 添加一条边并继续遍历目标类型
+func (g *TypeGraph) addEdge(m *DeepCopyManager, from, to reflect.Type, via string) {
+	g.Edges = append(g.Edges, TypeEdge{From: from, To: to, Via: via})
+	g.visit(m, to)
+}
+
+// ToDOT 以 Graphviz DOT 格式输出依赖图，只包含值类型的节点以方框表示
+func (g *TypeGraph) ToDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph TypeGraph {\n")
+	for _, t := range g.order {
+		shape := "ellipse"
+		if g.Nodes[t].IsOnlyValues {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "\t%q [shape=%s];\n", t.String(), shape)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", e.From.String(), e.To.String(), e.Via)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// TopologicalOrder 按依赖顺序返回所有类型：被引用的类型排在引用它的类型之前，
+// 环中的类型按从根开始深度优先遍历的顺序断开
+func (g *TypeGraph) TopologicalOrder() []reflect.Type {
+	out := make(map[reflect.Type][]reflect.Type, len(g.Nodes))
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e.To)
+	}
+
+	order := make([]reflect.Type, 0, len(g.Nodes))
+	seen := make(map[reflect.Type]bool, len(g.Nodes))
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		for _, to := range out[t] {
+			walk(to)
+		}
+		order = append(order, t)
+	}
+	for _, t := range g.order {
+		walk(t)
+	}
+	return order
+}
@@ -0,0 +1,83 @@
+package deepcopy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 两个字段引用同一类型的联系人
+type Contact struct {
+	Name     string
+	Home     *PostalAddress
+	Work     *PostalAddress
+	Friends  []*Contact
+	Meta     map[string]int
+	internal *PostalAddress
+}
+
+type PostalAddress struct {
+	City string
+}
+
+func TestAnalyzeTypeGraph(t *testing.T) {
+	g := AnalyzeTypeGraph[Contact]()
+
+	contact := reflect.TypeOf(Contact{})
+	addrPtr := reflect.TypeOf(&PostalAddress{})
+	addr := reflect.TypeOf(PostalAddress{})
+	if g.Root != contact {
+		t.Errorf("Root: got %v", g.Root)
+	}
+	for _, typ := range []reflect.Type{contact, addrPtr, addr, reflect.TypeOf([]*Contact{}), reflect.TypeOf(&Contact{})} {
+		if g.Nodes[typ] == nil {
+			t.Errorf("missing node %v", typ)
+		}
+	}
+	if !g.Nodes[addr].IsOnlyValues || g.Nodes[contact].IsOnlyValues {
+		t.Error("nodes should carry the analysis results")
+	}
+
+	// Home 和 Work 指向同一个节点，未导出字段不计入
+	var vias []string
+	for _, e := range g.Edges {
+		if e.From == contact && e.To == addrPtr {
+			vias = append(vias, e.Via)
+		}
+	}
+	if !reflect.DeepEqual(vias, []string{"Home", "Work"}) {
+		t.Errorf("edges to *PostalAddress: got %v, want [Home Work]", vias)
+	}
+
+	dot := g.ToDOT()
+	for _, want := range []string{
+		"digraph TypeGraph {",
+		`"deepcopy.Contact" -> "*deepcopy.PostalAddress" [label="Home"];`,
+		`"map[string]int" -> "string" [label="key"];`,
+		`"deepcopy.PostalAddress" [shape=box];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output should contain %s:\n%s", want, dot)
+		}
+	}
+}
+
+func TestTypeGraphTopologicalOrder(t *testing.T) {
+	g := AnalyzeTypeGraph[Contact]()
+	order := g.TopologicalOrder()
+	if len(order) != len(g.Nodes) {
+		t.Fatalf("order should contain every node once, got %d of %d", len(order), len(g.Nodes))
+	}
+
+	index := make(map[reflect.Type]int, len(order))
+	for i, typ := range order {
+		index[typ] = i
+	}
+	if index[reflect.TypeOf(PostalAddress{})] > index[reflect.TypeOf(&PostalAddress{})] {
+		t.Error("dependencies should come before the types that use them")
+	}
+	// 递归类型（Contact -> []*Contact -> *Contact -> Contact）在根处断开，根排在最后
+	if order[len(order)-1] != reflect.TypeOf(Contact{}) {
+		t.Errorf("root should come last, got %v", order)
+	}
+}
@@ -0,0 +1,116 @@
+package deepcopy
+
+import (
+	"reflect"
+	"sync"
+)
+
+// postCopyHook 拷贝后钩子，以指针区分各次注册，注销时按指针移除
+type postCopyHook struct {
+	fn func(ptr reflect.Value) // 参数为指向副本的指针
+}
+
+// pendingHook 等待执行的钩子
+type pendingHook struct {
+	hooks []*postCopyHook
+	ptr   reflect.Value
+}
+
+// WithPostCopyHook 为类型 T 注册拷贝后钩子：T 类型的值（包括嵌套在其他结构中的）拷贝完成后，
+// 以指向副本的指针调用 fn，可用于重建派生字段、重新绑定自引用指针等。
+// 钩子在整个拷贝完成后按拷贝完成的顺序执行，此时循环引用已经建立；每个副本实例只调用一次。
+// 由 DeepCopy 方法或自定义拷贝函数整体拷贝的值不会调用 T 内部字段的钩子。
+// 注意 Copy 按值返回根值，根值中内联的副本在返回后地址会改变，自引用指针应指向经由指针或切片拷贝的对象
+func WithPostCopyHook[T any](fn func(copied *T)) ManagerOption {
+	return withPostCopyHook(newPostCopyHook(fn))
+}
+
+// RegisterPostCopyHook 为默认管理器注册类型 T 的拷贝后钩子，见 WithPostCopyHook，返回注销该钩子的函数（可重复调用）
+// 注册和注销与 SetDefaultManagerOptions 相同，不能与拷贝同时进行
+func RegisterPostCopyHook[T any](fn func(copied *T)) (unregister func()) {
+	t, hook := newPostCopyHook(fn)
+	SetDefaultManagerOptions(withPostCopyHook(t, hook))
+	var once sync.Once
+	return func() {
+		once.Do(func() { SetDefaultManagerOptions(withoutPostCopyHook(t, hook)) })
+	}
+}
+
+// newPostCopyHook 包装类型 T 的钩子函数
+func newPostCopyHook[T any](fn func(copied *T)) (reflect.Type, *postCopyHook) {
+	return reflect.TypeOf((*T)(nil)).Elem(), &postCopyHook{fn: func(ptr reflect.Value) {
+		fn(ptr.Interface().(*T))
+	}}
+}
+
+// withPostCopyHook 为类型 t 追加钩子
+func withPostCopyHook(t reflect.Type, hook *postCopyHook) ManagerOption {
+	return func(m *DeepCopyManager) {
+		if m.postCopyHooks == nil {
+			m.postCopyHooks = make(map[reflect.Type][]*postCopyHook)
+		}
+		m.postCopyHooks[t] = append(m.postCopyHooks[t], hook)
+	}
+}
+
+// withoutPostCopyHook 移除类型 t 的钩子 hook，移除后没有任何钩子时恢复为 nil，拷贝不再记录嵌套深度
+func withoutPostCopyHook(t reflect.Type, hook *postCopyHook) ManagerOption {
+	return func(m *DeepCopyManager) {
+		var kept []*postCopyHook
+		for _, h := range m.postCopyHooks[t] {
+			if h != hook {
+				kept = append(kept, h)
+			}
+		}
+		if len(kept) > 0 {
+			m.postCopyHooks[t] = kept
+			return
+		}
+		delete(m.postCopyHooks, t)
+		if len(m.postCopyHooks) == 0 {
+			m.postCopyHooks = nil
+		}
+	}
+}
+
+// leaveHooked 注册了钩子时在 copyRecursive 返回前调用：副本类型注册了钩子时排队，
+// 最外层返回时执行所有排队的钩子
+func (s *copyState) leaveHooked(cpy reflect.Value) {
+	s.depth--
+	if hooks, ok := s.manager.postCopyHooks[cpy.Type()]; ok && cpy.CanAddr() && s.err == nil {
+		s.hooks = append(s.hooks, pendingHook{hooks: hooks, ptr: cpy.Addr()})
+	}
+	if s.depth == 0 {
+		pending := s.hooks
+		s.hooks = nil
+		for _, p := range pending {
+			p.run()
+		}
+	}
+}
+
+// runInlineHooks 立即执行 mark 之后排队的、位于临时值 v 内部的钩子：
+// v 随后被复制到映射或接口中，之后再通过原地址修改不会影响副本
+func (s *copyState) runInlineHooks(mark int, v reflect.Value) {
+	if len(s.hooks) == mark {
+		return
+	}
+	start := v.UnsafeAddr()
+	end := start + v.Type().Size()
+	kept := s.hooks[:mark]
+	for _, p := range s.hooks[mark:] {
+		if addr := p.ptr.Pointer(); addr >= start && addr < end {
+			p.run()
+		} else {
+			kept = append(kept, p)
+		}
+	}
+	s.hooks = kept
+}
+
+// run 依次调用钩子
+func (p pendingHook) run() {
+	for _, hook := range p.hooks {
+		hook.fn(p.ptr)
+	}
+}
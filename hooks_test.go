@@ -0,0 +1,141 @@
+package deepcopy
+
+import (
+	"testing"
+)
+
+// 拷贝后需要重建派生字段的会话
+type Session struct {
+	ID     string
+	Tokens []string
+	Peer   *Session
+
+	tokenCount int      // 派生字段，拷贝后由钩子重建
+	self       *Session // 自引用指针，拷贝后由钩子重新绑定
+}
+
+// 包含多处 Session 的结构
+type SessionHolder struct {
+	Main   Session
+	Ptr    *Session
+	Again  *Session
+	List   []Session
+	ByName map[string]Session
+	Any    any
+}
+
+// sessionHook 重建 Session 的派生字段，并记录被调用的副本
+func sessionHook(calls *[]*Session) func(*Session) {
+	return func(copied *Session) {
+		copied.tokenCount = len(copied.Tokens)
+		copied.self = copied
+		*calls = append(*calls, copied)
+	}
+}
+
+func TestPostCopyHookRunsOncePerInstance(t *testing.T) {
+	shared := &Session{ID: "shared", Tokens: []string{"a", "b"}}
+	original := SessionHolder{
+		Main:   Session{ID: "main", Tokens: []string{"a"}},
+		Ptr:    shared,
+		Again:  shared,
+		List:   []Session{{ID: "l0"}, {ID: "l1", Tokens: []string{"x", "y", "z"}}},
+		ByName: map[string]Session{"m": {ID: "m", Tokens: []string{"k"}}},
+		Any:    Session{ID: "any", Tokens: []string{"1", "2"}},
+	}
+
+	var calls []*Session
+	manager := NewDeepCopyManager(WithPostCopyHook(sessionHook(&calls)))
+	copied := manager.CopyValue(original).(SessionHolder)
+
+	// Main、共享指针（只拷贝一次）、List 两个元素、映射值、接口中的值
+	if len(calls) != 6 {
+		t.Fatalf("hook calls: got %d, want 6", len(calls))
+	}
+	check := func(name string, s Session, want int) {
+		t.Helper()
+		if s.tokenCount != want {
+			t.Errorf("%s: tokenCount = %d, want %d", name, s.tokenCount, want)
+		}
+	}
+	check("Main", copied.Main, 1)
+	check("Ptr", *copied.Ptr, 2)
+	check("List[1]", copied.List[1], 3)
+	check("ByName", copied.ByName["m"], 1)
+	check("Any", copied.Any.(Session), 2)
+
+	// CopyValue 按值返回，Main 随根值移动；经由指针和切片拷贝的副本地址不变
+	if copied.Ptr.self != copied.Ptr || copied.List[0].self != &copied.List[0] {
+		t.Error("hooks should receive pointers to the final copies")
+	}
+	if original.Main.tokenCount != 0 || shared.self != nil {
+		t.Error("hooks should not run on the original")
+	}
+}
+
+func TestPostCopyHookAfterCycles(t *testing.T) {
+	a := &Session{ID: "a"}
+	b := &Session{ID: "b", Peer: a}
+	a.Peer = b
+
+	var calls []*Session
+	manager := NewDeepCopyManager(WithPostCopyHook(sessionHook(&calls)))
+	copied := manager.CopyValue(a).(*Session)
+
+	if len(calls) != 2 {
+		t.Fatalf("hook calls: got %d, want 2", len(calls))
+	}
+	// 钩子执行时循环引用已经建立
+	for _, s := range calls {
+		if s.Peer == nil || s.Peer.Peer != s {
+			t.Errorf("cycle should be resolved before hooks run: %s", s.ID)
+		}
+	}
+	if copied.self != copied || copied.Peer.self != copied.Peer {
+		t.Error("self pointers should be bound to the copies")
+	}
+}
+
+// 只在独立管理器中注册钩子的类型
+type ManagedCounter struct {
+	N     int
+	Items []int
+}
+
+func TestWithPostCopyHook(t *testing.T) {
+	var calls int
+	manager := NewDeepCopyManager(WithPostCopyHook(func(c *ManagedCounter) {
+		calls++
+		c.N = len(c.Items)
+	}))
+
+	original := ManagedCounter{Items: []int{1, 2, 3}}
+	copied := manager.CopyValue(original).(ManagedCounter)
+	if calls != 1 || copied.N != 3 {
+		t.Errorf("manager hook: got %d calls, N = %d", calls, copied.N)
+	}
+
+	if Copy(original).N != 0 || calls != 1 {
+		t.Error("hooks registered on a manager should not affect the default manager")
+	}
+}
+
+// 默认管理器上注册的钩子对 Copy 生效，注销后不再调用
+func TestRegisterPostCopyHook(t *testing.T) {
+	var calls []*Session
+	unregister := RegisterPostCopyHook(sessionHook(&calls))
+	t.Cleanup(unregister)
+
+	copied := Copy(&Session{ID: "a", Tokens: []string{"x", "y"}})
+	if len(calls) != 1 || copied.tokenCount != 2 || copied.self != copied {
+		t.Fatalf("hook calls: got %d, tokenCount = %d", len(calls), copied.tokenCount)
+	}
+
+	unregister()
+	if Copy(&Session{Tokens: []string{"x"}}).tokenCount != 0 || len(calls) != 1 {
+		t.Error("unregistered hook should not run")
+	}
+	if defaultManager.postCopyHooks != nil {
+		t.Error("removing the last hook should leave no hooks registered")
+	}
+}
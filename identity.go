@@ -0,0 +1,70 @@
+package deepcopy
+
+import "reflect"
+
+// IdentityMap 一次拷贝中原值的指针到副本中对应指针的映射，由 CopyWithIdentityMap 返回
+type IdentityMap struct {
+	ptrs map[uintptr]reflect.Value
+}
+
+// CopyWithIdentityMap 深拷贝 src，同时返回原值中每个被复制的指针（包括接口、映射中的）到副本中对应指针的映射，
+// 用于把指向原对象图的外部引用转换为指向副本。由 DeepCopy 方法或自定义拷贝函数整体拷贝的值，其内部的指针不会被记录
+func CopyWithIdentityMap[T any](src T, opts ...Option) (T, *IdentityMap, error) {
+	var zero T
+	cfg := newCopyConfig(opts)
+	if cfg.locker != nil {
+		cfg.locker.Lock()
+		defer cfg.locker.Unlock()
+	}
+
+	ids := &IdentityMap{}
+	srcVal := reflect.ValueOf(src)
+	if !srcVal.IsValid() {
+		return zero, ids, nil
+	}
+
+	// 不走快速路径和顶层的 DeepCopy 方法，保证所有指针都经由 copyRecursive 记录
+	state := newCopyState(cfg)
+	var result T
+	if err := state.run(func() { result = copyToT[T](srcVal, state) }); err != nil {
+		return zero, nil, err
+	}
+	ids.ptrs = state.visited
+	return result, ids, nil
+}
+
+// Len 映射中的指针个数
+func (m *IdentityMap) Len() int {
+	return len(m.ptrs)
+}
+
+// Lookup 返回原值中的指针 orig 在副本中对应的指针，orig 不是被复制过的指针时返回 false
+func (m *IdentityMap) Lookup(orig any) (any, bool) {
+	v, ok := m.lookup(reflect.ValueOf(orig))
+	if !ok || !v.CanInterface() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// LookupCopy 与 IdentityMap.Lookup 相同，按原指针的类型返回
+func LookupCopy[P any](m *IdentityMap, orig P) (P, bool) {
+	copied, ok := m.Lookup(orig)
+	if !ok {
+		var zero P
+		return zero, false
+	}
+	return copied.(P), true
+}
+
+// lookup 按地址查找，类型不同的同一地址（如结构体与其第一个字段）不算匹配
+func (m *IdentityMap) lookup(orig reflect.Value) (reflect.Value, bool) {
+	if orig.Kind() != reflect.Ptr || orig.IsNil() {
+		return reflect.Value{}, false
+	}
+	copied, ok := m.ptrs[orig.Pointer()]
+	if !ok || copied.Type() != orig.Type() {
+		return reflect.Value{}, false
+	}
+	return copied, true
+}
@@ -0,0 +1,68 @@
+package deepcopy
+
+import "testing"
+
+// IdentityNode 对象图中的节点，通过指针、接口和映射互相引用
+type IdentityNode struct {
+	Name  string
+	Next  *IdentityNode
+	Attr  any
+	Peers map[string]*IdentityNode
+}
+
+type IdentityGraph struct {
+	Nodes []*IdentityNode
+}
+
+func TestCopyWithIdentityMap(t *testing.T) {
+	a := &IdentityNode{Name: "a"}
+	b := &IdentityNode{Name: "b", Next: a}
+	hidden := &IdentityNode{Name: "hidden"} // 只能经由接口到达
+	peer := &IdentityNode{Name: "peer"}     // 只能经由映射到达
+	a.Next = b
+	a.Attr = hidden
+	b.Peers = map[string]*IdentityNode{"p": peer}
+	graph := &IdentityGraph{Nodes: []*IdentityNode{a, b}}
+
+	copied, ids, err := CopyWithIdentityMap(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[*IdentityNode]*IdentityNode{
+		a:      copied.Nodes[0],
+		b:      copied.Nodes[1],
+		hidden: copied.Nodes[0].Attr.(*IdentityNode),
+		peer:   copied.Nodes[1].Peers["p"],
+	}
+	for orig, wantCopy := range want {
+		got, ok := LookupCopy(ids, orig)
+		if !ok || got != wantCopy || got == orig {
+			t.Errorf("%s: got %p (%v), want %p", orig.Name, got, ok, wantCopy)
+		}
+	}
+	if got, ok := ids.Lookup(graph); !ok || got != copied {
+		t.Errorf("root: got %v (%v)", got, ok)
+	}
+	if ids.Len() != 5 {
+		t.Errorf("Len = %d, want 5", ids.Len())
+	}
+
+	// 不在原值中的指针、类型不同的同一地址、非指针值都找不到
+	if _, ok := LookupCopy(ids, &IdentityNode{}); ok {
+		t.Error("unrelated pointer should not be found")
+	}
+	if _, ok := LookupCopy(ids, &a.Name); ok {
+		t.Error("field address should not match the struct pointer")
+	}
+	if _, ok := ids.Lookup(*a); ok {
+		t.Error("non-pointer should not be found")
+	}
+}
+
+func TestCopyWithIdentityMapNil(t *testing.T) {
+	copied, ids, err := CopyWithIdentityMap[any](nil)
+	if err != nil || copied != nil || ids.Len() != 0 {
+		t.Errorf("got %v, %d entries, %v", copied, ids.Len(), err)
+	}
+}
@@ -0,0 +1,122 @@
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package gentest
+
+import (
+	"github.com/wsqun/deepcopy"
+)
+
+// deepcopyGenManager 用于拷贝接口字段中的动态值
+var deepcopyGenManager = deepcopy.NewDeepCopyManager()
+
+// DeepCopy 返回 Node 的深拷贝
+func (in Node) DeepCopy() Node {
+	return in.deepCopyMemo(make(map[any]any))
+}
+
+// deepCopyMemo 深拷贝 Node，memo 记录已复制的指针以处理循环引用
+func (in Node) deepCopyMemo(memo map[any]any) Node {
+	out := in
+	if in.Children != nil {
+		out.Children = make([]*Node, len(in.Children), cap(in.Children))
+		for i1 := range in.Children {
+			if in.Children[i1] != nil {
+				if v, ok := memo[in.Children[i1]]; ok {
+					out.Children[i1] = v.(*Node)
+				} else {
+					p2 := new(Node)
+					memo[in.Children[i1]] = p2
+					*p2 = (*in.Children[i1]).deepCopyMemo(memo)
+					out.Children[i1] = p2
+				}
+			}
+		}
+	}
+	if in.Parent != nil {
+		if v, ok := memo[in.Parent]; ok {
+			out.Parent = v.(*Node)
+		} else {
+			p3 := new(Node)
+			memo[in.Parent] = p3
+			*p3 = (*in.Parent).deepCopyMemo(memo)
+			out.Parent = p3
+		}
+	}
+	if in.Attrs != nil {
+		out.Attrs = make(map[string][]int, len(in.Attrs))
+		for k4, v5 := range in.Attrs {
+			var cv6 []int
+			if v5 != nil {
+				cv6 = make([]int, len(v5), cap(v5))
+				copy(cv6, v5)
+			}
+			out.Attrs[k4] = cv6
+		}
+	}
+	if in.Meta != nil {
+		out.Meta = deepcopyGenManager.CopyValue(in.Meta).(interface{})
+	}
+	return out
+}
+
+// DeepCopy 返回 Matrix 的深拷贝
+func (in Matrix) DeepCopy() Matrix {
+	return in.deepCopyMemo(make(map[any]any))
+}
+
+// deepCopyMemo 深拷贝 Matrix，memo 记录已复制的指针以处理循环引用
+func (in Matrix) deepCopyMemo(memo map[any]any) Matrix {
+	out := in
+	if in != nil {
+		out = make(Matrix, len(in), cap(in))
+		for i7 := range in {
+			if in[i7] != nil {
+				out[i7] = make([]float64, len(in[i7]), cap(in[i7]))
+				copy(out[i7], in[i7])
+			}
+		}
+	}
+	return out
+}
+
+// DeepCopy 返回 Config 的深拷贝
+func (in Config) DeepCopy() Config {
+	return in.deepCopyMemo(make(map[any]any))
+}
+
+// deepCopyMemo 深拷贝 Config，memo 记录已复制的指针以处理循环引用
+func (in Config) deepCopyMemo(memo map[any]any) Config {
+	out := in
+	if in.Limits != nil {
+		if v, ok := memo[in.Limits]; ok {
+			out.Limits = v.(*Limits)
+		} else {
+			p8 := new(Limits)
+			memo[in.Limits] = p8
+			*p8 = *in.Limits
+			out.Limits = p8
+		}
+	}
+	if in.Rules != nil {
+		out.Rules = make([]Rule, len(in.Rules), cap(in.Rules))
+		copy(out.Rules, in.Rules)
+	}
+	if in.Index != nil {
+		out.Index = make(map[Rule]*Node, len(in.Index))
+		for k9, v10 := range in.Index {
+			var cv11 *Node
+			if v10 != nil {
+				if v, ok := memo[v10]; ok {
+					cv11 = v.(*Node)
+				} else {
+					p12 := new(Node)
+					memo[v10] = p12
+					*p12 = (*v10).deepCopyMemo(memo)
+					cv11 = p12
+				}
+			}
+			out.Index[k9] = cv11
+		}
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+package gentest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wsqun/deepcopy"
+)
+
+// plainNode 与 Node 结构相同但没有 DeepCopy 方法，用于走反射拷贝路径
+type plainNode struct {
+	Name     string
+	Children []*plainNode
+	Parent   *plainNode
+	Attrs    map[string][]int
+	Tags     [2]string
+	Meta     interface{}
+	Created  time.Time
+}
+
+// plainConfig 与 Config 结构相同但没有 DeepCopy 方法
+type plainConfig struct {
+	Version int
+	Limits  *Limits
+	Rules   []Rule
+	Index   map[Rule]*plainNode
+}
+
+// toPlain 把 Node 图转换为 plainNode 图，保持指针共享关系
+func toPlain(n *Node, memo map[*Node]*plainNode) *plainNode {
+	if n == nil {
+		return nil
+	}
+	if p, ok := memo[n]; ok {
+		return p
+	}
+	p := &plainNode{Name: n.Name, Attrs: n.Attrs, Tags: n.Tags, Meta: n.Meta, Created: n.Created}
+	memo[n] = p
+	if n.Children != nil {
+		p.Children = make([]*plainNode, len(n.Children))
+		for i, c := range n.Children {
+			p.Children[i] = toPlain(c, memo)
+		}
+	}
+	p.Parent = toPlain(n.Parent, memo)
+	return p
+}
+
+func toPlainConfig(c Config) plainConfig {
+	p := plainConfig{Version: c.Version, Limits: c.Limits, Rules: c.Rules}
+	if c.Index != nil {
+		memo := make(map[*Node]*plainNode)
+		p.Index = make(map[Rule]*plainNode, len(c.Index))
+		for k, v := range c.Index {
+			p.Index[k] = toPlain(v, memo)
+		}
+	}
+	return p
+}
+
+func newTree() *Node {
+	root := &Node{
+		Name:    "root",
+		Attrs:   map[string][]int{"a": {1, 2}, "b": nil},
+		Tags:    [2]string{"x", "y"},
+		Meta:    []string{"m1", "m2"},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	left := &Node{Name: "left", Parent: root}
+	right := &Node{Name: "right", Parent: root, Children: []*Node{left}}
+	root.Children = []*Node{left, right, nil}
+	return root
+}
+
+func TestGeneratedNodeMatchesReflectiveCopy(t *testing.T) {
+	original := newTree()
+
+	generated := original.DeepCopy()
+	reflective := deepcopy.Copy(toPlain(original, make(map[*Node]*plainNode)))
+
+	if !reflect.DeepEqual(toPlain(&generated, make(map[*Node]*plainNode)), reflective) {
+		t.Fatalf("generated copy differs from reflective copy")
+	}
+
+	// 循环引用与共享指针需要在副本中保持
+	if generated.Children[0].Parent == original {
+		t.Error("Parent should point into the copy, not the original")
+	}
+	if generated.Children[1].Children[0] != generated.Children[0] {
+		t.Error("shared child pointer should be preserved in the copy")
+	}
+
+	// 副本与原始值相互独立
+	generated.Attrs["a"][0] = 100
+	generated.Meta.([]string)[0] = "changed"
+	if original.Attrs["a"][0] != 1 || original.Meta.([]string)[0] != "m1" {
+		t.Error("modifying the copy should not affect the original")
+	}
+}
+
+func TestGeneratedMethodUsedByCopy(t *testing.T) {
+	original := newTree()
+	copied := deepcopy.Copy(original)
+
+	if copied == original || copied.Children[0] == original.Children[0] {
+		t.Error("Copy should return an independent pointer graph")
+	}
+	// 值接收者的 DeepCopy 无法得知根指针，根节点在副本内部会被复制一次，
+	// 但副本内部的循环仍然闭合
+	parent := copied.Children[0].Parent
+	if parent == original || parent.Children[0] != copied.Children[0] {
+		t.Error("cycle through Parent should stay inside the copy")
+	}
+}
+
+func TestGeneratedMatrixMatchesReflectiveCopy(t *testing.T) {
+	original := Matrix{{1, 2}, nil, {3}}
+
+	generated := original.DeepCopy()
+	reflective := deepcopy.Copy([][]float64(original))
+
+	if !reflect.DeepEqual([][]float64(generated), reflective) {
+		t.Fatalf("generated %v, reflective %v", generated, reflective)
+	}
+	generated[0][0] = 100
+	if original[0][0] != 1 {
+		t.Error("modifying the copy should not affect the original")
+	}
+}
+
+func TestGeneratedConfigMatchesReflectiveCopy(t *testing.T) {
+	shared := &Node{Name: "shared"}
+	original := Config{
+		Version: 3,
+		Limits:  &Limits{Max: 10, Min: 1},
+		Rules:   []Rule{{Name: "r1", Weight: 0.5}},
+		Index:   map[Rule]*Node{{Name: "a"}: shared, {Name: "b"}: shared},
+	}
+
+	generated := original.DeepCopy()
+	reflective := deepcopy.Copy(toPlainConfig(original))
+
+	if !reflect.DeepEqual(toPlainConfig(generated), reflective) {
+		t.Fatalf("generated copy differs from reflective copy")
+	}
+	if generated.Limits == original.Limits {
+		t.Error("Limits pointer should be copied")
+	}
+	if generated.Index[Rule{Name: "a"}] != generated.Index[Rule{Name: "b"}] {
+		t.Error("shared map values should remain shared in the copy")
+	}
+}
@@ -0,0 +1,40 @@
+// Package gentest 存放 deepcopy-gen 的测试类型，生成的代码与反射版 Copy 进行对比
+package gentest
+
+import "time"
+
+//go:generate go run ../../cmd/deepcopy-gen -type=Node,Matrix,Config
+
+// Node 包含切片、映射、指针、循环引用和接口字段
+type Node struct {
+	Name     string
+	Children []*Node
+	Parent   *Node
+	Attrs    map[string][]int
+	Tags     [2]string
+	Meta     interface{}
+	Created  time.Time
+}
+
+// Matrix 非结构体的命名类型
+type Matrix [][]float64
+
+// Config 包含可选的嵌套结构体指针和结构体切片
+type Config struct {
+	Version int
+	Limits  *Limits
+	Rules   []Rule
+	Index   map[Rule]*Node
+}
+
+// Limits 只包含值类型
+type Limits struct {
+	Max int
+	Min int
+}
+
+// Rule 作为映射键使用的值类型
+type Rule struct {
+	Name   string
+	Weight float64
+}
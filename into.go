@@ -0,0 +1,122 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNilDestination CopyInto 的目标为 nil 时返回
+var ErrNilDestination = errors.New("deepcopy: nil destination")
+
+// ErrNotSettable 拷贝目标不可设置（不可寻址）时返回
+var ErrNotSettable = errors.New("deepcopy: destination is not settable")
+
+// CopyInto 将 src 深拷贝到 *dst 中，适合反复拷贝到同一个目标的场景：
+// 目标中长度相同的切片直接复用底层数组，已有的映射清空后复用，减少稳态下的分配。
+// 未导出字段以及被 WithFieldFilter 排除的字段保留 *dst 中原有的值；
+// dst 不应与 src 共享底层存储，出错时 *dst 可能已被部分写入
+func CopyInto[T any](dst *T, src T, opts ...Option) error {
+	if dst == nil {
+		return ErrNilDestination
+	}
+
+	cfg := newCopyConfig(opts)
+	if cfg.locker != nil {
+		cfg.locker.Lock()
+		defer cfg.locker.Unlock()
+	}
+
+	manager := getTypedManager[T]()
+	// T 为接口类型时没有静态类型信息，先按动态类型检查 DeepCopy 方法
+	if manager.rtype.Kind() == reflect.Interface && !cfg.ignoreMethods {
+		if result, ok, err := newCopyState(cfg).tryDeepCopyRoot(reflect.ValueOf(src)); err != nil {
+			return err
+		} else if ok {
+			*dst = result.Interface().(T)
+			return nil
+		}
+	}
+
+	analysis := manager.getOrAnalyzeType()
+	if cfg.useFastPath() && analysis.IsOnlyValues {
+		*dst = src
+		return nil
+	}
+
+	state := newCopyState(cfg)
+	if analysis.useDeepCopy() && !cfg.ignoreMethods {
+		if result, ok, err := state.tryDeepCopyRoot(reflect.ValueOf(src)); err != nil {
+			return err
+		} else if ok {
+			*dst = result.Interface().(T)
+			return nil
+		}
+	}
+
+	state.reuse = true
+	// 通过 &src 取值，T 为接口类型时源和目标同为接口
+	return state.run(func() {
+		state.copyRecursive(reflect.ValueOf(&src).Elem(), reflect.ValueOf(dst).Elem())
+	})
+}
+
+// CopyReflectValueInto 将 src 深拷贝到 dst 中，使用本管理器的分析结果和自定义拷贝函数
+// dst 必须与 src 类型相同且可设置，例如 reflect.ValueOf(&v).Elem()；
+// 遍历中的 panic 转换为带路径的 *PanicError 返回
+func (m *DeepCopyManager) CopyReflectValueInto(src, dst reflect.Value) error {
+	if !dst.IsValid() {
+		return ErrNilDestination
+	}
+	if !src.IsValid() {
+		src = reflect.Zero(dst.Type())
+	}
+	if src.Type() != dst.Type() {
+		return fmt.Errorf("%w: cannot copy %s into %s", ErrTypeConversion, src.Type(), dst.Type())
+	}
+	// 不可设置的目标在写入时才会 panic，提前检查；结构体以外的类型没有逐字段的检查
+	if !dst.CanSet() {
+		return fmt.Errorf("%w: %s, pass an addressable value such as reflect.ValueOf(&v).Elem()", ErrNotSettable, dst.Type())
+	}
+
+	state := newCopyState(&defaultCopyConfig)
+	state.manager = m
+	return state.run(func() {
+		state.copyRecursive(src, dst)
+	})
+}
+
+// CopySliceInto 将 src 深拷贝到 *dst 中，容量足够时复用 *dst 的底层数组，只在需要扩容时分配。
+// 结果长度为 len(src)，原长度范围内多出的元素被清零，避免保留不再使用的指针；
+// 元素中长度相同的切片和已有的映射同样被复用（与 CopyInto 相同）。dst 为 nil 时返回 ErrNilDestination
+func CopySliceInto[T any](dst *[]T, src []T) error {
+	if dst == nil {
+		return ErrNilDestination
+	}
+
+	buf := *dst
+	if cap(buf) < len(src) {
+		buf = make([]T, len(src))
+	} else {
+		if len(src) < len(buf) {
+			clear(buf[len(src):])
+		}
+		buf = buf[:len(src)]
+	}
+	*dst = buf
+	if len(src) == 0 {
+		return nil
+	}
+
+	// 元素只包含值类型时直接复制，稳态下没有任何分配
+	if getTypedManager[T]().getOrAnalyzeType().IsOnlyValues && !fastPathDisabled.Load() {
+		copy(buf, src)
+		return nil
+	}
+
+	state := newCopyState(&defaultCopyConfig)
+	state.reuse = true
+	return state.run(func() {
+		state.copyRecursive(reflect.ValueOf(src), reflect.ValueOf(dst).Elem())
+	})
+}
@@ -0,0 +1,228 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 复用目标存储的快照结构
+type Snapshot struct {
+	Values []int
+	Rows   [][]string
+	Index  map[string]int
+}
+
+func TestCopyIntoReusesStorage(t *testing.T) {
+	src := Snapshot{
+		Values: []int{1, 2, 3},
+		Rows:   [][]string{{"a"}, {"b", "c"}},
+		Index:  map[string]int{"a": 1},
+	}
+	dst := Snapshot{
+		Values: make([]int, 3),
+		Rows:   [][]string{{"x"}, {"y"}},
+		Index:  map[string]int{"stale": 9},
+	}
+	valuesPtr := reflect.ValueOf(dst.Values).Pointer()
+	rowsPtr := reflect.ValueOf(dst.Rows).Pointer()
+	firstRowPtr := reflect.ValueOf(dst.Rows[0]).Pointer()
+	indexPtr := reflect.ValueOf(dst.Index).Pointer()
+
+	if err := CopyInto(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst, src) {
+		t.Fatalf("got %+v, want %+v", dst, src)
+	}
+
+	if reflect.ValueOf(dst.Values).Pointer() != valuesPtr {
+		t.Error("slice with matching length should reuse its backing array")
+	}
+	if reflect.ValueOf(dst.Rows).Pointer() != rowsPtr || reflect.ValueOf(dst.Rows[0]).Pointer() != firstRowPtr {
+		t.Error("nested slices with matching length should be reused")
+	}
+	if reflect.ValueOf(dst.Index).Pointer() != indexPtr {
+		t.Error("existing map should be reused")
+	}
+
+	// 长度不同的切片重新分配，且与源不共享
+	if reflect.ValueOf(dst.Rows[1]).Pointer() == reflect.ValueOf(src.Rows[1]).Pointer() {
+		t.Error("destination must not share storage with the source")
+	}
+	src.Values[0] = 100
+	if dst.Values[0] != 1 {
+		t.Error("destination should be independent from the source")
+	}
+}
+
+// 多个目标字段引用同一个映射、切片时只复用一次，后面的字段不覆盖前面已写入的内容
+func TestCopyIntoSharedDestination(t *testing.T) {
+	type pair struct {
+		A, B map[string]int
+		X, Y []int
+	}
+	sharedMap := map[string]int{"stale": 0}
+	sharedSlice := make([]int, 2)
+	dst := pair{A: sharedMap, B: sharedMap, X: sharedSlice, Y: sharedSlice}
+	src := pair{A: map[string]int{"a": 1}, B: map[string]int{"b": 2}, X: []int{1, 2}, Y: []int{3, 4}}
+
+	if err := CopyInto(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst, src) {
+		t.Fatalf("got %+v, want %+v", dst, src)
+	}
+	if reflect.ValueOf(dst.A).Pointer() != reflect.ValueOf(sharedMap).Pointer() ||
+		reflect.ValueOf(dst.X).Pointer() != reflect.ValueOf(sharedSlice).Pointer() {
+		t.Error("the first field should still reuse the shared storage")
+	}
+}
+
+func TestCopyIntoAllocations(t *testing.T) {
+	src := Snapshot{Values: []int{1, 2, 3}, Rows: [][]string{{"a", "b"}}}
+	var dst Snapshot
+	if err := CopyInto(&dst, src); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := testing.AllocsPerRun(100, func() { _, _ = CopyE(src) })
+	reused := testing.AllocsPerRun(100, func() { _ = CopyInto(&dst, src) })
+	if reused >= fresh {
+		t.Errorf("CopyInto should allocate less than CopyE: %v >= %v", reused, fresh)
+	}
+}
+
+func TestCopyIntoNilDestination(t *testing.T) {
+	if err := CopyInto[Snapshot](nil, Snapshot{}); !errors.Is(err, ErrNilDestination) {
+		t.Errorf("expected ErrNilDestination, got %v", err)
+	}
+}
+
+// PartialUser 部分更新合并到已有对象
+type PartialUser struct {
+	ID      int
+	Name    string
+	Tags    []string
+	Profile struct {
+		Email string
+		Age   int
+	}
+}
+
+func TestCopyIntoSkipZeroSource(t *testing.T) {
+	dst := PartialUser{ID: 1, Name: "old", Tags: []string{"a"}}
+	dst.Profile.Email = "old@example.com"
+	dst.Profile.Age = 30
+
+	var src PartialUser
+	src.Name = "x"
+	src.Profile.Age = 31
+	if err := CopyInto(&dst, src, WithSkipZeroSource()); err != nil {
+		t.Fatal(err)
+	}
+	if dst.ID != 1 || dst.Name != "x" {
+		t.Errorf("ID = %d, Name = %q, want 1, x", dst.ID, dst.Name)
+	}
+	// nil 切片跳过，结构体逐字段合并
+	if !reflect.DeepEqual(dst.Tags, []string{"a"}) {
+		t.Errorf("Tags = %v, want [a]", dst.Tags)
+	}
+	if dst.Profile.Email != "old@example.com" || dst.Profile.Age != 31 {
+		t.Errorf("Profile = %+v", dst.Profile)
+	}
+
+	// 非 nil 的空切片不是零值，会覆盖目标
+	if err := CopyInto(&dst, PartialUser{Tags: []string{}}, WithSkipZeroSource()); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Tags == nil || len(dst.Tags) != 0 || dst.Name != "x" {
+		t.Errorf("dst = %+v", dst)
+	}
+
+	// 不带选项时整体替换
+	if err := CopyInto(&dst, PartialUser{Name: "y"}); err != nil {
+		t.Fatal(err)
+	}
+	if dst.ID != 0 || dst.Profile.Age != 0 {
+		t.Errorf("CopyInto without option should replace: %+v", dst)
+	}
+}
+
+func TestCopyReflectValueInto(t *testing.T) {
+	src := Snapshot{Values: []int{1}, Index: map[string]int{"a": 1}}
+	var dst Snapshot
+	if err := defaultManager.CopyReflectValueInto(reflect.ValueOf(src), reflect.ValueOf(&dst).Elem()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst, src) {
+		t.Fatalf("got %+v, want %+v", dst, src)
+	}
+	dst.Values[0] = 100
+	if src.Values[0] != 1 {
+		t.Error("CopyReflectValueInto should deep copy")
+	}
+
+	if err := defaultManager.CopyReflectValueInto(reflect.ValueOf(1), reflect.ValueOf(&dst).Elem()); !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("expected ErrTypeConversion, got %v", err)
+	}
+}
+
+func TestCopyReflectValueIntoNotSettable(t *testing.T) {
+	src := Snapshot{Values: []int{1}}
+	// 按值传入的结构体不可寻址，其字段不可设置
+	err := defaultManager.CopyReflectValueInto(reflect.ValueOf(src), reflect.ValueOf(Snapshot{}))
+	if !errors.Is(err, ErrNotSettable) {
+		t.Fatalf("expected ErrNotSettable, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "reflect.ValueOf(&v).Elem()") {
+		t.Errorf("error should explain how to fix the call: %v", err)
+	}
+
+	// 非结构体目标同样返回错误而不是 panic
+	for _, v := range []any{1, []int{1}, map[string]int{"a": 1}, &src} {
+		if err := defaultManager.CopyReflectValueInto(reflect.ValueOf(v), reflect.ValueOf(v)); !errors.Is(err, ErrNotSettable) {
+			t.Errorf("%T: expected ErrNotSettable, got %v", v, err)
+		}
+	}
+}
+
+func TestCopySliceInto(t *testing.T) {
+	src := []Snapshot{{Values: []int{1}}, {Values: []int{2}}}
+	dst := make([]Snapshot, 3, 4)
+	dst[2] = Snapshot{Values: []int{9}}
+	backing := &dst[:cap(dst)][0]
+	stale := dst[:3]
+
+	if err := CopySliceInto(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst, src) {
+		t.Fatalf("got %+v, want %+v", dst, src)
+	}
+	if &dst[0] != backing {
+		t.Error("destination backing array should be reused when capacity suffices")
+	}
+	if stale[2].Values != nil {
+		t.Error("elements beyond the new length should be zeroed")
+	}
+	dst[0].Values[0] = 100
+	if src[0].Values[0] != 1 {
+		t.Error("elements should be deep copied")
+	}
+
+	// 容量不足时重新分配
+	grown := []Snapshot{{}, {}, {}, {}, {}}
+	if err := CopySliceInto(&dst, grown); err != nil || len(dst) != 5 || &dst[0] == backing {
+		t.Errorf("expected a new backing array of length 5, got len %d, %v", len(dst), err)
+	}
+
+	ints := []int{1, 2, 3}
+	if err := CopySliceInto(&ints, nil); err != nil || len(ints) != 0 || cap(ints) != 3 {
+		t.Errorf("nil src should truncate dst, got %v, %v", ints, err)
+	}
+	if err := CopySliceInto(nil, ints); !errors.Is(err, ErrNilDestination) {
+		t.Errorf("expected ErrNilDestination, got %v", err)
+	}
+}
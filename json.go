@@ -0,0 +1,130 @@
+package deepcopy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// CopyJSON 通过 encoding/json 序列化往返创建 src 的副本，结果遵循 JSON 语义：
+// 调用自定义的 MarshalJSON / UnmarshalJSON，忽略未导出字段和 `json:"-"` 字段，
+// 接口中的动态值还原为 map[string]any、float64 等 JSON 对应的类型。
+// 适用于围绕 JSON 设计的类型，也可以作为对照来检查反射拷贝的结果
+func CopyJSON[T any](src T) (T, error) {
+	var dst T
+	data, err := json.Marshal(src)
+	if err != nil {
+		return dst, fmt.Errorf("deepcopy: marshal %T: %w", src, err)
+	}
+	if err := json.Unmarshal(data, &dst); err != nil {
+		return dst, fmt.Errorf("deepcopy: unmarshal %T: %w", dst, err)
+	}
+	return dst, nil
+}
+
+// 解码到 any 的 JSON 值的类型
+var (
+	jsonObjectType = reflect.TypeOf(map[string]any(nil))
+	jsonArrayType  = reflect.TypeOf([]any(nil))
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+)
+
+// CopyJSONLike 深拷贝 encoding/json 解码到 any 得到的值：由 map[string]any、[]any 和字符串、float64、bool、
+// json.Number、nil 组成的树。通过类型断言遍历，不使用反射，比反射拷贝快数倍；结果与 Copy 完全相同，
+// 同一个映射或切片被多处引用（包括循环引用）时副本中同样只有一份。值中出现其他类型时整体改用反射拷贝。
+// Copy 的参数类型为 any、map[string]any 或 []any 时会自动走这条路径，CopyJSONLike 只是明确表达意图
+func CopyJSONLike(v any) any {
+	return Copy(v)
+}
+
+// copyJSONLike T 为 any、map[string]any 或 []any 且 src 为 JSON 值时通过类型断言拷贝，否则返回 false
+func copyJSONLike[T any](src T) (T, bool) {
+	switch any((*T)(nil)).(type) {
+	case *any, *map[string]any, *[]any:
+	default:
+		return src, false
+	}
+	switch any(src).(type) {
+	case map[string]any, []any:
+	default:
+		return src, false
+	}
+
+	var c jsonCopier
+	copied, ok := c.copy(any(src))
+	if !ok {
+		return src, false
+	}
+	return copied.(T), true
+}
+
+// jsonCopier 记录已复制的映射和切片，与 Copy 一样保持共享和循环引用
+type jsonCopier struct {
+	objects map[unsafe.Pointer]map[string]any
+	arrays  map[jsonArrayKey][]any
+}
+
+// jsonArrayKey 切片的标识，与 refKey 相同包括长度和容量
+type jsonArrayKey struct {
+	data     *any
+	len, cap int
+}
+
+// copy 拷贝 JSON 值，遇到其他类型时返回 false
+func (c *jsonCopier) copy(v any) (any, bool) {
+	switch v := v.(type) {
+	case nil, string, float64, bool, json.Number:
+		return v, true
+
+	case map[string]any:
+		if v == nil {
+			return v, true
+		}
+		// 映射变量的内容即指向运行时映射结构的指针
+		key := *(*unsafe.Pointer)(unsafe.Pointer(&v))
+		if copied, ok := c.objects[key]; ok {
+			return copied, true
+		}
+		if c.objects == nil {
+			c.objects = make(map[unsafe.Pointer]map[string]any)
+		}
+		copied := make(map[string]any, len(v))
+		c.objects[key] = copied
+		for k, e := range v {
+			ce, ok := c.copy(e)
+			if !ok {
+				return nil, false
+			}
+			copied[k] = ce
+		}
+		return copied, true
+
+	case []any:
+		if v == nil {
+			return v, true
+		}
+		key := jsonArrayKey{data: unsafe.SliceData(v), len: len(v), cap: cap(v)}
+		if key.cap > 0 {
+			if copied, ok := c.arrays[key]; ok {
+				return copied, true
+			}
+		}
+		copied := make([]any, len(v), cap(v))
+		if key.cap > 0 {
+			if c.arrays == nil {
+				c.arrays = make(map[jsonArrayKey][]any)
+			}
+			c.arrays[key] = copied
+		}
+		for i, e := range v {
+			ce, ok := c.copy(e)
+			if !ok {
+				return nil, false
+			}
+			copied[i] = ce
+		}
+		return copied, true
+	}
+	return nil, false
+}
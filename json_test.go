@@ -0,0 +1,119 @@
+package deepcopy
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 序列化时把名称转为大写的账户
+type Account struct {
+	Name    string
+	Roles   []string
+	Limits  map[string]int
+	Manager *Account
+	secret  string
+}
+
+func (a Account) MarshalJSON() ([]byte, error) {
+	type plain Account
+	p := plain(a)
+	p.Name = strings.ToUpper(p.Name)
+	return json.Marshal(p)
+}
+
+func TestCopyJSON(t *testing.T) {
+	original := Account{
+		Name:    "alice",
+		Roles:   []string{"admin"},
+		Limits:  map[string]int{"cpu": 2},
+		Manager: &Account{Name: "bob"},
+		secret:  "hidden",
+	}
+
+	copied, err := CopyJSON(original)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if copied.Name != "ALICE" || copied.Manager.Name != "BOB" {
+		t.Errorf("custom MarshalJSON should be used, got %q and %q", copied.Name, copied.Manager.Name)
+	}
+	if copied.secret != "" {
+		t.Error("unexported fields should be omitted")
+	}
+	if !reflect.DeepEqual(copied.Roles, original.Roles) || !reflect.DeepEqual(copied.Limits, original.Limits) {
+		t.Errorf("got %+v, want %+v", copied, original)
+	}
+
+	copied.Roles[0] = "changed"
+	copied.Limits["cpu"] = 100
+	if original.Roles[0] != "admin" || original.Limits["cpu"] != 2 {
+		t.Error("the JSON copy should not share storage with the original")
+	}
+
+	if _, err := CopyJSON(make(chan int)); err == nil {
+		t.Error("expected an error for values JSON cannot encode")
+	}
+}
+
+// decodeJSONPayload 解码得到的 JSON 值，items 个条目，每个条目约 250 字节
+func decodeJSONPayload(tb testing.TB, items int) any {
+	tb.Helper()
+	var b strings.Builder
+	b.WriteString(`{"version":1,"items":[`)
+	for i := 0; i < items; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"id":%d,"name":"item-%d","price":%d.5,"active":true,"note":null,`+
+			`"tags":["a","b","c"],"dims":{"w":1,"h":2,"d":3},"history":[{"at":"2024-01-01","qty":%d}]}`, i, i, i, i)
+	}
+	b.WriteString(`]}`)
+	var v any
+	if err := json.Unmarshal([]byte(b.String()), &v); err != nil {
+		tb.Fatal(err)
+	}
+	return v
+}
+
+func TestCopyJSONLike(t *testing.T) {
+	original := decodeJSONPayload(t, 3)
+	copied := CopyJSONLike(original)
+	if !reflect.DeepEqual(copied, original) {
+		t.Fatalf("got %v, want %v", copied, original)
+	}
+	copied.(map[string]any)["items"].([]any)[0].(map[string]any)["tags"].([]any)[0] = "changed"
+	if original.(map[string]any)["items"].([]any)[0].(map[string]any)["tags"].([]any)[0] != "a" {
+		t.Error("copy should not share memory with the original")
+	}
+
+	// 共享的子树和循环引用与反射拷贝一致
+	shared := map[string]any{"n": json.Number("1")}
+	list := []any{shared, shared, nil}
+	list[2] = list
+	root := map[string]any{"list": list}
+	root["self"] = root
+	c := Copy(root)
+	cl := c["list"].([]any)
+	if reflect.ValueOf(cl[0]).Pointer() != reflect.ValueOf(cl[1]).Pointer() ||
+		reflect.ValueOf(cl[0]).Pointer() == reflect.ValueOf(shared).Pointer() {
+		t.Error("shared map should be copied once")
+	}
+	if &cl[2].([]any)[0] != &cl[0] || reflect.ValueOf(c["self"]).Pointer() != reflect.ValueOf(c).Pointer() {
+		t.Error("cycles should point into the copy")
+	}
+
+	// 其他类型的值整体改用反射拷贝
+	n := 1
+	mixed := []any{map[string]any{"p": &n}, "s"}
+	cm := Copy(mixed)
+	if p := cm[0].(map[string]any)["p"].(*int); p == &n || *p != 1 {
+		t.Errorf("pointer inside JSON-like value should be deep copied, got %p", p)
+	}
+
+	if Copy(map[string]any(nil)) != nil || Copy([]any{}) == nil {
+		t.Error("nil and empty values should be preserved")
+	}
+}
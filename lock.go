@@ -0,0 +1,39 @@
+package deepcopy
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// lockOption 标签选项，如 `deepcopy:"lock"`：标在锁字段（sync.Mutex、sync.RWMutex 或 RegisterLocker 注册的类型）上，
+// 拷贝所在结构体的其余字段期间持有该锁（读写锁使用读锁），拷贝结束或 panic 时释放，得到受该锁保护的字段的一致快照。
+// 只对经由指针、切片、映射等可寻址位置到达的结构体生效（按值传入的结构体在调用时已被无锁复制），
+// 锁字段在副本中为零值；结构体由 DeepCopy 方法或自定义拷贝函数拷贝时不加锁
+const lockOption = "lock"
+
+// isLockField 字段是否为带 lock 标签的锁
+func (m *DeepCopyManager) isLockField(field reflect.StructField) bool {
+	if !m.hasTagOption(field, lockOption) {
+		return false
+	}
+	_, ok := lockerFor(field.Type)
+	return ok
+}
+
+// holdLocks 获取可寻址结构体 v 中下标为 indices 的锁字段，返回按相反顺序释放的函数
+func holdLocks(v reflect.Value, indices []int) func() {
+	held := make([]heldLock, 0, len(indices))
+	for _, i := range indices {
+		field := v.Field(i)
+		lf, _ := lockerFor(field.Type())
+		// 锁通常是未导出字段，需要通过地址重新构造才能调用其方法
+		ptr := reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr()))
+		lf.lock(ptr)
+		held = append(held, heldLock{ptr: ptr, unlock: lf.unlock})
+	}
+	return func() {
+		for i := len(held) - 1; i >= 0; i-- {
+			held[i].unlock(held[i].ptr)
+		}
+	}
+}
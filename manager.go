@@ -0,0 +1,262 @@
+package deepcopy
+
+import (
+	"container/list"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultTagKey 默认的结构体标签名
+const DefaultTagKey = "deepcopy"
+
+// ManagerOption 深拷贝管理器的配置项
+type ManagerOption func(*DeepCopyManager)
+
+// CopyFunc 自定义拷贝函数，返回值需要能赋值给源值的类型
+type CopyFunc func(src any) any
+
+// WithCacheSize 限制类型分析缓存的条目数，超出时淘汰最久未使用的类型；max <= 0 表示不限制（默认）。
+// 分析结果创建后不再修改，正在进行的拷贝持有的结果被淘汰后仍可安全使用，之后再用到该类型时重新分析。
+// 适用于 reflect.StructOf、插件等不断产生新类型的场景，淘汰次数见 CacheStats
+func WithCacheSize(max int) ManagerOption {
+	return func(m *DeepCopyManager) {
+		if max > 0 {
+			m.lru = newAnalysisLRU(max)
+		} else {
+			m.lru = nil
+		}
+	}
+}
+
+// WithBuiltinHandlers 是否启用内置的特殊处理（time.Time、netip 等不可变类型的共享，
+// 实现了序列化接口的类型的序列化往返拷贝，以及 container/list、container/ring 的重建），默认启用
+func WithBuiltinHandlers(enabled bool) ManagerOption {
+	return func(m *DeepCopyManager) {
+		m.disableBuiltins = !enabled
+	}
+}
+
+// WithTagKey 设置结构体标签名，默认为 DefaultTagKey
+func WithTagKey(key string) ManagerOption {
+	return func(m *DeepCopyManager) {
+		m.tagKey = key
+	}
+}
+
+// WithLogger 使用 slog 记录警告（例如按原样拷贝 unsafe.Pointer），未设置时使用 WarnFunc
+func WithLogger(logger *slog.Logger) ManagerOption {
+	return func(m *DeepCopyManager) {
+		m.logger = logger
+	}
+}
+
+// WithCustomCopiers 为指定类型注册自定义拷贝函数，优先于 DeepCopy 方法和默认的反射拷贝
+func WithCustomCopiers(copiers map[reflect.Type]CopyFunc) ManagerOption {
+	return func(m *DeepCopyManager) {
+		if m.customCopiers == nil {
+			m.customCopiers = make(map[reflect.Type]CopyFunc, len(copiers))
+		}
+		for t, fn := range copiers {
+			m.customCopiers[t] = fn
+		}
+	}
+}
+
+// interfaceCopier 按接口注册的自定义拷贝函数
+type interfaceCopier struct {
+	iface reflect.Type
+	fn    CopyFunc
+}
+
+// WithInterfaceCopier 为实现了接口 iface 的所有类型注册自定义拷贝函数，拷贝时按值（包括接口、指针、映射中的值）
+// 的具体类型匹配，fn 收到并返回该具体类型的值。优先级：按类型注册的函数 > 按接口注册的函数 > DeepCopy 方法 > 默认的反射拷贝；
+// 一个类型实现了多个注册过的接口时使用最先注册的。值接收者实现接口时，指向该类型的指针同样实现了接口，会以指针传入 fn
+func WithInterfaceCopier(iface reflect.Type, fn CopyFunc) ManagerOption {
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("deepcopy: WithInterfaceCopier needs an interface type, got %s", iface))
+	}
+	return func(m *DeepCopyManager) {
+		m.ifaceCopiers = append(m.ifaceCopiers, interfaceCopier{iface: iface, fn: fn})
+	}
+}
+
+// RegisterCopierForInterface 为默认管理器注册接口 iface 的自定义拷贝函数，见 WithInterfaceCopier
+// 与 SetDefaultManagerOptions 相同，不能与拷贝同时进行
+func RegisterCopierForInterface(iface reflect.Type, fn func(any) any) {
+	SetDefaultManagerOptions(WithInterfaceCopier(iface, fn))
+}
+
+// interfaceCopierFor 返回非接口类型 t 实现的第一个注册过拷贝函数的接口对应的函数
+func (m *DeepCopyManager) interfaceCopierFor(t reflect.Type) CopyFunc {
+	for _, c := range m.ifaceCopiers {
+		if t.Implements(c.iface) {
+			return c.fn
+		}
+	}
+	return nil
+}
+
+// RegisterCopier 为默认管理器注册类型 T 的自定义拷贝函数，见 WithCustomCopiers
+// 与 SetDefaultManagerOptions 相同，不能与拷贝同时进行
+func RegisterCopier[T any](fn func(T) T) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	SetDefaultManagerOptions(WithCustomCopiers(map[reflect.Type]CopyFunc{
+		t: func(src any) any { return fn(src.(T)) },
+	}))
+}
+
+// RegisterShallowType 默认管理器拷贝类型 T 的值时直接共享（浅拷贝），例如只读的大型配置或外部句柄
+func RegisterShallowType[T any]() {
+	RegisterCopier(func(src T) T { return src })
+}
+
+// SetDefaultManagerOptions 配置 Copy 等包级函数使用的默认管理器
+// 不是并发安全的，不能与拷贝同时进行（通常在 init() 中调用）；之前缓存的分析结果在下次使用时重新分析
+func SetDefaultManagerOptions(opts ...ManagerOption) {
+	for _, opt := range opts {
+		opt(defaultManager)
+	}
+	defaultManager.shortcutOff.Store(defaultManager.overridesShortcutTypes())
+	// 已缓存的分析结果（包括包含被配置类型的外层类型）在下次使用时重新分析
+	defaultManager.generation.Add(1)
+	defaultManager.forgetFieldMappings(nil)
+}
+
+// TagKey 返回管理器使用的结构体标签名
+func (m *DeepCopyManager) TagKey() string {
+	if m.tagKey == "" {
+		return DefaultTagKey
+	}
+	return m.tagKey
+}
+
+// hasTagOption 字段的标签（标签名见 TagKey）是否包含选项 opt，选项之间以逗号分隔
+func (m *DeepCopyManager) hasTagOption(field reflect.StructField, opt string) bool {
+	tag, ok := field.Tag.Lookup(m.TagKey())
+	if !ok {
+		return false
+	}
+	for _, o := range strings.Split(tag, ",") {
+		if strings.TrimSpace(o) == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// warn 输出警告，优先使用配置的 slog.Logger
+func (m *DeepCopyManager) warn(format string, args ...any) {
+	switch {
+	case m.logger != nil:
+		m.logger.Warn(fmt.Sprintf(format, args...))
+	case WarnFunc != nil:
+		WarnFunc(format, args...)
+	}
+}
+
+// loadAnalysis 从缓存读取类型分析结果
+func (m *DeepCopyManager) loadAnalysis(t reflect.Type) (*TypeAnalysisResult, bool) {
+	var result *TypeAnalysisResult
+	if m.lru != nil {
+		result, _ = m.lru.get(t)
+	} else if cached, ok := m.analysisCache.Load(t); ok {
+		result = cached.(*TypeAnalysisResult)
+	}
+	// 配置变化前的分析结果视为未命中
+	if result == nil || result.generation != m.generation.Load() {
+		return nil, false
+	}
+	return result, true
+}
+
+// storeAnalysis 缓存类型分析结果
+func (m *DeepCopyManager) storeAnalysis(t reflect.Type, result *TypeAnalysisResult) {
+	if m.lru != nil {
+		m.lru.put(t, result)
+		return
+	}
+	m.analysisCache.Store(t, result)
+}
+
+// forgetAnalysis 移除缓存的类型分析结果
+func (m *DeepCopyManager) forgetAnalysis(t reflect.Type) {
+	if m.lru != nil {
+		m.lru.remove(t)
+		return
+	}
+	m.analysisCache.Delete(t)
+}
+
+// analysisLRU 容量受限的类型分析缓存
+type analysisLRU struct {
+	mu        sync.Mutex
+	max       int
+	order     *list.List                     // 队首为最近使用
+	entries   map[reflect.Type]*list.Element // 元素值为 *lruEntry
+	evictions atomic.Uint64                  // 超出容量淘汰的条目数
+}
+
+// lruEntry 缓存条目
+type lruEntry struct {
+	t      reflect.Type
+	result *TypeAnalysisResult
+}
+
+// newAnalysisLRU 创建容量为 max 的缓存
+func newAnalysisLRU(max int) *analysisLRU {
+	return &analysisLRU{
+		max:     max,
+		order:   list.New(),
+		entries: make(map[reflect.Type]*list.Element),
+	}
+}
+
+// get 读取并标记为最近使用
+func (c *analysisLRU) get(t reflect.Type) (*TypeAnalysisResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[t]; ok {
+		c.order.MoveToFront(elem)
+		return elem.Value.(*lruEntry).result, true
+	}
+	return nil, false
+}
+
+// put 写入，超出容量时淘汰最久未使用的条目
+func (c *analysisLRU) put(t reflect.Type, result *TypeAnalysisResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[t]; ok {
+		elem.Value.(*lruEntry).result = result
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[t] = c.order.PushFront(&lruEntry{t: t, result: result})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).t)
+		c.evictions.Add(1)
+	}
+}
+
+// remove 删除条目
+func (c *analysisLRU) remove(t reflect.Type) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[t]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, t)
+	}
+}
+
+// len 当前条目数
+func (c *analysisLRU) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
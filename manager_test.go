@@ -0,0 +1,375 @@
+package deepcopy
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// 由自定义拷贝函数处理的类型
+type customCopied struct {
+	Values []int
+}
+
+type customCopiedHolder struct {
+	Inner customCopied
+	Ptr   *customCopied
+}
+
+func TestManagerCustomCopiers(t *testing.T) {
+	calls := 0
+	m := NewDeepCopyManager(WithCustomCopiers(map[reflect.Type]CopyFunc{
+		reflect.TypeOf(customCopied{}): func(src any) any {
+			calls++
+			return customCopied{Values: append([]int{-1}, src.(customCopied).Values...)}
+		},
+	}))
+
+	original := customCopiedHolder{Inner: customCopied{Values: []int{1}}, Ptr: &customCopied{Values: []int{2}}}
+	copied := m.CopyValue(original).(customCopiedHolder)
+	if calls != 2 {
+		t.Errorf("custom copier called %d times, want 2", calls)
+	}
+	if !reflect.DeepEqual(copied.Inner.Values, []int{-1, 1}) || !reflect.DeepEqual(copied.Ptr.Values, []int{-1, 2}) {
+		t.Errorf("unexpected copy %+v %+v", copied.Inner, copied.Ptr)
+	}
+
+	// 默认管理器不受影响
+	if Copy(original).Inner.Values[0] != 1 {
+		t.Error("custom copiers should be scoped to their manager")
+	}
+}
+
+// ifaceEvent 由按接口注册的拷贝函数处理的事件
+type ifaceEvent interface {
+	Source() string
+}
+
+type clickEvent struct {
+	From string
+	Tags []string
+}
+
+func (e clickEvent) Source() string { return e.From }
+
+// keyEvent 同时实现了 DeepCopy，按接口注册的函数优先
+type keyEvent struct {
+	From string
+}
+
+func (e keyEvent) Source() string { return e.From }
+
+func (e keyEvent) DeepCopy() keyEvent { return keyEvent{From: "deepcopy"} }
+
+// scrollEvent 另有按类型注册的函数，优先于按接口注册的函数
+type scrollEvent struct {
+	From string
+}
+
+func (e scrollEvent) Source() string { return e.From }
+
+type eventLog struct {
+	First  ifaceEvent
+	Ptr    *clickEvent
+	ByName map[string]ifaceEvent
+	Key    keyEvent
+	Scroll scrollEvent
+	Plain  customCopied
+}
+
+func TestManagerInterfaceCopier(t *testing.T) {
+	var seen []string
+	normalize := func(src any) any {
+		seen = append(seen, reflect.TypeOf(src).String())
+		switch e := src.(type) {
+		case clickEvent:
+			return clickEvent{From: strings.ToUpper(e.From), Tags: append([]string(nil), e.Tags...)}
+		case *clickEvent:
+			return &clickEvent{From: strings.ToUpper(e.From)}
+		case keyEvent:
+			return keyEvent{From: "interface"}
+		}
+		return src
+	}
+	m := NewDeepCopyManager(
+		WithInterfaceCopier(reflect.TypeOf((*ifaceEvent)(nil)).Elem(), normalize),
+		WithCustomCopiers(map[reflect.Type]CopyFunc{
+			reflect.TypeOf(scrollEvent{}): func(src any) any { return scrollEvent{From: "concrete"} },
+		}),
+	)
+
+	original := eventLog{
+		First:  clickEvent{From: "mouse", Tags: []string{"a"}},
+		Ptr:    &clickEvent{From: "pad"},
+		ByName: map[string]ifaceEvent{"k": keyEvent{From: "keyboard"}},
+		Key:    keyEvent{From: "keyboard"},
+		Scroll: scrollEvent{From: "wheel"},
+		Plain:  customCopied{Values: []int{1}},
+	}
+	copied := m.CopyValue(original).(eventLog)
+
+	if got := copied.First.(clickEvent); got.From != "MOUSE" || &got.Tags[0] == &original.First.(clickEvent).Tags[0] {
+		t.Errorf("interface value: %+v", got)
+	}
+	if copied.Ptr.From != "PAD" || copied.Ptr == original.Ptr {
+		t.Errorf("pointer implementing the interface: %+v", copied.Ptr)
+	}
+	// 按接口注册的函数优先于 DeepCopy 方法
+	if copied.ByName["k"].Source() != "interface" || copied.Key.From != "interface" {
+		t.Errorf("interface copier should beat DeepCopy: %+v, %+v", copied.ByName["k"], copied.Key)
+	}
+	// 按类型注册的函数优先于按接口注册的函数
+	if copied.Scroll.From != "concrete" {
+		t.Errorf("concrete copier should beat interface copier: %+v", copied.Scroll)
+	}
+	// 未实现接口的类型按结构拷贝
+	if !reflect.DeepEqual(copied.Plain, original.Plain) || &copied.Plain.Values[0] == &original.Plain.Values[0] {
+		t.Errorf("structural copy: %+v", copied.Plain)
+	}
+	if want := []string{"deepcopy.clickEvent", "*deepcopy.clickEvent", "deepcopy.keyEvent", "deepcopy.keyEvent"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("copier called with %v, want %v", seen, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("non-interface type should panic")
+		}
+	}()
+	WithInterfaceCopier(reflect.TypeOf(clickEvent{}), normalize)
+}
+
+func TestRegisterCopierForInterface(t *testing.T) {
+	t.Cleanup(func() {
+		defaultManager.ifaceCopiers = nil
+		defaultManager.generation.Add(1)
+	})
+	original := []ifaceEvent{clickEvent{From: "mouse"}}
+	if Copy(original)[0].Source() != "mouse" {
+		t.Fatal("unexpected copy before registration")
+	}
+
+	RegisterCopierForInterface(reflect.TypeOf((*ifaceEvent)(nil)).Elem(), func(src any) any {
+		return clickEvent{From: "registered"}
+	})
+	if got := Copy(original)[0].Source(); got != "registered" {
+		t.Errorf("Source = %q, want registered", got)
+	}
+}
+
+func TestManagerCacheSize(t *testing.T) {
+	m := NewDeepCopyManager(WithCacheSize(2))
+	m.AnalyzeValue(1)
+	m.AnalyzeValue("a")
+	m.AnalyzeValue(1)   // 标记 int 为最近使用
+	m.AnalyzeValue(1.5) // 淘汰 string
+
+	if n := m.lru.len(); n != 2 {
+		t.Fatalf("cache size = %d, want 2", n)
+	}
+	if _, ok := m.loadAnalysis(reflect.TypeOf("")); ok {
+		t.Error("least recently used entry should be evicted")
+	}
+	if _, ok := m.loadAnalysis(reflect.TypeOf(0)); !ok {
+		t.Error("recently used entry should be kept")
+	}
+}
+
+// 并发拷贝不断产生的匿名结构体类型，缓存条目数不超过上限，被淘汰的结果仍可使用
+func TestManagerCacheSizeChurn(t *testing.T) {
+	const limit, types, workers = 16, 400, 8
+	m := NewDeepCopyManager(WithCacheSize(limit))
+
+	newValue := func(i int) reflect.Value {
+		typ := reflect.StructOf([]reflect.StructField{
+			{Name: "ID", Type: reflect.TypeOf(0)},
+			{Name: "Tags", Type: reflect.TypeOf([]string(nil)), Tag: reflect.StructTag(`json:"t` + strconv.Itoa(i) + `"`)},
+		})
+		v := reflect.New(typ).Elem()
+		v.Field(0).SetInt(int64(i))
+		v.Field(1).Set(reflect.ValueOf([]string{strconv.Itoa(i)}))
+		return v
+	}
+
+	held := m.AnalyzeValue(newValue(-1).Interface())
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < types; i += workers {
+				src := newValue(i)
+				copied := reflect.ValueOf(m.CopyValue(src.Interface()))
+				if copied.Field(0).Int() != int64(i) || copied.Field(1).Index(0).String() != strconv.Itoa(i) {
+					t.Errorf("type %d copied incorrectly: %v", i, copied)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	stats := m.CacheStats()
+	if stats.AnalyzedTypes > limit || m.lru.len() > limit {
+		t.Errorf("cache holds %d entries, limit %d", stats.AnalyzedTypes, limit)
+	}
+	if stats.Evictions < types+1-limit {
+		t.Errorf("evictions = %d, want at least %d", stats.Evictions, types+1-limit)
+	}
+	if held.IsOnlyValues || !held.ContainsSlice {
+		t.Errorf("evicted analysis result should stay intact: %+v", held)
+	}
+	if NewDeepCopyManager().CacheStats().Evictions != 0 {
+		t.Error("unbounded cache should not evict")
+	}
+}
+
+func TestManagerBuiltinHandlers(t *testing.T) {
+	if !NewDeepCopyManager().AnalyzeValue(time.Time{}).IsOnlyValues {
+		t.Error("time.Time should be value-only with builtin handlers")
+	}
+	if NewDeepCopyManager(WithBuiltinHandlers(false)).AnalyzeValue(time.Time{}).IsOnlyValues {
+		t.Error("time.Time should not be special-cased without builtin handlers")
+	}
+}
+
+func TestManagerLoggerAndTagKey(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewDeepCopyManager(WithLogger(slog.New(slog.NewTextHandler(&buf, nil))), WithTagKey("copy"))
+
+	value := 1
+	m.CopyValue(UnsafeHolder{Raw: unsafe.Pointer(&value)})
+	if !strings.Contains(buf.String(), "unsafe.Pointer") {
+		t.Errorf("warning should go to the logger, got %q", buf.String())
+	}
+	if m.TagKey() != "copy" || NewDeepCopyManager().TagKey() != DefaultTagKey {
+		t.Error("unexpected tag key")
+	}
+}
+
+func TestSetDefaultManagerOptions(t *testing.T) {
+	defer SetDefaultManagerOptions(WithTagKey(""))
+	SetDefaultManagerOptions(WithTagKey("custom"))
+	if defaultManager.TagKey() != "custom" {
+		t.Errorf("default manager tag key = %q, want custom", defaultManager.TagKey())
+	}
+}
+
+// sharedBuffer 注册为浅拷贝类型后直接共享
+type sharedBuffer struct {
+	Data []byte
+}
+
+type bufferHolder struct {
+	Buf sharedBuffer
+}
+
+// sensorReading 只包含值类型，外层类型最初被分析为可以直接返回原值
+type sensorReading struct {
+	Value int
+}
+
+type readingBatch struct {
+	Readings [2]sensorReading
+}
+
+// unregisterCopier 测试结束后移除默认管理器中注册的拷贝函数
+func unregisterCopier[T any](t *testing.T) {
+	t.Cleanup(func() {
+		delete(defaultManager.customCopiers, reflect.TypeOf((*T)(nil)).Elem())
+		defaultManager.shortcutOff.Store(defaultManager.overridesShortcutTypes())
+		defaultManager.generation.Add(1)
+	})
+}
+
+// 基础类型直接返回，为其注册了拷贝函数后改用注册的函数
+func TestRegisterCopierForBasicType(t *testing.T) {
+	type label string
+	if Copy("a") != "a" || Copy(3.5) != 3.5 || Copy(label("l")) != "l" || Copy(uintptr(1)) != 1 {
+		t.Fatal("basic values should be returned as is")
+	}
+
+	unregisterCopier[string](t)
+	RegisterCopier(func(s string) string { return strings.ToUpper(s) })
+	if got := Copy("a"); got != "A" {
+		t.Errorf("Copy(string) = %q, want the registered copier's result", got)
+	}
+	if Copy(42) != 42 {
+		t.Error("other basic types are unaffected")
+	}
+}
+
+// 注册后递增配置版本，之前缓存的分析结果自动失效
+func TestRegisterInvalidatesAnalysis(t *testing.T) {
+	holder := bufferHolder{Buf: sharedBuffer{Data: []byte("abc")}}
+	if copied := Copy(holder); &copied.Buf.Data[0] == &holder.Buf.Data[0] {
+		t.Fatal("Data should be deep copied before registration")
+	}
+
+	unregisterCopier[sharedBuffer](t)
+	RegisterShallowType[sharedBuffer]()
+	if copied := Copy(holder); &copied.Buf.Data[0] != &holder.Buf.Data[0] {
+		t.Error("Data should be shared after RegisterShallowType")
+	}
+
+	batch := readingBatch{Readings: [2]sensorReading{{1}, {2}}}
+	if !AnalyzeType(batch).IsOnlyValues || CopyWithKey(batch, "test.readings") != batch {
+		t.Fatal("readingBatch should start as value-only")
+	}
+
+	unregisterCopier[sensorReading](t)
+	RegisterCopier(func(r sensorReading) sensorReading { return sensorReading{Value: r.Value * 10} })
+	want := readingBatch{Readings: [2]sensorReading{{10}, {20}}}
+	if got := Copy(batch); got != want {
+		t.Errorf("Copy = %+v, want %+v", got, want)
+	}
+	if got := CopyWithKey(batch, "test.readings"); got != want {
+		t.Errorf("CopyWithKey = %+v, want %+v", got, want)
+	}
+	if AnalyzeType(batch).IsOnlyValues {
+		t.Error("re-analysis should see the registered copier")
+	}
+}
+
+// CopyValue 返回的副本不论在接口中直接还是间接存储，都与原值相等且相互独立
+func TestManagerCopyValueShapes(t *testing.T) {
+	n := 1
+	type onePtr struct{ P *int }
+	type oneInt struct{ N int64 }
+	type empty struct{}
+	m := NewDeepCopyManager()
+
+	values := []any{
+		customCopiedHolder{},
+		onePtr{P: &n},
+		oneInt{N: 2},
+		empty{},
+		[1]*int{&n},
+		[3][]int{{1}, {2, 3}, nil},
+		[]string{"a"},
+		map[string][]int{"a": {1}},
+		&n,
+		"s",
+		3.5,
+	}
+	for _, v := range values {
+		copied := m.CopyValue(v)
+		if !reflect.DeepEqual(copied, v) {
+			t.Errorf("CopyValue(%#v) = %#v", v, copied)
+		}
+	}
+
+	src := [3][]int{{1}, {2, 3}, nil}
+	copied := m.CopyValue(src).([3][]int)
+	copied[1][0] = 100
+	if src[1][0] != 2 {
+		t.Error("copy shares memory with the original")
+	}
+	if got := m.CopyValue(onePtr{P: &n}).(onePtr); got.P == &n || *got.P != 1 {
+		t.Errorf("pointer-shaped struct: %+v", got)
+	}
+}
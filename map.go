@@ -0,0 +1,96 @@
+package deepcopy
+
+import "reflect"
+
+// mapCopyConfig CopyMap 的配置
+type mapCopyConfig[K comparable, V any] struct {
+	keyTransformer   func(K) K       // 键转换
+	valueFilter      func(K, V) bool // 条目过滤，返回 false 的条目被跳过
+	valueTransformer func(K, V) V    // 值转换，作用于拷贝后的值
+}
+
+// MapCopyOption CopyMap 的选项
+type MapCopyOption[K comparable, V any] func(*mapCopyConfig[K, V])
+
+// WithKeyTransformer 在拷贝时转换键，例如统一转为小写
+// 转换后发生冲突的键只保留其中一个条目（取决于 map 的遍历顺序）。
+// fn 中没有值类型，V 无法推断，需要显式实例化，例如 WithKeyTransformer[string, int](strings.ToLower)；
+// 键、值类型与 CopyMap 的 map 不一致时编译失败
+func WithKeyTransformer[K comparable, V any](fn func(K) K) MapCopyOption[K, V] {
+	return func(c *mapCopyConfig[K, V]) {
+		c.keyTransformer = fn
+	}
+}
+
+// WithValueFilter 过滤条目，fn 收到原始的键和值，返回 false 时跳过该条目
+func WithValueFilter[K comparable, V any](fn func(K, V) bool) MapCopyOption[K, V] {
+	return func(c *mapCopyConfig[K, V]) {
+		c.valueFilter = fn
+	}
+}
+
+// WithValueTransformer 转换值，fn 收到原始的键和拷贝后的值
+func WithValueTransformer[K comparable, V any](fn func(K, V) V) MapCopyOption[K, V] {
+	return func(c *mapCopyConfig[K, V]) {
+		c.valueTransformer = fn
+	}
+}
+
+// CopyMap 深拷贝 map，并可在拷贝过程中过滤条目、转换键和值
+// 键和值分别通过 Copy 进行深拷贝，不同条目之间共享的指针在副本中不再共享
+func CopyMap[K comparable, V any](src map[K]V, opts ...MapCopyOption[K, V]) map[K]V {
+	if src == nil {
+		return nil
+	}
+
+	var cfg mapCopyConfig[K, V]
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	dst := make(map[K]V, len(src))
+	for key, value := range src {
+		if cfg.valueFilter != nil && !cfg.valueFilter(key, value) {
+			continue
+		}
+
+		copyValue := Copy(value)
+		if cfg.valueTransformer != nil {
+			copyValue = cfg.valueTransformer(key, copyValue)
+		}
+
+		copyKey := Copy(key)
+		if cfg.keyTransformer != nil {
+			copyKey = cfg.keyTransformer(copyKey)
+		}
+
+		dst[copyKey] = copyValue
+	}
+
+	return dst
+}
+
+// CopyMapInto 清空 dst 后将 src 的条目深拷贝到 dst 中，复用 dst 已分配的桶，
+// 适合反复刷新同一个长期存在的 map 的场景。dst 为 nil 时返回 ErrNilDestination，src 为 nil 时只清空 dst；
+// dst 与 src 为同一个 map 时条目被替换为各自的深拷贝
+func CopyMapInto[K comparable, V any](dst, src map[K]V) error {
+	if dst == nil {
+		return ErrNilDestination
+	}
+
+	// 同一个 map 先拷贝出全部条目，否则 clear 后 src 也为空
+	if reflect.ValueOf(dst).UnsafePointer() == reflect.ValueOf(src).UnsafePointer() {
+		copied := Copy(src)
+		clear(dst)
+		for key, value := range copied {
+			dst[key] = value
+		}
+		return nil
+	}
+
+	clear(dst)
+	for key, value := range src {
+		dst[Copy(key)] = Copy(value)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCopyMapKeyTransformer(t *testing.T) {
+	original := map[string][]int{
+		"Alpha": {1},
+		"BETA":  {2, 3},
+		"gamma": {4},
+	}
+
+	copied := CopyMap(original, WithKeyTransformer[string, []int](strings.ToLower))
+
+	if len(copied) != len(original) {
+		t.Fatalf("len: got %d, want %d", len(copied), len(original))
+	}
+	for key, value := range original {
+		got, ok := copied[strings.ToLower(key)]
+		if !ok {
+			t.Errorf("key %q should appear lowercased in the copy", key)
+			continue
+		}
+		if len(got) != len(value) || &got[0] == &value[0] {
+			t.Errorf("value of %q should be an independent copy", key)
+		}
+	}
+	if _, ok := original["alpha"]; ok {
+		t.Error("original should not be modified")
+	}
+}
+
+func TestCopyMapFilterAndValueTransformer(t *testing.T) {
+	original := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	copied := CopyMap(original,
+		WithValueFilter(func(k string, v int) bool { return v%2 == 1 }),
+		WithValueTransformer(func(k string, v int) int { return v * 10 }),
+	)
+
+	want := map[string]int{"a": 10, "c": 30}
+	if len(copied) != len(want) {
+		t.Fatalf("got %v, want %v", copied, want)
+	}
+	for k, v := range want {
+		if copied[k] != v {
+			t.Errorf("%s: got %d, want %d", k, copied[k], v)
+		}
+	}
+}
+
+func TestCopyMapNil(t *testing.T) {
+	var original map[string]int
+	if CopyMap(original) != nil {
+		t.Error("nil map should copy to nil")
+	}
+}
+
+func TestCopyMapInto(t *testing.T) {
+	src := map[string][]int{"a": {1}, "b": {2, 3}}
+	dst := map[string][]int{"stale": {9}}
+
+	if err := CopyMapInto(dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst, src) {
+		t.Fatalf("got %v, want %v", dst, src)
+	}
+	dst["a"][0] = 100
+	if src["a"][0] != 1 {
+		t.Error("values should be deep copied")
+	}
+
+	// nil 源只清空目标
+	if err := CopyMapInto(dst, nil); err != nil || len(dst) != 0 {
+		t.Errorf("nil src should clear dst, got %v, %v", dst, err)
+	}
+	if err := CopyMapInto(nil, src); !errors.Is(err, ErrNilDestination) {
+		t.Errorf("expected ErrNilDestination, got %v", err)
+	}
+
+	// dst 与 src 为同一个 map 时保留条目，值替换为深拷贝
+	shared := []int{1}
+	same := map[string][]int{"a": shared}
+	if err := CopyMapInto(same, same); err != nil || !reflect.DeepEqual(same, map[string][]int{"a": {1}}) {
+		t.Fatalf("aliased: got %v, %v", same, err)
+	}
+	same["a"][0] = 100
+	if shared[0] != 1 {
+		t.Error("aliased: values should be replaced with deep copies")
+	}
+}
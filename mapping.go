@@ -0,0 +1,192 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValidationError CopyWithMapping 的字段映射与结构体不符，映射按结构体类型检查，同一个映射每次都返回该错误
+type ValidationError struct {
+	Type         reflect.Type
+	Unknown      []string // 结构体中不存在或未导出的字段名（源和目标）
+	Incompatible []string // 类型不同的映射，如 "Age -> Years (string -> int)"
+	Duplicate    []string // 被多个源字段映射到的目标字段
+}
+
+func (e *ValidationError) Error() string {
+	var parts []string
+	if len(e.Unknown) > 0 {
+		parts = append(parts, "unknown fields: "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Incompatible) > 0 {
+		parts = append(parts, "incompatible: "+strings.Join(e.Incompatible, ", "))
+	}
+	if len(e.Duplicate) > 0 {
+		parts = append(parts, "mapped more than once: "+strings.Join(e.Duplicate, ", "))
+	}
+	return fmt.Sprintf("deepcopy: invalid field mapping for %s (%s)", e.Type, strings.Join(parts, "; "))
+}
+
+// fieldMove 副本中把字段 from 的值移动到字段 to
+type fieldMove struct {
+	from, to int
+}
+
+// fieldMapping 编译后的字段映射
+type fieldMapping struct {
+	moves      []fieldMove
+	clear      []int // 被移走且不是映射目标的源字段，副本中为零值
+	err        error
+	generation uint64 // 编译时管理器配置的版本
+}
+
+// fieldMappingKey 字段映射的缓存键，fingerprint 为排序后的映射
+type fieldMappingKey struct {
+	t           reflect.Type
+	fingerprint string
+}
+
+// maxFieldMappings 管理器缓存的字段映射数量上限，每次调用都传入不同映射时缓存不会无限增长
+const maxFieldMappings = 1024
+
+// CopyWithMapping 深拷贝 src，同时按 fieldMap（源字段名 -> 目标字段名）在同一类型内移动顶层字段的值，
+// 用于字段改名的迁移期间：副本中 NewField 取 OldField 的值，OldField 为零值（OldField 本身也是映射目标时取其映射来的值，
+// 因此 {"A": "B", "B": "A"} 交换两个字段）。字段须为导出字段且类型相同，映射无效时返回 *ValidationError。
+// T 须为结构体或结构体指针，编译后的映射按类型和映射内容缓存在默认管理器中，ResetTypeCache 和 SetDefaultManagerOptions 后重新编译
+func CopyWithMapping[T any](src T, fieldMap map[string]string) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return zero, fmt.Errorf("deepcopy: CopyWithMapping needs a struct or a pointer to struct, got %s", t)
+	}
+
+	mapping := defaultManager.fieldMapping(st, fieldMap)
+	if mapping.err != nil {
+		return zero, mapping.err
+	}
+	copied, err := CopyE(src)
+	if err != nil {
+		return zero, err
+	}
+
+	v := reflect.ValueOf(&copied).Elem()
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return copied, nil
+		}
+		v = v.Elem()
+	}
+	// 先取出所有源值再写入，交换等互相映射的字段不会读到已写入的值
+	values := make([]reflect.Value, len(mapping.moves))
+	for i, m := range mapping.moves {
+		values[i] = reflect.New(st.Field(m.from).Type).Elem()
+		values[i].Set(v.Field(m.from))
+	}
+	for _, i := range mapping.clear {
+		v.Field(i).SetZero()
+	}
+	for i, m := range mapping.moves {
+		v.Field(m.to).Set(values[i])
+	}
+	return copied, nil
+}
+
+// fieldMapping 获取或编译结构体类型 t 的字段映射，配置变化前编译的映射视为未命中
+func (m *DeepCopyManager) fieldMapping(t reflect.Type, fieldMap map[string]string) *fieldMapping {
+	froms, fp := renameFingerprint(fieldMap)
+	key := fieldMappingKey{t: t, fingerprint: fp}
+	generation := m.generation.Load()
+	if cached, ok := m.fieldMappings.Load(key); ok && cached.(*fieldMapping).generation == generation {
+		return cached.(*fieldMapping)
+	}
+
+	mapping := compileFieldMapping(t, froms, fieldMap)
+	mapping.generation = generation
+	if _, loaded := m.fieldMappings.Swap(key, mapping); !loaded && m.fieldMappingCount.Add(1) > maxFieldMappings {
+		m.forgetFieldMappings(nil)
+	}
+	return mapping
+}
+
+// forgetFieldMappings 移除结构体类型 t（t 为指针时为其指向的结构体）缓存的字段映射，t 为 nil 时全部移除
+func (m *DeepCopyManager) forgetFieldMappings(t reflect.Type) {
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	m.fieldMappings.Range(func(key, _ any) bool {
+		if t == nil || key.(fieldMappingKey).t == t {
+			if _, loaded := m.fieldMappings.LoadAndDelete(key); loaded {
+				m.fieldMappingCount.Add(-1)
+			}
+		}
+		return true
+	})
+}
+
+// renameFingerprint 排序后的源字段名，以及由排序后的映射拼接成的字符串，用作缓存键
+func renameFingerprint(fieldMap map[string]string) ([]string, string) {
+	froms := make([]string, 0, len(fieldMap))
+	for from := range fieldMap {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+	var fp strings.Builder
+	for _, from := range froms {
+		fp.WriteString(from)
+		fp.WriteByte('\x00')
+		fp.WriteString(fieldMap[from])
+		fp.WriteByte('\x00')
+	}
+	return froms, fp.String()
+}
+
+// compileFieldMapping 按排序后的源字段名 froms 检查并编译映射
+func compileFieldMapping(t reflect.Type, froms []string, fieldMap map[string]string) *fieldMapping {
+	mapping := &fieldMapping{}
+	invalid := &ValidationError{Type: t}
+	lookup := func(name string) (reflect.StructField, bool) {
+		field, ok := t.FieldByName(name)
+		if !ok || len(field.Index) != 1 || !field.IsExported() {
+			invalid.Unknown = append(invalid.Unknown, name)
+			return field, false
+		}
+		return field, true
+	}
+
+	targets := make(map[int]bool)
+	for _, from := range froms {
+		to := fieldMap[from]
+		src, srcOK := lookup(from)
+		dst, dstOK := lookup(to)
+		if !srcOK || !dstOK {
+			continue
+		}
+		if src.Type != dst.Type {
+			invalid.Incompatible = append(invalid.Incompatible, fmt.Sprintf("%s -> %s (%s -> %s)", from, to, src.Type, dst.Type))
+			continue
+		}
+		if targets[dst.Index[0]] {
+			invalid.Duplicate = append(invalid.Duplicate, to)
+			continue
+		}
+		targets[dst.Index[0]] = true
+		mapping.moves = append(mapping.moves, fieldMove{from: src.Index[0], to: dst.Index[0]})
+	}
+	if len(invalid.Unknown) > 0 || len(invalid.Incompatible) > 0 || len(invalid.Duplicate) > 0 {
+		mapping.err = invalid
+		return mapping
+	}
+
+	for _, m := range mapping.moves {
+		if !targets[m.from] {
+			mapping.clear = append(mapping.clear, m.from)
+		}
+	}
+	return mapping
+}
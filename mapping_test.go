@@ -0,0 +1,118 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// MigratingUser 字段改名迁移中的结构体：OldTags 正在改名为 Tags
+type MigratingUser struct {
+	Name    string
+	OldTags []string
+	Tags    []string
+	First   string
+	Last    string
+	Age     int
+	hidden  string
+}
+
+func TestCopyWithMapping(t *testing.T) {
+	src := MigratingUser{Name: "a", OldTags: []string{"x"}, Tags: []string{"stale"}, First: "f", Last: "l", hidden: "h"}
+	got, err := CopyWithMapping(src, map[string]string{"OldTags": "Tags", "First": "Last", "Last": "First"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MigratingUser{Name: "a", Tags: []string{"x"}, First: "l", Last: "f"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	got.Tags[0] = "changed"
+	if src.OldTags[0] != "x" || src.Tags[0] != "stale" {
+		t.Error("source should be unchanged and not shared")
+	}
+
+	ptr, err := CopyWithMapping(&src, map[string]string{"OldTags": "Tags"})
+	if err != nil || ptr == &src || ptr.OldTags != nil || ptr.Tags[0] != "x" {
+		t.Errorf("pointer: %+v, %v", ptr, err)
+	}
+	if ptr, err := CopyWithMapping((*MigratingUser)(nil), map[string]string{"OldTags": "Tags"}); ptr != nil || err != nil {
+		t.Errorf("nil pointer: %v, %v", ptr, err)
+	}
+}
+
+func TestCopyWithMappingValidation(t *testing.T) {
+	_, err := CopyWithMapping(MigratingUser{}, map[string]string{
+		"Missing": "Tags",
+		"hidden":  "Name",
+		"Age":     "Name",
+		"First":   "Tags",
+		"OldTags": "Tags",
+		"Last":    "Name",
+		"Name":    "Last",
+	})
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	want := &ValidationError{
+		Type:         reflect.TypeOf(MigratingUser{}),
+		Unknown:      []string{"Missing", "hidden"},
+		Incompatible: []string{"Age -> Name (int -> string)", "First -> Tags (string -> []string)"},
+	}
+	if !reflect.DeepEqual(invalid, want) {
+		t.Errorf("got %+v, want %+v", invalid, want)
+	}
+
+	_, err = CopyWithMapping(MigratingUser{}, map[string]string{"First": "Name", "Last": "Name"})
+	if !errors.As(err, &invalid) || !reflect.DeepEqual(invalid.Duplicate, []string{"Name"}) {
+		t.Errorf("duplicate target: %v", err)
+	}
+
+	if _, err := CopyWithMapping(42, map[string]string{}); err == nil {
+		t.Error("non-struct should fail")
+	}
+}
+
+// 编译后的映射缓存在默认管理器中，数量有上限，ResetTypeCache 和 SetDefaultManagerOptions 后移除
+func TestCopyWithMappingCache(t *testing.T) {
+	userType := reflect.TypeOf(MigratingUser{})
+	cached := func() int {
+		n := 0
+		defaultManager.fieldMappings.Range(func(key, _ any) bool {
+			if key.(fieldMappingKey).t == userType {
+				n++
+			}
+			return true
+		})
+		return n
+	}
+	defaultManager.forgetFieldMappings(nil)
+
+	if _, err := CopyWithMapping(&MigratingUser{}, map[string]string{"OldTags": "Tags"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CopyWithMapping(MigratingUser{}, map[string]string{"OldTags": "Tags"}); err != nil {
+		t.Fatal(err)
+	}
+	if n := cached(); n != 1 {
+		t.Fatalf("cached mappings = %d, want 1", n)
+	}
+	ResetTypeCache[*MigratingUser]()
+	if n := cached(); n != 0 {
+		t.Errorf("ResetTypeCache should drop the mapping, %d left", n)
+	}
+
+	for i := 0; i < maxFieldMappings+10; i++ {
+		_, _ = CopyWithMapping(MigratingUser{}, map[string]string{"OldTags": "Tags", strconv.Itoa(i): "Name"})
+	}
+	if n := defaultManager.fieldMappingCount.Load(); n > maxFieldMappings || int(n) != cached() {
+		t.Errorf("cache holds %d mappings (counted %d), want at most %d", cached(), n, maxFieldMappings)
+	}
+
+	SetDefaultManagerOptions()
+	if n := cached(); n != 0 {
+		t.Errorf("SetDefaultManagerOptions should drop the mappings, %d left", n)
+	}
+}
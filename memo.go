@@ -0,0 +1,99 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Memo 拷贝过程中的访问记录，传给 DeepCopyWith 方法用于拷贝子值，
+// 使自定义拷贝的值与引擎共享已复制指针的记录，保持循环引用和共享关系
+type Memo interface {
+	// Copy 深拷贝 v，其中已复制过的指针直接使用已有的副本
+	Copy(v any) any
+	// Remember 记录指针 orig 的副本为 copied（类型必须相同），
+	// 应在拷贝可能指回 orig 的子值之前调用，否则环中的 orig 会被重复拷贝
+	Remember(orig, copied any)
+}
+
+// CycleAwareCopier 参与循环引用处理的自定义拷贝接口，优先于 Copier 的 DeepCopy 方法
+//
+//	func (n *Node) DeepCopyWith(m deepcopy.Memo) *Node {
+//		c := &Node{Name: n.Name}
+//		m.Remember(n, c)
+//		c.Next = deepcopy.MemoCopy(m, n.Next)
+//		return c
+//	}
+type CycleAwareCopier[T any] interface {
+	DeepCopyWith(memo Memo) T
+}
+
+// MemoCopy 与 Memo.Copy 相同，按 v 的类型返回
+func MemoCopy[T any](m Memo, v T) T {
+	copied, _ := m.Copy(v).(T)
+	return copied
+}
+
+// memoType Memo 接口的类型，用于检查 DeepCopyWith 的签名
+var memoType = reflect.TypeOf((*Memo)(nil)).Elem()
+
+// copyMemo 基于 copyState 的 Memo 实现
+type copyMemo struct {
+	s *copyState
+}
+
+func (m copyMemo) Copy(v any) any {
+	original := reflect.ValueOf(v)
+	if !original.IsValid() {
+		return nil
+	}
+	cpy := reflect.New(original.Type()).Elem()
+	m.s.copyRecursive(original, cpy)
+	return cpy.Interface()
+}
+
+func (m copyMemo) Remember(orig, copied any) {
+	o, c := reflect.ValueOf(orig), reflect.ValueOf(copied)
+	if o.Kind() != reflect.Ptr || o.IsNil() || !c.IsValid() || c.Type() != o.Type() {
+		panic(fmt.Sprintf("deepcopy: Memo.Remember needs a non-nil pointer and a copy of the same type, got %T and %T", orig, copied))
+	}
+	m.s.markVisited(o.Pointer(), c)
+}
+
+// hasDeepCopyWithMethod 检查值是否有 DeepCopyWith(Memo) 方法
+func hasDeepCopyWithMethod(v reflect.Value) (reflect.Method, bool) {
+	method, found := v.Type().MethodByName("DeepCopyWith")
+	if found && isDeepCopyWithSignature(v.Type(), method.Type) {
+		return method, true
+	}
+	return reflect.Method{}, false
+}
+
+// typeHasDeepCopyWithMethod 检查类型（非接口）的方法集中是否有 DeepCopyWith(Memo) 方法
+func typeHasDeepCopyWithMethod(t reflect.Type) bool {
+	method, found := t.MethodByName("DeepCopyWith")
+	return found && isDeepCopyWithSignature(t, method.Type)
+}
+
+// isDeepCopyWithSignature 方法类型（包括接收者）是否为 DeepCopyWith(Memo) T，且不是从嵌入字段提升而来
+func isDeepCopyWithSignature(t, methodType reflect.Type) bool {
+	return methodType.NumIn() == 2 && methodType.In(1) == memoType && methodType.NumOut() == 1 &&
+		!isPromotedMethod(t, methodType.Out(0), "DeepCopyWith")
+}
+
+// copyWithMemo 调用 DeepCopyWith 方法拷贝 original，返回值的类型与 original 不符时返回 false。
+// 指针的方法集包含值接收者的方法，此时返回的是值，包装成新指针
+func (s *copyState) copyWithMemo(original, cpy reflect.Value, method reflect.Method) bool {
+	s.report.customCopy()
+	result := method.Func.Call([]reflect.Value{original, reflect.ValueOf(Memo(copyMemo{s}))})[0]
+	switch {
+	case result.Type() == original.Type():
+		cpy.Set(result)
+	case original.Kind() == reflect.Ptr && result.Type() == original.Type().Elem():
+		newPtr := s.cfg.allocator.New(result.Type())
+		newPtr.Elem().Set(result)
+		cpy.Set(newPtr)
+	default:
+		return false
+	}
+	return true
+}
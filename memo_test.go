@@ -0,0 +1,106 @@
+package deepcopy
+
+import (
+	"errors"
+	"testing"
+)
+
+// MemoNode 自定义拷贝的图节点，拷贝时给名称加上后缀，边通过 Memo 拷贝
+type MemoNode struct {
+	Name  string
+	Edges []*MemoNode
+	Graph *MemoGraph
+}
+
+var memoNodeCopies int
+
+func (n *MemoNode) DeepCopyWith(m Memo) *MemoNode {
+	memoNodeCopies++
+	c := &MemoNode{Name: n.Name + "'"}
+	m.Remember(n, c)
+	c.Edges = MemoCopy(m, n.Edges)
+	c.Graph = MemoCopy(m, n.Graph)
+	return c
+}
+
+// DeepCopy 同时存在时应使用 DeepCopyWith
+func (n *MemoNode) DeepCopy() *MemoNode {
+	panic("DeepCopy should not be called when DeepCopyWith exists")
+}
+
+type MemoGraph struct {
+	Nodes []*MemoNode
+}
+
+func TestCycleAwareCopier(t *testing.T) {
+	graph := &MemoGraph{}
+	a := &MemoNode{Name: "a", Graph: graph}
+	b := &MemoNode{Name: "b", Graph: graph}
+	a.Edges = []*MemoNode{b, a}
+	b.Edges = []*MemoNode{a}
+	graph.Nodes = []*MemoNode{a, b}
+
+	memoNodeCopies = 0
+	copied, err := CopyE(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, cb := copied.Nodes[0], copied.Nodes[1]
+	if ca.Name != "a'" || cb.Name != "b'" {
+		t.Errorf("custom copy not used: %q, %q", ca.Name, cb.Name)
+	}
+	if ca == a || cb == b {
+		t.Error("nodes should be copied")
+	}
+	if ca.Edges[0] != cb || ca.Edges[1] != ca || cb.Edges[0] != ca {
+		t.Error("cycles between custom-copied nodes not preserved")
+	}
+	if ca.Graph != copied || cb.Graph != copied {
+		t.Error("back-references to the enclosing graph not preserved")
+	}
+	if memoNodeCopies != 2 {
+		t.Errorf("DeepCopyWith called %d times, want 2", memoNodeCopies)
+	}
+}
+
+// MemoValue 值接收者的 DeepCopyWith，同样优先于 DeepCopy
+type MemoValue struct {
+	Items []int
+}
+
+func (v MemoValue) DeepCopyWith(m Memo) MemoValue {
+	return MemoValue{Items: append(MemoCopy(m, v.Items), 0)}
+}
+
+func (v MemoValue) DeepCopy() MemoValue {
+	panic("DeepCopy should not be called when DeepCopyWith exists")
+}
+
+func TestCycleAwareCopierValueReceiver(t *testing.T) {
+	original := MemoValue{Items: []int{1}}
+	if got := Copy(original); len(got.Items) != 2 {
+		t.Errorf("Copy: got %v", got.Items)
+	}
+	if got := Copy(&original); len(got.Items) != 2 {
+		t.Errorf("Copy pointer: got %v", got.Items)
+	}
+	if got := Copy([]MemoValue{original}); len(got[0].Items) != 2 {
+		t.Errorf("Copy slice: got %v", got[0].Items)
+	}
+}
+
+// MemoMisuse 以错误的类型调用 Remember
+type MemoMisuse struct{ N int }
+
+func (m *MemoMisuse) DeepCopyWith(memo Memo) *MemoMisuse {
+	memo.Remember(m, MemoMisuse{})
+	return &MemoMisuse{}
+}
+
+func TestMemoRememberMismatch(t *testing.T) {
+	var panicErr *PanicError
+	if _, err := CopyE(&MemoMisuse{}); !errors.As(err, &panicErr) {
+		t.Errorf("expected *PanicError, got %v", err)
+	}
+}
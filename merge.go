@@ -0,0 +1,282 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// MergeStrategy MergeInto 合并切片和映射字段的方式
+type MergeStrategy int
+
+const (
+	// MergeReplace 切片和映射：使用 src 的副本替换 dst（默认）
+	MergeReplace MergeStrategy = iota
+	// MergeAppend 切片：src 的元素追加到 dst 之后
+	MergeAppend
+	// MergeAppendUnique 切片：只追加 dst 中不存在的元素（使用 reflect.DeepEqual 比较）
+	MergeAppendUnique
+	// MergePrepend 切片：src 的元素插入到 dst 之前
+	MergePrepend
+	// MergeKeys 映射：src 的键覆盖 dst 中的同名键，保留 dst 独有的键
+	MergeKeys
+	// MergeDeleteAbsent 映射：src 的键覆盖 dst 中的同名键，删除 src 中不存在的键
+	MergeDeleteAbsent
+)
+
+// ErrMergeStrategy 为字段或类型指定的合并策略不适用于其种类时返回
+var ErrMergeStrategy = errors.New("deepcopy: merge strategy does not apply")
+
+// String 返回策略名称
+func (s MergeStrategy) String() string {
+	switch s {
+	case MergeReplace:
+		return "replace"
+	case MergeAppend:
+		return "append"
+	case MergeAppendUnique:
+		return "append-unique"
+	case MergePrepend:
+		return "prepend"
+	case MergeKeys:
+		return "merge-keys"
+	case MergeDeleteAbsent:
+		return "delete-absent"
+	}
+	return fmt.Sprintf("MergeStrategy(%d)", int(s))
+}
+
+// appliesTo 策略是否适用于该种类的值
+func (s MergeStrategy) appliesTo(kind reflect.Kind) bool {
+	switch s {
+	case MergeReplace:
+		return kind == reflect.Slice || kind == reflect.Map
+	case MergeAppend, MergeAppendUnique, MergePrepend:
+		return kind == reflect.Slice
+	case MergeKeys, MergeDeleteAbsent:
+		return kind == reflect.Map
+	}
+	return false
+}
+
+// fieldMergeConfig MergeInto 的合并策略，按字段名、类型、全局的顺序查找
+type fieldMergeConfig struct {
+	slices  MergeStrategy                  // 切片的全局策略
+	maps    MergeStrategy                  // 映射的全局策略
+	byField map[string]MergeStrategy       // 按结构体字段名指定的策略
+	byType  map[reflect.Type]MergeStrategy // 按字段类型指定的策略
+}
+
+// WithMergeStrategy 设置 MergeInto 的全局合并策略
+// 切片策略只影响切片，映射策略只影响映射，MergeReplace 同时影响两者
+func WithMergeStrategy(s MergeStrategy) Option {
+	return func(c *copyConfig) {
+		if s.appliesTo(reflect.Slice) {
+			c.merge.slices = s
+		}
+		if s.appliesTo(reflect.Map) {
+			c.merge.maps = s
+		}
+	}
+}
+
+// WithFieldMergeStrategy 为名为 name 的结构体字段（任意深度）指定合并策略，优先于类型和全局策略
+func WithFieldMergeStrategy(name string, s MergeStrategy) Option {
+	return func(c *copyConfig) {
+		if c.merge.byField == nil {
+			c.merge.byField = make(map[string]MergeStrategy)
+		}
+		c.merge.byField[name] = s
+	}
+}
+
+// WithTypeMergeStrategy 为 t 类型的切片或映射指定合并策略，优先于全局策略
+func WithTypeMergeStrategy(t reflect.Type, s MergeStrategy) Option {
+	return func(c *copyConfig) {
+		if c.merge.byType == nil {
+			c.merge.byType = make(map[reflect.Type]MergeStrategy)
+		}
+		c.merge.byType[t] = s
+	}
+}
+
+// strategyFor 查找字段的合并策略
+func (c *fieldMergeConfig) strategyFor(field string, t reflect.Type) (MergeStrategy, error) {
+	s, ok := c.byField[field]
+	if !ok {
+		s, ok = c.byType[t]
+	}
+	if !ok {
+		if t.Kind() == reflect.Map {
+			return c.maps, nil
+		}
+		return c.slices, nil
+	}
+	if !s.appliesTo(t.Kind()) {
+		return s, fmt.Errorf("%w: %s to %s", ErrMergeStrategy, s, t)
+	}
+	return s, nil
+}
+
+// MergeInto 将 src 合并到 *dst 中：src 中的零值字段保留 *dst 原有的值，
+// 非零的结构体字段逐字段递归合并，指针指向的结构体合并到 dst 已有的对象中，
+// 切片和映射按合并策略（WithMergeStrategy 等）处理，其余值使用 src 的深拷贝覆盖。
+// 出错时 *dst 可能已被部分修改
+func MergeInto[T any](dst *T, src T, opts ...Option) error {
+	if dst == nil {
+		return ErrNilDestination
+	}
+
+	cfg := newCopyConfig(opts)
+	if cfg.locker != nil {
+		cfg.locker.Lock()
+		defer cfg.locker.Unlock()
+	}
+
+	m := &merger{state: newCopyState(cfg), visited: make(map[uintptr]bool)}
+	return m.state.run(func() {
+		m.merge(reflect.ValueOf(&src).Elem(), reflect.ValueOf(dst).Elem(), "")
+	})
+}
+
+// merger 单次合并的状态
+type merger struct {
+	state   *copyState
+	visited map[uintptr]bool // 已合并的指针，避免循环引用导致无限递归
+}
+
+// merge 把 src 合并到 dst 中，field 为所在的结构体字段名
+func (m *merger) merge(src, dst reflect.Value, field string) {
+	s := m.state
+	if s.err != nil || src.IsZero() {
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Struct:
+		if !m.mergeable(src.Type()) {
+			s.copyRecursive(src, dst)
+			return
+		}
+		t := src.Type()
+		for _, i := range s.manager.getOrAnalyzeType(t).ExportedFieldIndices {
+			if s.cfg.fieldFilter != nil && !s.cfg.fieldFilter(t.Field(i)) {
+				continue
+			}
+			s.pushField(t, i)
+			m.merge(src.Field(i), dst.Field(i), t.Field(i).Name)
+			s.popPath()
+		}
+
+	case reflect.Ptr:
+		if dst.IsNil() || src.Elem().Kind() != reflect.Struct || !m.mergeable(src.Elem().Type()) {
+			s.copyRecursive(src, dst)
+			return
+		}
+		if m.visited[src.Pointer()] {
+			return
+		}
+		m.visited[src.Pointer()] = true
+		m.merge(src.Elem(), dst.Elem(), field)
+
+	case reflect.Slice:
+		m.mergeSlice(src, dst, field)
+
+	case reflect.Map:
+		m.mergeMap(src, dst, field)
+
+	default:
+		s.copyRecursive(src, dst)
+	}
+}
+
+// mergeable 结构体是否逐字段合并，不可变值类型和自定义拷贝方式的类型整体覆盖
+func (m *merger) mergeable(t reflect.Type) bool {
+	if !m.state.manager.disableBuiltins && immutableValueTypes[t] {
+		return false
+	}
+	analysis := m.state.manager.getOrAnalyzeType(t)
+	return !analysis.HasDeepCopyMethod && !analysis.hasCustomCopier &&
+		!analysis.ImplementsBinaryMarshaler && !analysis.ImplementsTextMarshaler
+}
+
+// mergeSlice 按策略合并切片
+func (m *merger) mergeSlice(src, dst reflect.Value, field string) {
+	s := m.state
+	strategy, err := s.cfg.merge.strategyFor(field, src.Type())
+	if err != nil {
+		s.err = err
+		return
+	}
+	if strategy == MergeReplace || dst.IsNil() {
+		s.copyRecursive(src, dst)
+		return
+	}
+
+	copied := m.copyNew(src)
+	switch strategy {
+	case MergeAppend:
+		dst.Set(reflect.AppendSlice(dst, copied))
+	case MergePrepend:
+		merged := reflect.MakeSlice(dst.Type(), 0, copied.Len()+dst.Len())
+		dst.Set(reflect.AppendSlice(reflect.AppendSlice(merged, copied), dst))
+	case MergeAppendUnique:
+		result := dst
+		for i := 0; i < copied.Len(); i++ {
+			if !containsDeepEqual(result, copied.Index(i)) {
+				result = reflect.Append(result, copied.Index(i))
+			}
+		}
+		dst.Set(result)
+	}
+}
+
+// mergeMap 按策略合并映射
+func (m *merger) mergeMap(src, dst reflect.Value, field string) {
+	s := m.state
+	strategy, err := s.cfg.merge.strategyFor(field, src.Type())
+	if err != nil {
+		s.err = err
+		return
+	}
+	if strategy == MergeReplace || dst.IsNil() {
+		s.copyRecursive(src, dst)
+		return
+	}
+
+	if strategy == MergeDeleteAbsent {
+		for _, key := range dst.MapKeys() {
+			if !src.MapIndex(key).IsValid() {
+				dst.SetMapIndex(key, reflect.Value{})
+			}
+		}
+	}
+
+	iter := src.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		if !s.cfg.sharedMapKeys {
+			key = m.copyNew(key)
+		}
+		s.pushKey(iter.Key())
+		dst.SetMapIndex(key, m.copyNew(iter.Value()))
+		s.popPath()
+	}
+}
+
+// copyNew 返回 v 的深拷贝
+func (m *merger) copyNew(v reflect.Value) reflect.Value {
+	cpy := reflect.New(v.Type()).Elem()
+	m.state.copyRecursive(v, cpy)
+	return cpy
+}
+
+// containsDeepEqual 切片中是否存在与 v 深度相等的元素
+func containsDeepEqual(slice, v reflect.Value) bool {
+	for i := 0; i < slice.Len(); i++ {
+		if reflect.DeepEqual(slice.Index(i).Interface(), v.Interface()) {
+			return true
+		}
+	}
+	return false
+}
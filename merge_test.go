@@ -0,0 +1,128 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// 合并测试使用的配置
+type Profile struct {
+	Name   string
+	Age    int
+	Tags   []string
+	Scores map[string]int
+	Extra  *ProfileExtra
+}
+
+type ProfileExtra struct {
+	Bio   string
+	Links []string
+}
+
+func TestMergeIntoFields(t *testing.T) {
+	extra := &ProfileExtra{Bio: "old", Links: []string{"a"}}
+	dst := Profile{Name: "dst", Age: 30, Extra: extra}
+	src := Profile{Name: "src", Extra: &ProfileExtra{Links: []string{"b"}}}
+
+	if err := MergeInto(&dst, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "src" || dst.Age != 30 {
+		t.Errorf("zero fields in src should keep dst values, got %+v", dst)
+	}
+	if dst.Extra != extra || dst.Extra.Bio != "old" || !reflect.DeepEqual(dst.Extra.Links, []string{"b"}) {
+		t.Errorf("pointed-to structs should be merged in place, got %+v", *dst.Extra)
+	}
+	dst.Extra.Links[0] = "changed"
+	if src.Extra.Links[0] != "b" {
+		t.Error("merged values should be deep copied")
+	}
+
+	if err := MergeInto(nil, src); !errors.Is(err, ErrNilDestination) {
+		t.Errorf("expected ErrNilDestination, got %v", err)
+	}
+}
+
+func TestMergeIntoSliceStrategies(t *testing.T) {
+	tests := []struct {
+		strategy MergeStrategy
+		want     []string
+	}{
+		{MergeReplace, []string{"b", "c"}},
+		{MergeAppend, []string{"a", "b", "b", "c"}},
+		{MergeAppendUnique, []string{"a", "b", "c"}},
+		{MergePrepend, []string{"b", "c", "a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			dst := Profile{Tags: []string{"a", "b"}}
+			src := Profile{Tags: []string{"b", "c"}}
+			if err := MergeInto(&dst, src, WithMergeStrategy(tt.strategy)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(dst.Tags, tt.want) {
+				t.Errorf("got %v, want %v", dst.Tags, tt.want)
+			}
+			dst.Tags[len(dst.Tags)-1] = "changed"
+			if src.Tags[1] != "c" {
+				t.Error("src should not be modified")
+			}
+		})
+	}
+}
+
+func TestMergeIntoMapStrategies(t *testing.T) {
+	tests := []struct {
+		strategy MergeStrategy
+		want     map[string]int
+	}{
+		{MergeReplace, map[string]int{"b": 20, "c": 30}},
+		{MergeKeys, map[string]int{"a": 1, "b": 20, "c": 30}},
+		{MergeDeleteAbsent, map[string]int{"b": 20, "c": 30}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy.String(), func(t *testing.T) {
+			scores := map[string]int{"a": 1, "b": 2}
+			dst := Profile{Scores: scores}
+			src := Profile{Scores: map[string]int{"b": 20, "c": 30}}
+			if err := MergeInto(&dst, src, WithMergeStrategy(tt.strategy)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(dst.Scores, tt.want) {
+				t.Errorf("got %v, want %v", dst.Scores, tt.want)
+			}
+			if tt.strategy != MergeReplace && reflect.ValueOf(dst.Scores).Pointer() != reflect.ValueOf(scores).Pointer() {
+				t.Error("merge strategies should update the existing map")
+			}
+		})
+	}
+}
+
+func TestMergeIntoStrategyLookup(t *testing.T) {
+	dst := Profile{Tags: []string{"a"}, Extra: &ProfileExtra{Links: []string{"x"}}}
+	src := Profile{Tags: []string{"b"}, Extra: &ProfileExtra{Links: []string{"y"}}}
+
+	// 字段名优先于类型，类型优先于全局策略
+	err := MergeInto(&dst, src,
+		WithMergeStrategy(MergePrepend),
+		WithTypeMergeStrategy(reflect.TypeOf([]string(nil)), MergeAppend),
+		WithFieldMergeStrategy("Links", MergeReplace),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dst.Tags, []string{"a", "b"}) {
+		t.Errorf("Tags: got %v, want type strategy append", dst.Tags)
+	}
+	if !reflect.DeepEqual(dst.Extra.Links, []string{"y"}) {
+		t.Errorf("Links: got %v, want field strategy replace", dst.Extra.Links)
+	}
+
+	err = MergeInto(&dst, src, WithFieldMergeStrategy("Tags", MergeKeys))
+	if !errors.Is(err, ErrMergeStrategy) {
+		t.Errorf("expected ErrMergeStrategy for a map strategy on a slice, got %v", err)
+	}
+}
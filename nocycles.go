@@ -0,0 +1,93 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrCyclicType CopyNoCyclesE 的类型可能包含循环引用时返回
+var ErrCyclicType = errors.New("deepcopy: type may contain cycles")
+
+// CopyNoCycles 深拷贝不会包含循环引用的类型，跳过已复制指针的记录和查找，减少大量指针时的开销。
+// 注意：同一对象被多处引用时，副本中每处都是独立的拷贝，不再共享。
+// 类型可能包含循环引用（HasCycles 为 true，例如递归引用自身的链表、树节点，或包含接口）时 panic，应使用 Copy
+func CopyNoCycles[T any](src T) T {
+	result, err := CopyNoCyclesE(src)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// CopyNoCyclesE 同 CopyNoCycles，类型可能包含循环引用时返回 ErrCyclicType，DeepCopy 方法的错误和拷贝中的 panic 同样作为错误返回
+func CopyNoCyclesE[T any](src T) (T, error) {
+	var zero T
+	manager := getTypedManager[T]()
+	analysis := manager.getOrAnalyzeType()
+	if analysis.HasCycles {
+		return zero, fmt.Errorf("%w: %s, use Copy instead", ErrCyclicType, manager.rtype)
+	}
+
+	if analysis.IsOnlyValues && !fastPathDisabled.Load() {
+		return src, nil
+	}
+
+	srcVal := reflect.ValueOf(src)
+	state := newCopyState(&defaultCopyConfig)
+	if analysis.useDeepCopy() {
+		if result, ok, err := state.tryDeepCopyRoot(srcVal); err != nil {
+			return zero, err
+		} else if ok {
+			return result.Interface().(T), nil
+		}
+	}
+
+	state.acyclic = true
+	var result T
+	if err := state.run(func() { result = copyToT[T](srcVal, state) }); err != nil {
+		return zero, err
+	}
+	return result, nil
+}
+
+// typeMayCycle 判断类型的值能否包含循环引用：类型经由指针、切片、映射递归引用自身，或包含接口（动态值未知）
+// 按值共享或由自定义拷贝方式处理的类型不会被遍历，不计入其中。acyclic 记录已确认不会形成循环的类型
+func (m *DeepCopyManager) typeMayCycle(t reflect.Type, onPath, acyclic map[reflect.Type]bool) bool {
+	if onPath[t] {
+		return true
+	}
+	if acyclic[t] || (!m.disableBuiltins && immutableValueTypes[t]) {
+		return false
+	}
+	if _, ok := m.customCopiers[t]; ok || (t.Kind() != reflect.Interface && (typeHasDeepCopyMethod(t) || m.interfaceCopierFor(t) != nil)) {
+		return false
+	}
+
+	onPath[t] = true
+	defer delete(onPath, t)
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		if m.typeMayCycle(t.Elem(), onPath, acyclic) {
+			return true
+		}
+	case reflect.Map:
+		if m.typeMayCycle(t.Key(), onPath, acyclic) || m.typeMayCycle(t.Elem(), onPath, acyclic) {
+			return true
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			// 未导出字段不会被拷贝
+			if field.PkgPath == "" && m.typeMayCycle(field.Type, onPath, acyclic) {
+				return true
+			}
+		}
+	}
+
+	acyclic[t] = true
+	return false
+}
@@ -0,0 +1,113 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 不会形成循环引用的订单结构
+type Order struct {
+	ID       int
+	Customer *Customer
+	Items    []*OrderItem
+	Meta     map[string][]string
+	Created  time.Time
+}
+
+type Customer struct {
+	Name string
+}
+
+type OrderItem struct {
+	SKU   string
+	Price *float64
+}
+
+func TestHasCycles(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  reflect.Type
+		want bool
+	}{
+		{"value struct", reflect.TypeOf(OnlyValueStruct{}), false},
+		{"acyclic pointers", reflect.TypeOf(Order{}), false},
+		{"self reference", reflect.TypeOf(Node{}), true},
+		{"interface", reflect.TypeOf((*any)(nil)).Elem(), true},
+		{"interface field", reflect.TypeOf(PluginHost{}), true},
+		{"map of self", reflect.TypeOf(MyMapRec{}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultManager.getOrAnalyzeType(tt.typ).HasCycles; got != tt.want {
+				t.Errorf("HasCycles(%s) = %v, want %v", tt.typ, got, tt.want)
+			}
+		})
+	}
+}
+
+// 经由映射引用自身的类型
+type MyMapRec map[string]MyMapRec
+
+func TestCopyNoCycles(t *testing.T) {
+	price := 9.5
+	original := Order{
+		ID:       1,
+		Customer: &Customer{Name: "alice"},
+		Items:    []*OrderItem{{SKU: "a", Price: &price}, {SKU: "b", Price: &price}},
+		Meta:     map[string][]string{"tags": {"x"}},
+		Created:  time.Now(),
+	}
+
+	copied := CopyNoCycles(original)
+	if !reflect.DeepEqual(copied, original) {
+		t.Fatalf("got %+v, want %+v", copied, original)
+	}
+	if copied.Customer == original.Customer || copied.Items[0] == original.Items[0] || copied.Items[0].Price == &price {
+		t.Error("pointers should be deep copied")
+	}
+	copied.Meta["tags"][0] = "changed"
+	if original.Meta["tags"][0] != "x" {
+		t.Error("maps should be deep copied")
+	}
+	// 不记录已复制的指针，共享的对象在副本中各自独立
+	if copied.Items[0].Price == copied.Items[1].Price {
+		t.Error("shared pointers are not tracked by CopyNoCycles")
+	}
+}
+
+func TestCopyNoCyclesRejectsCyclicTypes(t *testing.T) {
+	_, err := CopyNoCyclesE(&Node{Value: 1})
+	if !errors.Is(err, ErrCyclicType) || !strings.Contains(err.Error(), "deepcopy.Node") {
+		t.Errorf("expected ErrCyclicType naming the type, got %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("CopyNoCycles should panic for cyclic types")
+		}
+	}()
+	CopyNoCycles[any](1)
+}
+
+// DeepCopy 方法返回的错误作为错误返回，顶层和嵌套的方法都不会 panic
+func TestCopyNoCyclesCopierError(t *testing.T) {
+	failing := SealedSecret{Cipher: "x", fail: true}
+	if _, err := CopyNoCyclesE(failing); !errors.Is(err, errSidecar) {
+		t.Errorf("top level: expected errSidecar, got %v", err)
+	}
+
+	vault := SecretVault{Secrets: []SealedSecret{{Cipher: "a"}, failing}}
+	_, err := CopyNoCyclesE(vault)
+	var copierErr *CopierError
+	if !errors.As(err, &copierErr) || copierErr.Path != "Secrets[1]" {
+		t.Errorf("nested: expected *CopierError at Secrets[1], got %v", err)
+	}
+
+	vault.Secrets = vault.Secrets[:1]
+	if got, err := CopyNoCyclesE(vault); err != nil || got.Secrets[0].Cipher != "a'" {
+		t.Errorf("got %+v, %v", got, err)
+	}
+}
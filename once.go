@@ -0,0 +1,46 @@
+package deepcopy
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CopyOnce 保存原值，在第一次 Get 时深拷贝并缓存副本，之后的 Get 返回同一个副本，零值可直接使用。
+// 并发的 Get 等待第一次拷贝完成后返回同一个副本，因此所有调用方共享该副本（与原值相互独立），不应修改它。
+// Set 只保存原值不拷贝，副本创建前原值不应被修改
+type CopyOnce[T any] struct {
+	state atomic.Pointer[copyOnceState[T]]
+}
+
+// copyOnceState 一个原值及其副本，Set、Reset 时整体替换
+type copyOnceState[T any] struct {
+	once   sync.Once
+	src    T
+	copied T
+}
+
+// Set 保存原值 src，丢弃已缓存的副本，下次 Get 时拷贝
+func (c *CopyOnce[T]) Set(src T) {
+	c.state.Store(&copyOnceState[T]{src: src})
+}
+
+// Get 返回原值的副本，第一次调用时拷贝；未调用过 Set 时返回零值
+func (c *CopyOnce[T]) Get() T {
+	st := c.state.Load()
+	if st == nil {
+		var zero T
+		return zero
+	}
+	st.once.Do(func() { st.copied = Copy(st.src) })
+	return st.copied
+}
+
+// Reset 丢弃已缓存的副本，下次 Get 时从保存的原值重新拷贝
+func (c *CopyOnce[T]) Reset() {
+	for {
+		st := c.state.Load()
+		if st == nil || c.state.CompareAndSwap(st, &copyOnceState[T]{src: st.src}) {
+			return
+		}
+	}
+}
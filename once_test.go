@@ -0,0 +1,76 @@
+package deepcopy
+
+import (
+	"sync"
+	"testing"
+)
+
+// OnceConfig 由 CopyOnce 延迟拷贝的配置
+type OnceConfig struct {
+	Hosts []string
+}
+
+// CountingOnceConfig 记录 DeepCopy 的调用次数
+type CountingOnceConfig struct {
+	Hosts []string
+	calls *int
+}
+
+func (c CountingOnceConfig) DeepCopy() CountingOnceConfig {
+	*c.calls++
+	return CountingOnceConfig{Hosts: append([]string(nil), c.Hosts...), calls: c.calls}
+}
+
+func TestCopyOnce(t *testing.T) {
+	var c CopyOnce[OnceConfig]
+	if got := c.Get(); got.Hosts != nil {
+		t.Errorf("Get before Set = %+v, want zero", got)
+	}
+
+	original := OnceConfig{Hosts: []string{"a"}}
+	c.Set(original)
+	first := c.Get()
+	if &first.Hosts[0] == &original.Hosts[0] {
+		t.Fatal("Get should return a copy")
+	}
+	if second := c.Get(); &second.Hosts[0] != &first.Hosts[0] {
+		t.Error("later Get calls should return the cached copy")
+	}
+
+	c.Reset()
+	if again := c.Get(); &again.Hosts[0] == &first.Hosts[0] || again.Hosts[0] != "a" {
+		t.Error("Get after Reset should copy the original again")
+	}
+
+	c.Set(OnceConfig{Hosts: []string{"b"}})
+	if got := c.Get(); got.Hosts[0] != "b" {
+		t.Errorf("Get after Set = %+v", got)
+	}
+}
+
+func TestCopyOnceConcurrentGet(t *testing.T) {
+	calls := 0
+	var c CopyOnce[CountingOnceConfig]
+	c.Set(CountingOnceConfig{Hosts: []string{"a"}, calls: &calls})
+
+	const n = 16
+	results := make([]CountingOnceConfig, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.Get()
+		}(i)
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("copied %d times, want 1", calls)
+	}
+	for _, r := range results {
+		if &r.Hosts[0] != &results[0].Hosts[0] {
+			t.Fatal("concurrent Get calls should return the same copy")
+		}
+	}
+}
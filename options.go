@@ -0,0 +1,426 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UnsafePointerPolicy 控制拷贝 unsafe.Pointer 时的行为
+type UnsafePointerPolicy int
+
+const (
+	// UnsafePointerCopy 直接复制地址值，每种类型第一次复制非 nil 值时输出警告（默认）
+	UnsafePointerCopy UnsafePointerPolicy = iota
+	// UnsafePointerZero 副本中对应字段置零
+	UnsafePointerZero
+	// UnsafePointerError 遇到 unsafe.Pointer 时返回错误
+	UnsafePointerError
+)
+
+// IOResourcePolicy 控制拷贝实现了 io.Reader、io.Writer 或 io.Closer 的值（如网络连接、文件）时的行为。
+// 按普通值深拷贝这类值时，副本与原值仍指向同一个底层资源（如同一个文件描述符），两者会争抢读写
+type IOResourcePolicy int
+
+const (
+	// IOResourceWarn 按普通值拷贝（*os.File、net.Conn 等句柄直接共享），对包含 io 资源的结构体输出一次警告（默认）
+	IOResourceWarn IOResourcePolicy = iota
+	// IOResourceShare 副本直接共享原值，不输出警告
+	IOResourceShare
+	// IOResourceNil 副本中对应的值置零
+	IOResourceNil
+	// IOResourceError 遇到 io 资源时返回错误
+	IOResourceError
+)
+
+// fastPathDisabled 全局关闭快速路径，由 SetDisableFastPath 设置
+var fastPathDisabled atomic.Bool
+
+// SetDisableFastPath 全局关闭（或重新开启）只包含值类型时直接返回原值的优化，
+// 使 Copy、CopyWithKey 等总是执行完整的递归拷贝（包括调用所有 DeepCopy 方法）
+func SetDisableFastPath(disable bool) {
+	fastPathDisabled.Store(disable)
+}
+
+// recoverHook Copy 发生 panic 时调用的函数，由 SetRecoverMode 设置
+var recoverHook atomic.Pointer[func(recovered any)]
+
+// SetRecoverMode 设置后 Copy 不再向外 panic：拷贝中的 panic（包括 DeepCopy 方法中的）被恢复，
+// 以 recover() 得到的值调用 onPanic 后返回 T 的零值，适合不检查错误的调用处；传入 nil 恢复默认行为。
+// 需要区分失败与零值时应使用 CopyE
+func SetRecoverMode(onPanic func(recovered any)) {
+	if onPanic == nil {
+		recoverHook.Store(nil)
+		return
+	}
+	recoverHook.Store(&onPanic)
+}
+
+// recoverCopy 由 Copy 延迟调用，把 panic 转交给 SetRecoverMode 设置的函数并把结果置为零值
+func recoverCopy[T any](onPanic func(recovered any), result *T) {
+	if r := recover(); r != nil {
+		var zero T
+		*result = zero
+		onPanic(r)
+	}
+}
+
+// NaNKeyPolicy 控制拷贝以 NaN 为键（或键中包含 NaN）的 map 条目时的行为
+type NaNKeyPolicy int
+
+const (
+	// NaNKeyPreserve 原样保留条目（默认），副本与原始值的长度一致，但这些条目同样无法按键查找
+	NaNKeyPreserve NaNKeyPolicy = iota
+	// NaNKeyDrop 副本中丢弃这些条目
+	NaNKeyDrop
+	// NaNKeyError 遇到这些条目时返回错误
+	NaNKeyError
+)
+
+// ErrNaNMapKey 在 NaNKeyError 策略下遇到 NaN 键时返回
+var ErrNaNMapKey = errors.New("deepcopy: NaN map key encountered")
+
+// ErrUnsafePointer 在 UnsafePointerError 策略下遇到 unsafe.Pointer 时返回
+var ErrUnsafePointer = errors.New("deepcopy: unsafe.Pointer encountered")
+
+// ErrIOResource 在 IOResourceError 策略下遇到 io 资源时返回
+var ErrIOResource = errors.New("deepcopy: io resource encountered")
+
+// WarnFunc 输出警告的函数，默认使用 log.Printf，设为 nil 可关闭警告
+var WarnFunc func(format string, args ...any) = log.Printf
+
+// Allocator 控制拷贝过程中新切片、映射和指针的分配方式
+// 可用于把副本分配到 arena 或对象池中
+type Allocator interface {
+	// New 返回指向 t 类型零值的新指针
+	New(t reflect.Type) reflect.Value
+	// NewSlice 返回 t 类型、指定长度和容量的新切片
+	NewSlice(t reflect.Type, len, cap int) reflect.Value
+	// NewMap 返回 t 类型的新映射，size 为预计的元素个数
+	NewMap(t reflect.Type, size int) reflect.Value
+}
+
+// reflectAllocator 默认分配器，直接使用 reflect 包的函数
+type reflectAllocator struct{}
+
+func (reflectAllocator) New(t reflect.Type) reflect.Value {
+	return reflect.New(t)
+}
+
+func (reflectAllocator) NewSlice(t reflect.Type, len, cap int) reflect.Value {
+	return reflect.MakeSlice(t, len, cap)
+}
+
+func (reflectAllocator) NewMap(t reflect.Type, size int) reflect.Value {
+	// 按源 map 的长度预分配，避免逐个写入时反复扩容
+	return reflect.MakeMapWithSize(t, size)
+}
+
+// PooledAllocs 记录一次拷贝中由 WithAllocHook 的钩子提供的对象（指针），
+// 拷贝完成后调用方据此管理对象的归还，例如副本不再使用时放回 sync.Pool
+type PooledAllocs struct {
+	Values []reflect.Value
+}
+
+// hookAllocator 分配新指针时先询问钩子，钩子不提供时使用原分配器
+type hookAllocator struct {
+	Allocator
+	hook   func(t reflect.Type) (reflect.Value, bool)
+	record *PooledAllocs
+}
+
+func (a hookAllocator) New(t reflect.Type) reflect.Value {
+	v, ok := a.hook(t)
+	if !ok {
+		return a.Allocator.New(t)
+	}
+	if !v.IsValid() || v.Type() != reflect.PointerTo(t) || v.IsNil() {
+		panic(fmt.Sprintf("deepcopy: alloc hook for %s returned %v, want a non-nil *%s", t, v, t))
+	}
+	// 池中取出的对象可能残留旧数据，未导出字段不会被拷贝覆盖，因此先清零
+	v.Elem().SetZero()
+	if a.record != nil {
+		a.record.Values = append(a.record.Values, v)
+	}
+	return v
+}
+
+// WithAllocHook 拷贝中需要分配新指针（reflect.New）时先调用 hook，返回 true 时使用其返回的 *t 作为新对象，
+// 返回 false 时按原方式分配，可用于从 sync.Pool 等对象池中取对象。
+// 返回的对象在使用前会被清零；record 不为 nil 时记录所有由 hook 提供并被副本使用的对象。
+// 与 WithAllocator 同时使用时应放在其后
+func WithAllocHook(hook func(t reflect.Type) (reflect.Value, bool), record *PooledAllocs) Option {
+	return func(c *copyConfig) {
+		c.allocator = hookAllocator{Allocator: c.allocator, hook: hook, record: record}
+	}
+}
+
+// nilCollectionMode nil 与空切片、映射之间的转换方式
+type nilCollectionMode int
+
+const (
+	keepNilCollections nilCollectionMode = iota // 保持原样（默认）
+	emptyForNil                                 // nil 转为空集合
+	nilForEmpty                                 // 空集合转为 nil
+)
+
+// copyConfig 拷贝配置，由 Option 修改
+type copyConfig struct {
+	unsafePointerPolicy UnsafePointerPolicy               // unsafe.Pointer 处理策略
+	allocator           Allocator                         // 新切片、映射和指针的分配器
+	nilCollections      nilCollectionMode                 // nil 与空切片、映射的转换方式
+	fieldFilter         func(reflect.StructField) bool    // 字段过滤器，返回 false 的字段不拷贝
+	trimCapacity        bool                              // 副本切片的容量是否裁剪为长度
+	disableFastPath     bool                              // 是否关闭只含值类型时直接返回原值的优化
+	sharedMapKeys       bool                              // map 的键是否原样保留而不深拷贝
+	nanKeyPolicy        NaNKeyPolicy                      // NaN 键的处理策略
+	retryBase           time.Duration                     // CopyWithRetry 的初始退避时间
+	retryMax            time.Duration                     // CopyWithRetry 的最大退避时间
+	typeConverters      map[reflect.Type]typeConverter    // 按源类型注册的类型转换
+	locker              sync.Locker                       // 拷贝期间持有的锁
+	shallowInterfaces   bool                              // 接口值是否直接共享
+	strict              bool                              // 副本不完全等价时是否报错
+	funcWrapper         func(reflect.Value) reflect.Value // 替换副本中的函数值
+	zeroFuncs           bool                              // 副本中的函数值是否置为 nil 而不共享
+	merge               fieldMergeConfig                  // MergeInto 的合并策略
+	clock               Clock                             // 拷贝期间 GetCopyContext 返回的时间来源
+	skipZeroSource      bool                              // 源字段为零值时是否保留目标字段
+	whitelist           bool                              // 是否只拷贝白名单中的字段
+	whitelistKey        string                            // 白名单模式下额外认可的标签名
+	conversionPolicy    ConversionPolicy                  // CopyBetween、FromMap 的类型转换策略
+	fieldRenames        map[string]string                 // CopyBetween 匹配前的字段改名
+	mapTag              string                            // ToMap、FromMap 中字段键所用的标签名
+	snapshotMaps        bool                              // 是否先取出映射的全部条目再逐个拷贝
+	ioResourcePolicy    IOResourcePolicy                  // io 资源的处理策略
+	ignoreMethods       bool                              // 是否忽略 DeepCopy、DeepCopyWith 方法，总是按结构拷贝
+}
+
+// useFastPath 是否可以对只包含值类型的数据直接返回原值
+func (c *copyConfig) useFastPath() bool {
+	return !c.disableFastPath && c.fieldFilter == nil && c.typeConverters == nil &&
+		!c.skipZeroSource && !c.whitelist && !fastPathDisabled.Load()
+}
+
+// 默认拷贝配置
+var defaultCopyConfig = copyConfig{
+	allocator: reflectAllocator{},
+}
+
+// Option 拷贝选项
+type Option func(*copyConfig)
+
+// WithUnsafePointerPolicy 设置 unsafe.Pointer 的处理策略
+func WithUnsafePointerPolicy(p UnsafePointerPolicy) Option {
+	return func(c *copyConfig) {
+		c.unsafePointerPolicy = p
+	}
+}
+
+// WithAllocator 设置新切片、映射和指针的分配器，传入 nil 时使用默认分配器
+func WithAllocator(a Allocator) Option {
+	return func(c *copyConfig) {
+		if a == nil {
+			a = reflectAllocator{}
+		}
+		c.allocator = a
+	}
+}
+
+// WithEmptyCollectionsForNil 副本中把 nil 切片和映射替换为空的非 nil 值
+// 对任意深度生效，包括映射的值和接口中的动态值
+func WithEmptyCollectionsForNil() Option {
+	return func(c *copyConfig) {
+		c.nilCollections = emptyForNil
+	}
+}
+
+// WithNilForEmptyCollections 副本中把长度为 0 的切片和映射替换为 nil
+// 对任意深度生效，包括映射的值和接口中的动态值
+func WithNilForEmptyCollections() Option {
+	return func(c *copyConfig) {
+		c.nilCollections = nilForEmpty
+	}
+}
+
+// WithFieldFilter 设置字段过滤器，拷贝每个结构体字段前调用
+// fn 返回 false 时跳过该字段，副本中保持零值。可用于按权限、特性开关等运行时条件裁剪字段
+func WithFieldFilter(fn func(reflect.StructField) bool) Option {
+	return func(c *copyConfig) {
+		c.fieldFilter = fn
+	}
+}
+
+// WithSkipZeroSource 源结构体中为零值的字段不拷贝，CopyInto 时保留目标中原有的值，用于把部分更新合并到已有对象。
+// 零值按 reflect.Value.IsZero 判断：nil 切片、映射被跳过，非 nil 的空切片、映射会覆盖目标；
+// 结构体字段全为零值时整体跳过，否则逐字段合并；非 nil 的指针指向源对象的副本，不合并到目标原有的对象中
+func WithSkipZeroSource() Option {
+	return func(c *copyConfig) {
+		c.skipZeroSource = true
+	}
+}
+
+// WithTrimCapacity 副本中的切片按长度分配（cap == len）
+// 从大缓冲区切出的小切片默认会按原容量分配，使用该选项可避免副本占用多余内存
+func WithTrimCapacity() Option {
+	return func(c *copyConfig) {
+		c.trimCapacity = true
+	}
+}
+
+// WithoutFastPath 关闭只包含值类型时直接返回原值的优化，总是执行完整的递归拷贝
+func WithoutFastPath() Option {
+	return func(c *copyConfig) {
+		c.disableFastPath = true
+	}
+}
+
+// WithSharedMapKeys map 的键原样保留，值仍然深拷贝
+// 对于 map[*Node]T 或键中包含指针的接口，默认深拷贝键会产生新的指针，
+// 副本无法再用原始键查找；使用该选项后原始键在副本中依然有效。
+// 代价是副本与原始值共享键所指向的数据，修改键指向的对象会同时影响两者。
+func WithSharedMapKeys() Option {
+	return func(c *copyConfig) {
+		c.sharedMapKeys = true
+	}
+}
+
+// WithSnapshotMap 拷贝映射时先把全部条目取到临时切片中，再逐个深拷贝，缩短遍历原映射的时间窗口。
+// 拷贝每个条目前重新查找其键，拷贝期间（例如在 DeepCopy 方法或自定义拷贝函数中）被删除的键直接跳过，
+// 仍存在的键使用其当前的值。只有并发读取的映射无需该选项即可安全拷贝；
+// 并发写入仍是无法 recover 的致命错误，该选项不能代替 WithLocker
+func WithSnapshotMap() Option {
+	return func(c *copyConfig) {
+		c.snapshotMaps = true
+	}
+}
+
+// WithShallowInterfaces 接口类型的值直接共享，不再深拷贝其中的具体值
+// 适用于接口背后的值不可变或由其他模块管理的场景
+func WithShallowInterfaces() Option {
+	return func(c *copyConfig) {
+		c.shallowInterfaces = true
+	}
+}
+
+// WithFuncWrapper 拷贝每个非 nil 的函数值时调用 fn，副本中使用其返回值，
+// 可用于为副本中的回调增加日志或计数（例如通过 reflect.MakeFunc 包装原函数）。
+// 返回值必须与原函数类型相同，否则返回 ErrTypeConversion；未设置时函数值直接共享
+func WithFuncWrapper(fn func(orig reflect.Value) reflect.Value) Option {
+	return func(c *copyConfig) {
+		c.funcWrapper = fn
+	}
+}
+
+// WithShareFuncs 设置副本是否共享函数值，默认为 true。Go 中无法真正深拷贝函数，
+// 共享的闭包与原值共用其捕获的变量；设为 false 时副本中的函数字段为 nil，由调用方显式重新设置，
+// 此时 WithFuncWrapper 不再生效
+func WithShareFuncs(share bool) Option {
+	return func(c *copyConfig) {
+		c.zeroFuncs = !share
+	}
+}
+
+// WithIgnoreCustomCopiers 忽略所有层级上类型自定义的 DeepCopy、DeepCopyWith 方法，总是按结构逐字段拷贝，
+// 用于获取不经方法规范化或脱敏的原始状态（例如调试快照）。与按结构拷贝一致，未导出字段不会被复制；
+// 管理器中注册的自定义拷贝函数和 WithTypeConverter 不受影响
+func WithIgnoreCustomCopiers() Option {
+	return func(c *copyConfig) {
+		c.ignoreMethods = true
+	}
+}
+
+// WithIOResourcePolicy 设置 io 资源的处理策略。接口类型的值按其动态值判断，
+// 注册的自定义拷贝函数优先于该策略
+func WithIOResourcePolicy(p IOResourcePolicy) Option {
+	return func(c *copyConfig) {
+		c.ioResourcePolicy = p
+	}
+}
+
+// WithNaNKeyPolicy 设置 NaN 键的处理策略
+func WithNaNKeyPolicy(p NaNKeyPolicy) Option {
+	return func(c *copyConfig) {
+		c.nanKeyPolicy = p
+	}
+}
+
+// WithLocker 拷贝期间持有 l，用于与持有同一把锁的写入方互斥。
+// 锁在遍历开始前获取，因此源值应以指针、映射或切片的形式传入，才能在锁内读取到其内容。
+// 注意：Go 运行时检测到的 map 并发读写（concurrent map iteration and map write）
+// 是无法通过 recover 捕获的致命错误，拷贝并发写入的 map 时必须使用锁
+// （WithLocker 或 ConcurrentCopy），重试或 CopyRecoverable 都无法避免进程退出
+func WithLocker(l sync.Locker) Option {
+	return func(c *copyConfig) {
+		c.locker = l
+	}
+}
+
+// newCopyConfig 基于默认配置应用所有选项
+func newCopyConfig(opts []Option) *copyConfig {
+	cfg := defaultCopyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &cfg
+}
+
+// CopyE 按给定选项创建深拷贝，遍历过程中遇到的错误会被返回
+func CopyE[T any](src T, opts ...Option) (T, error) {
+	var zero T
+	cfg := newCopyConfig(opts)
+	if cfg.locker != nil {
+		cfg.locker.Lock()
+		defer cfg.locker.Unlock()
+	}
+
+	manager := getTypedManager[T]()
+	// T 为接口类型时没有静态类型信息，先按动态类型检查 DeepCopy 方法
+	if manager.rtype.Kind() == reflect.Interface && !cfg.ignoreMethods {
+		if result, ok, err := newCopyState(cfg).tryDeepCopyRoot(reflect.ValueOf(src)); err != nil {
+			return zero, err
+		} else if ok {
+			return result.Interface().(T), nil
+		}
+	}
+
+	// 性能优化：如果只包含值类型，直接返回原值（字段过滤需要逐字段处理，不能走快速路径）
+	analysis := manager.getOrAnalyzeType()
+	if cfg.useFastPath() && analysis.IsOnlyValues {
+		return src, nil
+	}
+
+	srcVal := reflect.ValueOf(src)
+	if !srcVal.IsValid() {
+		return zero, nil
+	}
+
+	// 遍历中（包括顶层 DeepCopy 方法中）的 panic 以带路径的 *PanicError 返回
+	state := newCopyState(cfg)
+	if analysis.useDeepCopy() && !cfg.ignoreMethods {
+		if result, ok, err := state.tryDeepCopyRoot(srcVal); err != nil {
+			return zero, err
+		} else if ok {
+			return result.Interface().(T), nil
+		}
+	}
+
+	var result T
+	if err := state.run(func() { result = copyToT[T](srcVal, state) }); err != nil {
+		return zero, err
+	}
+
+	return result, nil
+}
+
+// CopyWithOptions 按给定选项创建深拷贝，遇到错误时 panic
+func CopyWithOptions[T any](src T, opts ...Option) T {
+	result, err := CopyE(src, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
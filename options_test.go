@@ -0,0 +1,816 @@
+package deepcopy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// 包含 unsafe.Pointer 字段的结构体
+type UnsafeHolder struct {
+	Name string
+	Raw  unsafe.Pointer
+}
+
+func TestUnsafePointerAnalysis(t *testing.T) {
+	analysis := AnalyzeType(UnsafeHolder{})
+	if !analysis.ContainsUnsafePointer {
+		t.Error("UnsafeHolder should contain unsafe.Pointer")
+	}
+	if analysis.IsOnlyValues {
+		t.Error("UnsafeHolder should not be value-only")
+	}
+
+	if AnalyzeType([]*UnsafeHolder{}).ContainsUnsafePointer != true {
+		t.Error("[]*UnsafeHolder should contain unsafe.Pointer")
+	}
+	if AnalyzeType(OnlyValueStruct{}).ContainsUnsafePointer {
+		t.Error("OnlyValueStruct should not contain unsafe.Pointer")
+	}
+}
+
+func TestUnsafePointerPolicy(t *testing.T) {
+	value := 42
+	original := UnsafeHolder{Name: "raw", Raw: unsafe.Pointer(&value)}
+
+	var warnings []string
+	oldWarn := WarnFunc
+	WarnFunc = func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	defer func() { WarnFunc = oldWarn }()
+
+	t.Run("copy", func(t *testing.T) {
+		SetDefaultManagerOptions() // 重新分析类型，之前的拷贝输出过的警告重新计数
+		warnings = nil
+		copied := CopyWithOptions(original)
+		if copied.Raw != original.Raw {
+			t.Error("Raw should be copied as-is by default")
+		}
+		// 同一类型只警告一次，nil 不警告
+		CopyWithOptions(original)
+		CopyWithOptions(UnsafeHolder{Name: "nil"})
+		if len(warnings) != 1 {
+			t.Errorf("expected 1 warning, got %d", len(warnings))
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		copied := CopyWithOptions(original, WithUnsafePointerPolicy(UnsafePointerZero))
+		if copied.Raw != nil {
+			t.Error("Raw should be zeroed")
+		}
+		if copied.Name != original.Name {
+			t.Errorf("Name: got %q, want %q", copied.Name, original.Name)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := CopyE(original, WithUnsafePointerPolicy(UnsafePointerError))
+		if !errors.Is(err, ErrUnsafePointer) {
+			t.Errorf("expected ErrUnsafePointer, got %v", err)
+		}
+	})
+}
+
+// countingAllocator 统计各类分配次数的分配器
+type countingAllocator struct {
+	news, slices, maps int
+}
+
+func (a *countingAllocator) New(t reflect.Type) reflect.Value {
+	a.news++
+	return reflect.New(t)
+}
+
+func (a *countingAllocator) NewSlice(t reflect.Type, len, cap int) reflect.Value {
+	a.slices++
+	return reflect.MakeSlice(t, len, cap)
+}
+
+func (a *countingAllocator) NewMap(t reflect.Type, size int) reflect.Value {
+	a.maps++
+	return reflect.MakeMap(t)
+}
+
+type AllocFixture struct {
+	Ptr    *int
+	Items  []*int
+	Lookup map[string][]int
+}
+
+func TestWithAllocator(t *testing.T) {
+	a, b := 1, 2
+	original := &AllocFixture{
+		Ptr:    &a,
+		Items:  []*int{&a, &b},
+		Lookup: map[string][]int{"x": {1}, "y": {2, 3}},
+	}
+
+	alloc := &countingAllocator{}
+	copied := CopyWithOptions(original, WithAllocator(alloc))
+
+	// 指针：original、Ptr、Items[1]（Items[0] 与 Ptr 指向同一地址，复用已复制的指针）
+	if alloc.news != 3 {
+		t.Errorf("New: got %d allocations, want 3", alloc.news)
+	}
+	// 切片：Items、Lookup["x"]、Lookup["y"]
+	if alloc.slices != 3 {
+		t.Errorf("NewSlice: got %d allocations, want 3", alloc.slices)
+	}
+	if alloc.maps != 1 {
+		t.Errorf("NewMap: got %d allocations, want 1", alloc.maps)
+	}
+
+	if !reflect.DeepEqual(copied, original) || copied.Ptr == original.Ptr {
+		t.Error("copy with custom allocator should be an independent equal value")
+	}
+}
+
+type CollectionHolder struct {
+	Slice  []int
+	Map    map[string]int
+	Nested map[string][]string
+	Any    interface{}
+}
+
+func TestWithEmptyCollectionsForNil(t *testing.T) {
+	original := CollectionHolder{
+		Nested: map[string][]string{"k": nil},
+		Any:    []int(nil),
+	}
+
+	copied := CopyWithOptions(original, WithEmptyCollectionsForNil())
+	if copied.Slice == nil || len(copied.Slice) != 0 {
+		t.Error("nil slice field should become empty")
+	}
+	if copied.Map == nil {
+		t.Error("nil map field should become empty")
+	}
+	if copied.Nested["k"] == nil {
+		t.Error("nil slice map value should become empty")
+	}
+	if s, ok := copied.Any.([]int); !ok || s == nil {
+		t.Error("nil slice inside interface should become empty")
+	}
+
+	// 默认行为保持不变
+	if CopyWithOptions(original).Slice != nil {
+		t.Error("nil slice should remain nil by default")
+	}
+}
+
+func TestWithNilForEmptyCollections(t *testing.T) {
+	original := CollectionHolder{
+		Slice:  []int{},
+		Map:    map[string]int{},
+		Nested: map[string][]string{"k": {}},
+		Any:    map[string]int{},
+	}
+
+	copied := CopyWithOptions(original, WithNilForEmptyCollections())
+	if copied.Slice != nil {
+		t.Error("empty slice field should become nil")
+	}
+	if copied.Map != nil {
+		t.Error("empty map field should become nil")
+	}
+	if v, ok := copied.Nested["k"]; !ok || v != nil {
+		t.Error("empty slice map value should become nil")
+	}
+	if m, ok := copied.Any.(map[string]int); !ok || m != nil {
+		t.Error("empty map inside interface should become nil")
+	}
+
+	// 默认行为保持不变
+	if CopyWithOptions(original).Slice == nil {
+		t.Error("empty slice should remain non-nil by default")
+	}
+}
+
+type FilteredRecord struct {
+	Name      string
+	Secret    string `perm:"admin"`
+	Tags      []string
+	CreatedAt time.Time
+	Audit     *AuditInfo
+}
+
+type AuditInfo struct {
+	UpdatedAt time.Time
+	By        string
+}
+
+func TestWithFieldFilter(t *testing.T) {
+	now := time.Now()
+	original := FilteredRecord{
+		Name:      "record",
+		Secret:    "s3cr3t",
+		Tags:      []string{"a"},
+		CreatedAt: now,
+		Audit:     &AuditInfo{UpdatedAt: now, By: "bob"},
+	}
+
+	t.Run("time fields", func(t *testing.T) {
+		timeType := reflect.TypeOf(time.Time{})
+		copied := CopyWithOptions(original, WithFieldFilter(func(f reflect.StructField) bool {
+			return f.Type != timeType
+		}))
+		if !copied.CreatedAt.IsZero() || !copied.Audit.UpdatedAt.IsZero() {
+			t.Error("time.Time fields should be zero at every depth")
+		}
+		if copied.Name != "record" || copied.Audit.By != "bob" || len(copied.Tags) != 1 {
+			t.Errorf("other fields should be copied, got %+v", copied)
+		}
+	})
+
+	t.Run("tag presence", func(t *testing.T) {
+		copied := CopyWithOptions(original, WithFieldFilter(func(f reflect.StructField) bool {
+			_, restricted := f.Tag.Lookup("perm")
+			return !restricted
+		}))
+		if copied.Secret != "" {
+			t.Errorf("Secret should be zero, got %q", copied.Secret)
+		}
+		if copied.Name != "record" {
+			t.Errorf("Name: got %q, want %q", copied.Name, "record")
+		}
+	})
+
+	t.Run("value-only struct", func(t *testing.T) {
+		copied := CopyWithOptions(OnlyValueStruct{Name: "Alice", Age: 30}, WithFieldFilter(func(f reflect.StructField) bool {
+			return f.Name != "Age"
+		}))
+		if copied.Age != 0 || copied.Name != "Alice" {
+			t.Errorf("filter should apply to value-only structs, got %+v", copied)
+		}
+	})
+}
+
+func TestWithTrimCapacity(t *testing.T) {
+	buffer := make([]int, 1000)
+	original := struct {
+		Window []int
+		Nested [][]int
+	}{
+		Window: buffer[:10],
+		Nested: [][]int{buffer[10:12]},
+	}
+
+	copied := CopyWithOptions(original, WithTrimCapacity())
+	if cap(copied.Window) != len(copied.Window) {
+		t.Errorf("Window: cap %d, want %d", cap(copied.Window), len(copied.Window))
+	}
+	if cap(copied.Nested[0]) != 2 {
+		t.Errorf("Nested[0]: cap %d, want 2", cap(copied.Nested[0]))
+	}
+
+	// 默认保留原容量
+	if cap(CopyWithOptions(original).Window) != cap(original.Window) {
+		t.Error("capacity should be preserved by default")
+	}
+}
+
+func TestWithoutFastPath(t *testing.T) {
+	original := ValueLookingStruct{Name: "outer", Copier: CustomCopier{Value: 5}}
+
+	copied := CopyWithOptions(original, WithoutFastPath())
+	if copied.Copier.Value != 10 {
+		t.Errorf("Copier.Value: got %d, want 10", copied.Copier.Value)
+	}
+
+	plain := OnlyValueStruct{Name: "Alice", Age: 30}
+	if got := CopyWithOptions(plain, WithoutFastPath()); got != plain {
+		t.Errorf("got %+v, want %+v", got, plain)
+	}
+}
+
+func TestSetDisableFastPath(t *testing.T) {
+	SetDisableFastPath(true)
+	defer SetDisableFastPath(false)
+
+	original := ValueLookingStruct{Name: "outer", Copier: CustomCopier{Value: 5}}
+	if got := Copy(original); got.Copier.Value != 10 {
+		t.Errorf("Copy: Copier.Value got %d, want 10", got.Copier.Value)
+	}
+	if got := CopyWithKey(original, "fast.path.disabled"); got.Copier.Value != 10 {
+		t.Errorf("CopyWithKey: Copier.Value got %d, want 10", got.Copier.Value)
+	}
+
+	plain := OnlyValueStruct{Name: "Alice", Age: 30}
+	if got := Copy(plain); got != plain {
+		t.Errorf("got %+v, want %+v", got, plain)
+	}
+}
+
+// ExplodingCopier 的 DeepCopy 总是 panic，模拟拷贝中的内部错误
+type ExplodingCopier struct {
+	Items []int
+}
+
+func (ExplodingCopier) DeepCopy() ExplodingCopier {
+	panic("boom")
+}
+
+func TestSetRecoverMode(t *testing.T) {
+	var recovered []any
+	SetRecoverMode(func(r any) { recovered = append(recovered, r) })
+	t.Cleanup(func() { SetRecoverMode(nil) })
+
+	got := Copy(ExplodingCopier{Items: []int{1}})
+	if got.Items != nil {
+		t.Errorf("got %+v, want zero value", got)
+	}
+	if len(recovered) != 1 || recovered[0] != "boom" {
+		t.Errorf("recovered = %v, want [boom]", recovered)
+	}
+
+	// 没有 panic 时不调用钩子
+	if got := Copy([]int{1, 2}); len(got) != 2 || len(recovered) != 1 {
+		t.Errorf("got %v, recovered %v", got, recovered)
+	}
+
+	// 关闭后恢复默认行为
+	SetRecoverMode(nil)
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected panic to propagate, got %v", r)
+		}
+	}()
+	Copy(ExplodingCopier{})
+}
+
+type KeyNode struct {
+	ID int
+}
+
+func TestWithSharedMapKeys(t *testing.T) {
+	a, b := &KeyNode{ID: 1}, &KeyNode{ID: 2}
+	original := map[*KeyNode][]string{a: {"x"}, b: {"y"}}
+
+	copied := CopyWithOptions(original, WithSharedMapKeys())
+	for key, value := range original {
+		got, ok := copied[key]
+		if !ok {
+			t.Errorf("lookup by original key %d should succeed", key.ID)
+			continue
+		}
+		if &got[0] == &value[0] {
+			t.Error("values should still be deep-copied")
+		}
+	}
+
+	// 接口键中的指针同样保留
+	ifaceMap := map[interface{}]int{a: 1}
+	if CopyWithOptions(ifaceMap, WithSharedMapKeys())[a] != 1 {
+		t.Error("lookup by original interface key should succeed")
+	}
+
+	// 默认深拷贝键，原始键无法查找
+	if _, ok := CopyWithOptions(original)[a]; ok {
+		t.Error("keys should be deep-copied by default")
+	}
+}
+
+// SnapshotEntry DeepCopy 时删除所在映射中的其他条目
+type SnapshotEntry struct {
+	Name  string
+	owner map[string]*SnapshotEntry
+}
+
+func (e *SnapshotEntry) DeepCopy() *SnapshotEntry {
+	for key := range e.owner {
+		if key != e.Name {
+			delete(e.owner, key)
+		}
+	}
+	return &SnapshotEntry{Name: e.Name}
+}
+
+func TestWithSnapshotMap(t *testing.T) {
+	original := map[string]*SnapshotEntry{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		original[name] = &SnapshotEntry{Name: name, owner: original}
+	}
+
+	// 第一个拷贝的条目删除其余条目，之后被删除的键都被跳过
+	copied := CopyWithOptions(original, WithSnapshotMap())
+	if len(original) != 1 || len(copied) != 1 {
+		t.Fatalf("got %d entries from %d remaining", len(copied), len(original))
+	}
+	for key, entry := range original {
+		if got := copied[key]; got == nil || got == entry || got.Name != key {
+			t.Errorf("copied[%q] = %+v", key, got)
+		}
+	}
+}
+
+type FloatKey struct {
+	Name  string
+	Value float64
+}
+
+func TestNaNKeyPolicy(t *testing.T) {
+	original := map[float64]int{math.NaN(): 1, 1.5: 2}
+
+	if got := CopyWithOptions(original); len(got) != 2 || got[1.5] != 2 {
+		t.Errorf("default should preserve NaN entries, got %v", got)
+	}
+	if got := CopyWithOptions(original, WithNaNKeyPolicy(NaNKeyDrop)); len(got) != 1 || got[1.5] != 2 {
+		t.Errorf("NaNKeyDrop: got %v, want map[1.5:2]", got)
+	}
+	if _, err := CopyE(original, WithNaNKeyPolicy(NaNKeyError)); !errors.Is(err, ErrNaNMapKey) {
+		t.Errorf("NaNKeyError: expected ErrNaNMapKey, got %v", err)
+	}
+
+	structKeys := map[FloatKey]string{
+		{Name: "nan", Value: math.NaN()}: "a",
+		{Name: "one", Value: 1}:          "b",
+	}
+	if got := CopyWithOptions(structKeys); len(got) != 2 {
+		t.Errorf("default should preserve NaN struct keys, got %d entries", len(got))
+	}
+	got := CopyWithOptions(structKeys, WithNaNKeyPolicy(NaNKeyDrop))
+	if len(got) != 1 || got[FloatKey{Name: "one", Value: 1}] != "b" {
+		t.Errorf("NaNKeyDrop with struct keys: got %v", got)
+	}
+	if _, err := CopyE(structKeys, WithNaNKeyPolicy(NaNKeyError)); !errors.Is(err, ErrNaNMapKey) {
+		t.Errorf("NaNKeyError with struct keys: expected ErrNaNMapKey, got %v", err)
+	}
+}
+
+func TestWithLocker(t *testing.T) {
+	var mu sync.Mutex
+	shared := map[string][]int{"a": {}, "b": {}, "c": {}}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			// 每次在锁内把所有值更新为同一长度，拷贝只能看到一致的状态
+			mu.Lock()
+			for k := range shared {
+				shared[k] = make([]int, i%16)
+			}
+			shared[fmt.Sprint("k", i%8)] = make([]int, i%16)
+			mu.Unlock()
+		}
+	}()
+
+	for i := 0; i < 200; i++ {
+		copied, err := CopyE(shared, WithLocker(&mu))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := len(copied["a"])
+		for k, v := range copied {
+			if len(v) != want {
+				t.Fatalf("inconsistent snapshot: %s has %d elements, want %d", k, len(v), want)
+			}
+		}
+	}
+	close(stop)
+	<-done
+}
+
+// 插件系统中由其他模块管理的值
+type PluginHost struct {
+	Name   string
+	Plugin interface{}
+	Extras []any
+}
+
+func TestWithShallowInterfaces(t *testing.T) {
+	plugin := &TestStruct{Int: 1}
+	original := PluginHost{Name: "host", Plugin: plugin, Extras: []any{plugin}}
+
+	copied := CopyWithOptions(original, WithShallowInterfaces())
+	if copied.Plugin.(*TestStruct) != plugin || copied.Extras[0].(*TestStruct) != plugin {
+		t.Error("interface values should be shared under WithShallowInterfaces")
+	}
+	if &copied.Extras[0] == &original.Extras[0] {
+		t.Error("the slice holding interfaces should still be copied")
+	}
+
+	// 默认仍然深拷贝
+	if Copy(original).Plugin.(*TestStruct) == plugin {
+		t.Error("interface values should be deep copied by default")
+	}
+}
+
+// 带回调的任务
+type Job struct {
+	Name   string
+	OnDone func(string) string
+}
+
+func TestWithFuncWrapper(t *testing.T) {
+	original := Job{Name: "job", OnDone: strings.ToUpper}
+
+	var calls int
+	copied := CopyWithOptions(original, WithFuncWrapper(func(orig reflect.Value) reflect.Value {
+		return reflect.MakeFunc(orig.Type(), func(args []reflect.Value) []reflect.Value {
+			calls++
+			return orig.Call(args)
+		})
+	}))
+
+	if got := copied.OnDone("done"); got != "DONE" || calls != 1 {
+		t.Errorf("wrapped func: got %q with %d calls", got, calls)
+	}
+	if original.OnDone("done"); calls != 1 {
+		t.Error("the original func should not be counted")
+	}
+
+	// 默认共享函数值
+	if reflect.ValueOf(Copy(original).OnDone).Pointer() != reflect.ValueOf(original.OnDone).Pointer() {
+		t.Error("funcs should be shared by default")
+	}
+
+	_, err := CopyE(original, WithFuncWrapper(func(reflect.Value) reflect.Value {
+		return reflect.ValueOf(func() {})
+	}))
+	if !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("expected ErrTypeConversion for a mismatched wrapper, got %v", err)
+	}
+}
+
+func TestWithShareFuncs(t *testing.T) {
+	original := Job{Name: "job", OnDone: strings.ToUpper}
+
+	copied := CopyWithOptions(original, WithShareFuncs(false))
+	if copied.OnDone != nil || copied.Name != "job" {
+		t.Errorf("funcs should be nil in the copy: %+v", copied)
+	}
+	// 优先于 WithFuncWrapper
+	wrapped := CopyWithOptions(original, WithShareFuncs(false), WithFuncWrapper(func(orig reflect.Value) reflect.Value {
+		return orig
+	}))
+	if wrapped.OnDone != nil {
+		t.Error("WithShareFuncs(false) should take precedence over WithFuncWrapper")
+	}
+	if _, err := CopyE(original, WithShareFuncs(false), WithStrict()); err == nil {
+		t.Error("strict mode should report the dropped func")
+	}
+
+	if shared := CopyWithOptions(original, WithShareFuncs(true)); shared.OnDone == nil {
+		t.Error("funcs should be shared with WithShareFuncs(true)")
+	}
+}
+
+// FuncJobs 包含函数字段的嵌套结构体
+type FuncJobs struct {
+	Main  Job
+	Extra []Job
+}
+
+// RawSnapshot 各层级都有自定义 DeepCopy、DeepCopyWith 方法的值
+type RawSnapshot struct {
+	Top   CustomCopier
+	Items []CustomCopier
+	ByPtr *CustomCopier
+	Node  *MemoNode
+}
+
+func TestWithIgnoreCustomCopiers(t *testing.T) {
+	node := &MemoNode{Name: "n"}
+	node.Edges = []*MemoNode{node}
+	original := RawSnapshot{
+		Top:   CustomCopier{Value: 1},
+		Items: []CustomCopier{{Value: 2}},
+		ByPtr: &CustomCopier{Value: 3},
+		Node:  node,
+	}
+
+	calls := memoNodeCopies
+	copied := CopyWithOptions(original, WithIgnoreCustomCopiers())
+	if copied.Top.Value != 1 || copied.Items[0].Value != 2 || copied.ByPtr.Value != 3 || copied.ByPtr == original.ByPtr {
+		t.Errorf("DeepCopy methods should be ignored: %+v", copied)
+	}
+	if copied.Node == node || copied.Node.Name != "n" || copied.Node.Edges[0] != copied.Node || memoNodeCopies != calls {
+		t.Errorf("DeepCopyWith should be ignored: %+v", copied.Node)
+	}
+
+	// 顶层的值（包括接口中的）同样按结构拷贝
+	if got := CopyWithOptions(CustomCopier{Value: 4}, WithIgnoreCustomCopiers()); got.Value != 4 {
+		t.Errorf("top-level CustomCopier = %+v", got)
+	}
+	if got := CopyWithOptions[any](CustomCopier{Value: 5}, WithIgnoreCustomCopiers()); got.(CustomCopier).Value != 5 {
+		t.Errorf("CustomCopier in interface = %+v", got)
+	}
+	var into RawSnapshot
+	if err := CopyInto(&into, original, WithIgnoreCustomCopiers()); err != nil || into.Top.Value != 1 {
+		t.Errorf("CopyInto = %+v, %v", into, err)
+	}
+
+	// 缓存的分析结果不受选项影响
+	if got := Copy(original); got.Top.Value != 2 || got.Items[0].Value != 4 || got.Node.Name != "n'" {
+		t.Errorf("default copy should still use the methods: %+v", got)
+	}
+	if !AnalyzeType(CustomCopier{}).HasDeepCopyMethod {
+		t.Error("analysis should still record the DeepCopy method")
+	}
+}
+
+// IOConn 包含 io 资源的结构体
+type IOConn struct {
+	Name  string
+	Body  io.ReadCloser
+	Log   *bytes.Buffer
+	Extra any
+}
+
+func TestIOResourceAnalysis(t *testing.T) {
+	if !AnalyzeType(IOConn{}).ContainsIOResource {
+		t.Error("IOConn should contain io resources")
+	}
+	if !AnalyzeType(map[string][]*IOConn{}).ContainsIOResource {
+		t.Error("map[string][]*IOConn should contain io resources")
+	}
+	if !AnalyzeType(bytes.Buffer{}).ContainsIOResource {
+		t.Error("bytes.Buffer should count through its pointer methods")
+	}
+	if AnalyzeType(struct{ Extra any }{}).ContainsIOResource || AnalyzeType(OnlyValueStruct{}).ContainsIOResource {
+		t.Error("types without io resources should not be flagged")
+	}
+}
+
+func TestIOResourcePolicy(t *testing.T) {
+	body := io.NopCloser(strings.NewReader("body"))
+	extra := strings.NewReader("extra")
+	original := IOConn{Name: "conn", Body: body, Log: bytes.NewBufferString("log"), Extra: extra}
+
+	var warnings []string
+	oldWarn := WarnFunc
+	WarnFunc = func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	defer func() { WarnFunc = oldWarn }()
+
+	t.Run("share", func(t *testing.T) {
+		copied := CopyWithOptions(original, WithIOResourcePolicy(IOResourceShare))
+		if copied.Body != body || copied.Log != original.Log || copied.Extra != extra {
+			t.Errorf("io resources should be shared: %+v", copied)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		copied := CopyWithOptions(original, WithIOResourcePolicy(IOResourceNil))
+		if copied.Body != nil || copied.Log != nil || copied.Extra != nil || copied.Name != "conn" {
+			t.Errorf("io resources should be zeroed: %+v", copied)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := CopyE(original, WithIOResourcePolicy(IOResourceError))
+		if !errors.Is(err, ErrIOResource) {
+			t.Errorf("expected ErrIOResource, got %v", err)
+		}
+		if _, err := CopyE(IOConn{Name: "idle"}, WithIOResourcePolicy(IOResourceError)); err != nil {
+			t.Errorf("nil resources should copy: %v", err)
+		}
+	})
+
+	if len(warnings) != 0 {
+		t.Errorf("no warning expected with an explicit policy, got %q", warnings)
+	}
+
+	// 默认按普通值拷贝，每个类型只警告一次
+	copied := Copy(original)
+	Copy(original)
+	if copied.Log == nil || copied.Log == original.Log {
+		t.Error("Log should be copied to a new buffer by default")
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "deepcopy.IOConn contains io.Reader/io.Writer/io.Closer values") {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
+
+// HandleHolder 持有文件和网络连接的结构体
+type HandleHolder struct {
+	Name  string
+	File  *os.File
+	Conn  net.Conn
+	Extra any
+}
+
+func TestCopyHandles(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "handle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	conn, peer := net.Pipe()
+	defer conn.Close()
+	defer peer.Close()
+
+	oldWarn := WarnFunc
+	WarnFunc = func(string, ...any) {}
+	defer func() { WarnFunc = oldWarn }()
+
+	original := HandleHolder{Name: "h", File: file, Conn: conn, Extra: file}
+	copied := Copy(original)
+	if copied.File != file || copied.Conn != conn || copied.Extra != file {
+		t.Errorf("handles should be shared: %+v", copied)
+	}
+	if ptr := Copy(file); ptr != file {
+		t.Error("top-level *os.File should be shared")
+	}
+	if _, err := copied.File.WriteString("ok"); err != nil {
+		t.Errorf("shared file should stay usable: %v", err)
+	}
+
+	zeroed := CopyWithOptions(original, WithIOResourcePolicy(IOResourceNil))
+	if zeroed.File != nil || zeroed.Conn != nil || zeroed.Extra != nil || zeroed.Name != "h" {
+		t.Errorf("handles should be nil: %+v", zeroed)
+	}
+}
+
+// 包含函数字段的结构体在共享函数值时只警告一次，只针对最外层的类型
+func TestFuncFieldWarning(t *testing.T) {
+	var warnings []string
+	oldWarn := WarnFunc
+	WarnFunc = func(format string, args ...any) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	defer func() { WarnFunc = oldWarn }()
+
+	original := FuncJobs{Main: Job{OnDone: strings.ToUpper}, Extra: []Job{{OnDone: strings.ToLower}}}
+	CopyWithOptions(original, WithShareFuncs(false))
+	if len(warnings) != 0 {
+		t.Errorf("no warning expected when funcs are not shared, got %q", warnings)
+	}
+
+	Copy(original)
+	Copy(original)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "deepcopy.FuncJobs contains func fields") {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
+
+// 从对象池分配的链表节点
+type PooledNode struct {
+	Name  string
+	Next  *PooledNode
+	stale int
+}
+
+func TestWithAllocHook(t *testing.T) {
+	original := &PooledNode{Name: "a", Next: &PooledNode{Name: "b"}}
+	nodeType := reflect.TypeOf(PooledNode{})
+
+	pool := []*PooledNode{{Name: "dirty", stale: 1}, {Name: "dirty", stale: 2}}
+	handed := map[*PooledNode]bool{}
+	hook := func(t reflect.Type) (reflect.Value, bool) {
+		if t != nodeType || len(pool) == 0 {
+			return reflect.Value{}, false
+		}
+		node := pool[len(pool)-1]
+		pool = pool[:len(pool)-1]
+		handed[node] = true
+		return reflect.ValueOf(node), true
+	}
+
+	var record PooledAllocs
+	copied := CopyWithOptions(original, WithAllocHook(hook, &record))
+	if !handed[copied] || !handed[copied.Next] {
+		t.Error("pooled objects should be used as the new pointers")
+	}
+	if copied.Name != "a" || copied.Next.Name != "b" || copied.stale != 0 || copied.Next.stale != 0 {
+		t.Errorf("pooled objects should be zeroed before use, got %+v, %+v", *copied, *copied.Next)
+	}
+	if len(record.Values) != 2 || record.Values[0].Interface() != copied || record.Values[1].Interface() != copied.Next {
+		t.Errorf("record should list the consumed pooled objects, got %v", record.Values)
+	}
+
+	// 钩子不提供对象时行为不变
+	record = PooledAllocs{}
+	copied = CopyWithOptions(original, WithAllocHook(hook, &record))
+	if !reflect.DeepEqual(copied, original) || copied == original || len(record.Values) != 0 {
+		t.Errorf("declined allocations should fall back to the default allocator, got %+v, %v", copied, record.Values)
+	}
+
+	_, err := CopyE(original, WithAllocHook(func(reflect.Type) (reflect.Value, bool) {
+		return reflect.ValueOf(1), true
+	}, nil))
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("expected *PanicError for a mistyped hook result, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PanicError 拷贝过程中发生的 panic，被恢复后以错误形式返回
+type PanicError struct {
+	Value any    // recover() 得到的值
+	Path  string // panic 发生时正在拷贝的位置，如 Items[2].Name，根值为空
+}
+
+func (e *PanicError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("deepcopy: panic during copy: %v", e.Value)
+	}
+	return fmt.Sprintf("deepcopy: panic during copy at %s: %v", e.Path, e.Value)
+}
+
+// Unwrap panic 的值是 error 时返回该错误
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// pathSegment 路径中的一段：结构体字段（structType 的第 index 个字段）、切片或数组下标、映射的键
+type pathSegment struct {
+	structType reflect.Type
+	index      int
+	key        reflect.Value
+}
+
+// pushField 进入结构体 t 的第 i 个字段，字段名在生成路径字符串时才读取
+func (s *copyState) pushField(t reflect.Type, i int) {
+	if s.trackPath {
+		s.path = append(s.path, pathSegment{structType: t, index: i})
+	}
+}
+
+// pushIndex 进入切片或数组元素
+func (s *copyState) pushIndex(i int) {
+	if s.trackPath {
+		s.path = append(s.path, pathSegment{index: i})
+	}
+}
+
+// pushKey 进入映射条目
+func (s *copyState) pushKey(key reflect.Value) {
+	if s.trackPath {
+		s.path = append(s.path, pathSegment{key: key})
+	}
+}
+
+// popPath 离开最近进入的一段路径
+func (s *copyState) popPath() {
+	if s.trackPath {
+		s.path = s.path[:len(s.path)-1]
+	}
+}
+
+// pathString 当前路径的字符串形式
+func (s *copyState) pathString() string {
+	return formatPath(s.path)
+}
+
+// formatPath 路径的字符串形式，如 Items[2].Name、Labels["env"]
+func formatPath(path []pathSegment) string {
+	var b strings.Builder
+	for _, seg := range path {
+		switch {
+		case seg.structType != nil:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(seg.structType.Field(seg.index).Name)
+		case seg.key.IsValid():
+			if seg.key.Kind() == reflect.String {
+				fmt.Fprintf(&b, "[%q]", seg.key.String())
+			} else {
+				fmt.Fprintf(&b, "[%v]", seg.key)
+			}
+		default:
+			fmt.Fprintf(&b, "[%d]", seg.index)
+		}
+	}
+	return b.String()
+}
+
+// run 执行遍历，把遍历中的 panic 转换为带路径的 *PanicError
+func (s *copyState) run(fn func()) (err error) {
+	s.trackPath = true
+	// 所有接受 Option 的入口都经过这里，WithClock 指定的时间来源对其中调用的 DeepCopy 方法可见
+	defer s.cfg.enterContext()()
+	defer func() {
+		if r := recover(); r != nil {
+			// DeepCopy 方法返回的错误已带有路径，原样返回
+			if copierErr, ok := r.(*CopierError); ok {
+				err = copierErr
+				return
+			}
+			err = &PanicError{Value: r, Path: s.pathString()}
+		}
+	}()
+	fn()
+	if s.err == nil && len(s.issues) > 0 {
+		return &IncompleteCopyError{Issues: s.issues}
+	}
+	return s.err
+}
+
+// tryDeepCopyRoot 在 run 中调用顶层值的 DeepCopy 方法，方法中的 panic 与遍历中的一样转换为 *PanicError
+func (s *copyState) tryDeepCopyRoot(srcVal reflect.Value) (result reflect.Value, ok bool, err error) {
+	err = s.run(func() {
+		result, ok, s.err = tryDeepCopyE(srcVal)
+	})
+	return result, ok, err
+}
@@ -0,0 +1,146 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// badPtrCopier 的 DeepCopy 返回了不相关的类型
+type badPtrCopier struct{ N int }
+
+func (b *badPtrCopier) DeepCopy() int { return b.N }
+
+type badCopierHolder struct {
+	Items []*badPtrCopier
+}
+
+// shortSliceAllocator 分配的切片长度不足
+type shortSliceAllocator struct {
+	reflectAllocator
+}
+
+func (shortSliceAllocator) NewSlice(t reflect.Type, length, capacity int) reflect.Value {
+	return reflect.MakeSlice(t, 0, 0)
+}
+
+// invalidMapAllocator 分配映射时返回无效值
+type invalidMapAllocator struct {
+	reflectAllocator
+}
+
+func (invalidMapAllocator) NewMap(t reflect.Type, size int) reflect.Value {
+	return reflect.Value{}
+}
+
+type pathHolder struct {
+	Groups map[string][]string
+}
+
+func TestCopyEPanicPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		copy    func() error
+		path    string
+		message string
+	}{
+		{
+			name: "bad custom copier result",
+			copy: func() error {
+				_, err := CopyE(badCopierHolder{Items: []*badPtrCopier{{N: 1}}})
+				return err
+			},
+			path:    "Items[0]",
+			message: "not assignable",
+		},
+		{
+			name: "invalid map from allocator",
+			copy: func() error {
+				_, err := CopyE(pathHolder{Groups: map[string][]string{"admins": {"root"}}},
+					WithAllocator(invalidMapAllocator{}))
+				return err
+			},
+			path:    "Groups",
+			message: "zero Value",
+		},
+		{
+			name: "short slice inside map",
+			copy: func() error {
+				_, err := CopyE(map[string][]any{"admins": {"root"}},
+					WithAllocator(shortSliceAllocator{}))
+				return err
+			},
+			path:    `["admins"][0]`,
+			message: "index out of range",
+		},
+		{
+			name: "top-level DeepCopy",
+			copy: func() error {
+				_, err := CopyE(ExplodingCopier{})
+				return err
+			},
+			message: "boom",
+		},
+		{
+			name: "top-level DeepCopy behind interface",
+			copy: func() error {
+				_, err := CopyE[any](ExplodingCopier{})
+				return err
+			},
+			message: "boom",
+		},
+		{
+			name: "top-level DeepCopy without cycle tracking",
+			copy: func() error {
+				_, err := CopyNoCyclesE(ExplodingCopier{})
+				return err
+			},
+			message: "boom",
+		},
+		{
+			name: "top-level DeepCopy into destination",
+			copy: func() error {
+				var dst ExplodingCopier
+				return CopyInto(&dst, ExplodingCopier{})
+			},
+			message: "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.copy()
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("expected *PanicError, got %v", err)
+			}
+			if panicErr.Path != tt.path {
+				t.Errorf("path = %q, want %q", panicErr.Path, tt.path)
+			}
+			if !strings.Contains(err.Error(), tt.message) || !strings.Contains(err.Error(), tt.path) {
+				t.Errorf("unexpected error message %q", err)
+			}
+		})
+	}
+}
+
+func TestCopyStillPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Copy should keep panicking for compatibility")
+		}
+	}()
+	Copy(badCopierHolder{Items: []*badPtrCopier{{N: 1}}})
+}
+
+func TestPanicErrorUnwrap(t *testing.T) {
+	cause := errors.New("boom")
+	err := &PanicError{Value: cause, Path: "A.B"}
+	if !errors.Is(err, cause) {
+		t.Error("PanicError should unwrap error values")
+	}
+	if err.Error() != "deepcopy: panic during copy at A.B: boom" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
@@ -0,0 +1,75 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DumpPlan 以缩进文本展示类型 T 的拷贝计划：每个字段（以及指针、切片、映射、数组的元素）的类型、种类，
+// 以及是按值复制还是需要深拷贝，用于排查某个类型拷贝较慢的原因。
+// 按值复制的类型不再展开；递归引用自身的类型标记为 (cycle)
+func DumpPlan[T any]() string {
+	var b strings.Builder
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	dumpPlan(&b, "", t, 0, map[reflect.Type]bool{})
+	return b.String()
+}
+
+// dumpPlan 输出类型 t 的一行计划，并递归输出其子项
+func dumpPlan(b *strings.Builder, name string, t reflect.Type, depth int, onPath map[reflect.Type]bool) {
+	b.WriteString(strings.Repeat("  ", depth))
+	if name != "" {
+		b.WriteString(name)
+		b.WriteByte(' ')
+	}
+	fmt.Fprintf(b, "%s (%s): ", t, t.Kind())
+
+	if onPath[t] {
+		b.WriteString("deep copy (cycle)\n")
+		return
+	}
+
+	analysis := defaultManager.getOrAnalyzeType(t)
+	switch {
+	case analysis.HasDeepCopyMethod:
+		b.WriteString("DeepCopy method\n")
+		return
+	case analysis.IsOnlyValues:
+		b.WriteString("value\n")
+		return
+	case t.Kind() == reflect.Chan || t.Kind() == reflect.Func:
+		b.WriteString("shared\n")
+		return
+	case t.Kind() == reflect.Struct && (analysis.ImplementsBinaryMarshaler || analysis.ImplementsTextMarshaler):
+		b.WriteString("marshal round-trip\n")
+		return
+	}
+	b.WriteString("deep copy\n")
+
+	onPath[t] = true
+	defer delete(onPath, t)
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				fmt.Fprintf(b, "%s%s %s (%s): skipped (unexported)\n",
+					strings.Repeat("  ", depth+1), field.Name, field.Type, field.Type.Kind())
+				continue
+			}
+			if defaultManager.isRedacted(field) {
+				fmt.Fprintf(b, "%s%s %s (%s): zeroed (redact)\n",
+					strings.Repeat("  ", depth+1), field.Name, field.Type, field.Type.Kind())
+				continue
+			}
+			dumpPlan(b, field.Name, field.Type, depth+1, onPath)
+		}
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		dumpPlan(b, "[elem]", t.Elem(), depth+1, onPath)
+	case reflect.Map:
+		dumpPlan(b, "[key]", t.Key(), depth+1, onPath)
+		dumpPlan(b, "[value]", t.Elem(), depth+1, onPath)
+	}
+}
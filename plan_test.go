@@ -0,0 +1,30 @@
+package deepcopy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDumpPlan(t *testing.T) {
+	plan := DumpPlan[WithReferenceStruct]()
+	for _, want := range []string{
+		"deepcopy.WithReferenceStruct (struct): deep copy\n",
+		"  Name string (string): value\n",
+		"  Friends []string (slice): deep copy\n",
+		"    [elem] string (string): value\n",
+		"  Data *string (ptr): deep copy\n",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("plan should contain %q, got:\n%s", want, plan)
+		}
+	}
+
+	// 递归类型标记为 cycle，不会无限展开
+	plan = DumpPlan[Node]()
+	if !strings.Contains(plan, "[elem] deepcopy.Node (struct): deep copy (cycle)") {
+		t.Errorf("recursive type should be marked as cycle, got:\n%s", plan)
+	}
+	if !strings.Contains(DumpPlan[TestStruct](), "unexported string (string): skipped (unexported)") {
+		t.Error("unexported fields should be reported as skipped")
+	}
+}
@@ -0,0 +1,43 @@
+package deepcopy
+
+import "sync"
+
+// CopyWithPool 返回一个拷贝函数，每次从 p 中取 *T（为空时新分配）、清零后用 CopyInto 拷贝 src，
+// 返回副本的值后把 *T 清零放回 p。副本按值返回，不与池中的对象共享顶层存储；
+// 只有 T 的顶层存储被复用，切片、映射等仍为每次新分配。拷贝出错时与 Copy 一样 panic
+func CopyWithPool[T any](p *sync.Pool) func(T) T {
+	return func(src T) T {
+		result, release := CopyWithPoolReturner(p, src)
+		release()
+		return result
+	}
+}
+
+// CopyWithPoolReturner 从 p 中取 *T 拷贝 src，返回副本以及把 *T 清零并放回 p 的函数。
+// 放回的函数只应调用一次，重复调用不会重复放回
+func CopyWithPoolReturner[T any](p *sync.Pool, src T) (T, func()) {
+	dst := getPooled[T](p)
+	if err := CopyInto(dst, src); err != nil {
+		panic(err)
+	}
+
+	var once sync.Once
+	return *dst, func() {
+		once.Do(func() {
+			var zero T
+			*dst = zero
+			p.Put(dst)
+		})
+	}
+}
+
+// getPooled 从池中取出清零的 *T，池为空或取出的不是 *T 时新分配
+func getPooled[T any](p *sync.Pool) *T {
+	dst, _ := p.Get().(*T)
+	if dst == nil {
+		return new(T)
+	}
+	var zero T
+	*dst = zero
+	return dst
+}
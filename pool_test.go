@@ -0,0 +1,67 @@
+package deepcopy
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// PooledReport 通过对象池拷贝的报表
+type PooledReport struct {
+	Title  string
+	Rows   []int
+	Labels map[string]string
+	Owner  *string
+}
+
+func TestCopyWithPool(t *testing.T) {
+	pool := &sync.Pool{}
+	// 池中放入残留数据的对象，拷贝前必须被清零
+	owner := "stale"
+	pool.Put(&PooledReport{Title: "stale", Rows: []int{9, 9}, Owner: &owner})
+
+	copyReport := CopyWithPool[PooledReport](pool)
+	src := PooledReport{Rows: []int{1, 2}}
+	got := copyReport(src)
+	if want := (PooledReport{Rows: []int{1, 2}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	got.Rows[0] = 100
+	if src.Rows[0] != 1 {
+		t.Error("copy should not share Rows with src")
+	}
+
+	// 连续拷贝之间没有数据残留
+	first := copyReport(PooledReport{Title: "a", Labels: map[string]string{"k": "v"}})
+	second := copyReport(PooledReport{Title: "b"})
+	if second.Labels != nil || second.Title != "b" || first.Labels["k"] != "v" {
+		t.Errorf("first = %+v, second = %+v", first, second)
+	}
+}
+
+func TestCopyWithPoolReturner(t *testing.T) {
+	pool := &sync.Pool{}
+	src := PooledReport{Title: "report", Rows: []int{1}}
+
+	copied, release := CopyWithPoolReturner(pool, src)
+	t.Cleanup(func() {
+		// 放回池中的对象已被清零，不会把本次的数据带给下一次拷贝
+		release()
+		release() // 重复调用不会重复放回
+		if pooled, ok := pool.Get().(*PooledReport); ok && !reflect.DeepEqual(*pooled, PooledReport{}) {
+			t.Errorf("pooled object not cleared: %+v", *pooled)
+		}
+	})
+
+	if !reflect.DeepEqual(copied, src) {
+		t.Errorf("copied = %+v, want %+v", copied, src)
+	}
+
+	// 池中的对象类型不对时新分配
+	pool.Put("not a report")
+	other, releaseOther := CopyWithPoolReturner(pool, PooledReport{Title: "other"})
+	t.Cleanup(releaseOther)
+	if other.Title != "other" {
+		t.Errorf("other = %+v", other)
+	}
+}
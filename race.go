@@ -0,0 +1,6 @@
+//go:build race
+
+package deepcopy
+
+// raceEnabled 是否以 -race 构建
+const raceEnabled = true
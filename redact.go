@@ -0,0 +1,12 @@
+package deepcopy
+
+import "reflect"
+
+// redactOption 标签选项，如 `deepcopy:"redact"`：字段在副本中为零值，用于写日志、审计前清除敏感数据。
+// 包含该字段的结构体无论出现在切片、映射还是指针中都会生效
+const redactOption = "redact"
+
+// isRedacted 字段的标签是否包含 redact 选项
+func (m *DeepCopyManager) isRedacted(field reflect.StructField) bool {
+	return m.hasTagOption(field, redactOption)
+}
@@ -0,0 +1,133 @@
+package deepcopy
+
+import (
+	"strings"
+	"testing"
+)
+
+// AuditCredentials 唯一的引用字段被脱敏，其余字段只包含值
+type AuditCredentials struct {
+	User     string
+	Password string `deepcopy:"redact"`
+	Token    []byte `deepcopy:"redact"`
+}
+
+// AuditRequest 脱敏字段嵌套在切片、指针和映射值中
+type AuditRequest struct {
+	Path    string
+	Headers map[string]string `deepcopy:"redact"`
+	Auth    *AuditCredentials
+	History []AuditCredentials
+	ByHost  map[string]AuditCredentials
+}
+
+func TestCopyRedact(t *testing.T) {
+	creds := AuditCredentials{User: "alice", Password: "secret", Token: []byte("t0k3n")}
+	copied := Copy(creds)
+	if copied.User != "alice" {
+		t.Errorf("User = %q, want alice", copied.User)
+	}
+	if copied.Password != "" || copied.Token != nil {
+		t.Errorf("redacted fields not cleared: %+v", copied)
+	}
+	if creds.Password != "secret" || string(creds.Token) != "t0k3n" {
+		t.Error("source should not be modified")
+	}
+
+	// 除脱敏字段外只包含值类型，仍然整体复制
+	analysis := AnalyzeType(creds)
+	if analysis.IsOnlyValues || !analysis.redactFastPath {
+		t.Errorf("IsOnlyValues = %v, redactFastPath = %v", analysis.IsOnlyValues, analysis.redactFastPath)
+	}
+}
+
+func TestCopyRedactNested(t *testing.T) {
+	req := AuditRequest{
+		Path:    "/login",
+		Headers: map[string]string{"Authorization": "Bearer x"},
+		Auth:    &AuditCredentials{User: "alice", Password: "p1"},
+		History: []AuditCredentials{{User: "bob", Password: "p2", Token: []byte("t")}},
+		ByHost:  map[string]AuditCredentials{"db": {User: "carol", Password: "p3"}},
+	}
+	copied := Copy(req)
+
+	if copied.Path != "/login" || copied.Headers != nil {
+		t.Errorf("Path = %q, Headers = %v", copied.Path, copied.Headers)
+	}
+	if copied.Auth == req.Auth || copied.Auth.User != "alice" || copied.Auth.Password != "" {
+		t.Errorf("Auth = %+v", copied.Auth)
+	}
+	if got := copied.History[0]; got.User != "bob" || got.Password != "" || got.Token != nil {
+		t.Errorf("History[0] = %+v", got)
+	}
+	if got := copied.ByHost["db"]; got.User != "carol" || got.Password != "" {
+		t.Errorf("ByHost[db] = %+v", got)
+	}
+	if req.Auth.Password != "p1" || req.ByHost["db"].Password != "p3" {
+		t.Error("source should not be modified")
+	}
+}
+
+// CopyInto 复用目标时，目标中原有的敏感数据同样被清除
+func TestCopyIntoRedact(t *testing.T) {
+	dst := AuditCredentials{User: "old", Password: "old-secret"}
+	if err := CopyInto(&dst, AuditCredentials{User: "new", Password: "new-secret"}); err != nil {
+		t.Fatal(err)
+	}
+	if dst.User != "new" || dst.Password != "" {
+		t.Errorf("dst = %+v", dst)
+	}
+}
+
+// 使用自定义标签名的管理器
+func TestRedactCustomTagKey(t *testing.T) {
+	type masked struct {
+		Secret string `audit:"redact"`
+		Other  string `deepcopy:"redact"`
+	}
+	m := NewDeepCopyManager(WithTagKey("audit"))
+	copied := m.CopyValue(masked{Secret: "s", Other: "o"}).(masked)
+	if copied.Secret != "" || copied.Other != "o" {
+		t.Errorf("copied = %+v", copied)
+	}
+}
+
+func TestDumpPlanRedact(t *testing.T) {
+	plan := DumpPlan[AuditCredentials]()
+	if !strings.Contains(plan, "Password string (string): zeroed (redact)") {
+		t.Errorf("plan should mark redacted fields:\n%s", plan)
+	}
+}
+
+// RedactedSession 通过 TextMarshaler 往返拷贝（保留未导出的 nonce），脱敏字段仍须清零
+type RedactedSession struct {
+	User  string
+	Pass  string `deepcopy:"redact"`
+	nonce string
+}
+
+func (s RedactedSession) MarshalText() ([]byte, error) {
+	return []byte(s.User + "|" + s.Pass + "|" + s.nonce), nil
+}
+
+func (s *RedactedSession) UnmarshalText(data []byte) error {
+	parts := strings.SplitN(string(data), "|", 3)
+	s.User, s.Pass, s.nonce = parts[0], parts[1], parts[2]
+	return nil
+}
+
+func TestCopyRedactMarshaler(t *testing.T) {
+	session := RedactedSession{User: "u", Pass: "secret", nonce: "n1"}
+	if got := Copy(session); got.User != "u" || got.Pass != "" || got.nonce != "n1" {
+		t.Errorf("got %+v, want Pass redacted and nonce kept", got)
+	}
+
+	type holder struct {
+		Session  RedactedSession
+		Sessions []RedactedSession
+	}
+	got := Copy(holder{Session: session, Sessions: []RedactedSession{session}})
+	if got.Session.Pass != "" || got.Sessions[0].Pass != "" {
+		t.Errorf("nested: got %+v", got)
+	}
+}
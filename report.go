@@ -0,0 +1,77 @@
+package deepcopy
+
+import "reflect"
+
+// Report CopyWithReport 统计的一次拷贝的遍历情况，用于诊断拷贝为什么慢
+type Report struct {
+	Pointers       int // 复制的非 nil 指针数（不含已复制过的）
+	MapEntries     int // 复制的映射条目数
+	SliceElements  int // 复制的切片元素数（整体复制的元素同样计入）
+	CustomCopies   int // 调用注册的拷贝函数（按类型或接口）以及 DeepCopy、DeepCopyWith 方法的次数
+	CyclesHit      int // 再次遇到已复制的指针、切片或映射的次数（循环引用或共享）
+	BytesAllocated int // 估算的分配字节数：新指针指向的值、切片的底层数组、映射的键和值
+	MaxDepth       int // copyRecursive 的最大嵌套深度
+
+	depth int
+}
+
+// CopyWithReport 与 Copy 相同地深拷贝 src，同时返回遍历的统计。为了统计完整的遍历，不走只含值类型时直接返回的快速路径。
+// 统计只在 CopyWithReport 中进行，其他拷贝函数没有额外开销
+func CopyWithReport[T any](src T) (T, Report) {
+	var report Report
+	srcVal := reflect.ValueOf(src)
+	if !srcVal.IsValid() {
+		return src, report
+	}
+	state := newCopyState(&defaultCopyConfig)
+	state.report = &report
+	return copyToT[T](srcVal, state), report
+}
+
+// 以下方法的接收者为 nil（未在统计）时什么也不做
+
+func (r *Report) enter() {
+	if r != nil {
+		r.depth++
+		r.MaxDepth = max(r.MaxDepth, r.depth)
+	}
+}
+
+func (r *Report) leave() {
+	if r != nil {
+		r.depth--
+	}
+}
+
+func (r *Report) pointer(elem reflect.Type) {
+	if r != nil {
+		r.Pointers++
+		r.BytesAllocated += int(elem.Size())
+	}
+}
+
+func (r *Report) slice(t reflect.Type, length, capacity int) {
+	if r != nil {
+		r.SliceElements += length
+		r.BytesAllocated += capacity * int(t.Elem().Size())
+	}
+}
+
+func (r *Report) mapEntry(t reflect.Type) {
+	if r != nil {
+		r.MapEntries++
+		r.BytesAllocated += int(t.Key().Size() + t.Elem().Size())
+	}
+}
+
+func (r *Report) customCopy() {
+	if r != nil {
+		r.CustomCopies++
+	}
+}
+
+func (r *Report) cycleHit() {
+	if r != nil {
+		r.CyclesHit++
+	}
+}
@@ -0,0 +1,48 @@
+package deepcopy
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// ReportStamp 值接收者实现 DeepCopy 的字段
+type ReportStamp struct{ At int }
+
+func (s ReportStamp) DeepCopy() ReportStamp { return s }
+
+type ReportNode struct {
+	Name  string
+	Tags  []string
+	Attrs map[string]int
+	Stamp ReportStamp
+	Next  *ReportNode
+}
+
+func TestCopyWithReport(t *testing.T) {
+	root := &ReportNode{Name: "root", Tags: []string{"a", "b"}, Attrs: map[string]int{"x": 1, "y": 2}}
+	root.Next = &ReportNode{Name: "leaf", Next: root}
+
+	copied, report := CopyWithReport(root)
+	if copied == root || copied.Next.Next != copied || copied.Tags[1] != "b" || copied.Attrs["y"] != 2 {
+		t.Fatalf("bad copy: %+v", copied)
+	}
+
+	nodeSize := int(unsafe.Sizeof(ReportNode{}))
+	entrySize := int(unsafe.Sizeof("") + unsafe.Sizeof(0))
+	want := Report{
+		Pointers:       2,
+		MapEntries:     2,
+		SliceElements:  2,
+		CustomCopies:   2, // 两个节点的 Stamp
+		CyclesHit:      1, // leaf.Next 指回 root
+		BytesAllocated: 2*nodeSize + 2*int(unsafe.Sizeof("")) + 2*entrySize,
+		MaxDepth:       5, // root、*root、root.Next、*root.Next、leaf.Next
+	}
+	if report != want {
+		t.Errorf("report =\n%+v\nwant\n%+v", report, want)
+	}
+
+	if _, report := CopyWithReport[*ReportNode](nil); report != (Report{MaxDepth: 1}) {
+		t.Errorf("nil pointer: %+v", report)
+	}
+}
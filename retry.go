@@ -0,0 +1,81 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// 默认的重试退避时间
+const (
+	defaultRetryBase = time.Millisecond
+	defaultRetryMax  = 100 * time.Millisecond
+)
+
+// CopyRecoverable 与 CopyE 相同，CopyE 只恢复拷贝（包括 DeepCopy 方法）中的 panic，
+// CopyRecoverable 还会恢复类型分析、WithLocker 的锁等其余位置的 panic，统一返回 *PanicError
+func CopyRecoverable[T any](src T, opts ...Option) (result T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			result, err = zero, &PanicError{Value: r}
+		}
+	}()
+	return CopyE(src, opts...)
+}
+
+// WithRetryBackoff 设置 CopyWithRetry 的退避时间：第 n 次重试前等待 base*2^(n-1)（不超过 max）并加入随机抖动
+func WithRetryBackoff(base, max time.Duration) Option {
+	return func(c *copyConfig) {
+		c.retryBase = base
+		c.retryMax = max
+	}
+}
+
+// CopyWithRetry 拷贝过程中发生 panic 时（例如源值在拷贝期间被并发修改），
+// 等待一段带抖动的退避时间后重试，最多尝试 maxAttempts 次，仍失败时返回零值和错误。
+//
+// 注意：这只是在无法补充同步的遗留代码中的尽力而为的手段，不能替代正确的同步，
+// 并发修改也可能产生不一致但不 panic 的副本；
+// 并且 Go 运行时检测到的 map 并发读写是无法恢复的致命错误，不会被重试。
+// 非 panic 的错误（例如 UnsafePointerError 策略）直接返回，不会重试。
+func CopyWithRetry[T any](src T, maxAttempts int, opts ...Option) (T, error) {
+	cfg := newCopyConfig(opts)
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, err := CopyRecoverable(src, opts...)
+		var panicErr *PanicError
+		if err == nil || !errors.As(err, &panicErr) {
+			return result, err
+		}
+		if attempt >= maxAttempts {
+			var zero T
+			return zero, fmt.Errorf("deepcopy: copy failed after %d attempts: %w", attempt, err)
+		}
+		time.Sleep(cfg.retryDelay(attempt))
+	}
+}
+
+// retryDelay 第 attempt 次失败后的等待时间，在 [d/2, d] 之间随机取值
+func (c *copyConfig) retryDelay(attempt int) time.Duration {
+	base, max := c.retryBase, c.retryMax
+	if base <= 0 {
+		base = defaultRetryBase
+	}
+	if max < base {
+		max = base
+	}
+
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
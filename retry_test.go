@@ -0,0 +1,137 @@
+package deepcopy
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tornPair 写入方先更新 A 再更新 B，两次更新之间的状态不一致；拷贝读到不一致的状态时 panic，
+// 模拟拷贝期间源值被并发修改
+type tornPair struct {
+	A, B   *atomic.Int64
+	copies *atomic.Int32 // DeepCopy 的调用次数
+}
+
+func (p tornPair) DeepCopy() tornPair {
+	p.copies.Add(1)
+	a, b := p.A.Load(), p.B.Load()
+	if a != b {
+		panic("source modified during copy")
+	}
+	cp := tornPair{A: &atomic.Int64{}, B: &atomic.Int64{}, copies: p.copies}
+	cp.A.Store(a)
+	cp.B.Store(b)
+	return cp
+}
+
+func newTornPair() tornPair {
+	return tornPair{A: &atomic.Int64{}, B: &atomic.Int64{}, copies: &atomic.Int32{}}
+}
+
+// startWrite 在另一个 goroutine 中开始一次写入，A 更新后等待 release 关闭再更新 B，返回写入完成的通道
+func (p tornPair) startWrite(release <-chan struct{}) <-chan struct{} {
+	started, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		p.A.Add(1)
+		close(started)
+		<-release
+		p.B.Add(1)
+	}()
+	<-started
+	return done
+}
+
+func TestCopyRecoverable(t *testing.T) {
+	src := newTornPair()
+	release := make(chan struct{})
+	done := src.startWrite(release)
+
+	_, err := CopyRecoverable(src)
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+	if panicErr.Value != "source modified during copy" {
+		t.Errorf("unexpected panic value %v", panicErr.Value)
+	}
+
+	close(release)
+	<-done
+	copied, err := CopyRecoverable(src)
+	if err != nil || copied.A.Load() != 1 || copied.B.Load() != 1 {
+		t.Errorf("unexpected result %v, %v", copied, err)
+	}
+}
+
+func TestCopyWithRetry(t *testing.T) {
+	backoff := WithRetryBackoff(100*time.Microsecond, time.Millisecond)
+
+	t.Run("succeeds after retries", func(t *testing.T) {
+		src := newTornPair()
+		release := make(chan struct{})
+		done := src.startWrite(release)
+		// 写入方在两次拷贝失败后完成写入
+		go func() {
+			for src.copies.Load() < 2 {
+				runtime.Gosched()
+			}
+			close(release)
+		}()
+
+		copied, err := CopyWithRetry(src, 100, backoff)
+		<-done
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if copied.A.Load() != 1 || copied.B.Load() != 1 || copied.A == src.A {
+			t.Errorf("expected an independent consistent copy, got A=%d B=%d", copied.A.Load(), copied.B.Load())
+		}
+		if n := src.copies.Load(); n < 3 {
+			t.Errorf("expected at least 3 attempts, got %d", n)
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		src := newTornPair()
+		release := make(chan struct{})
+		done := src.startWrite(release)
+		defer func() {
+			close(release)
+			<-done
+		}()
+
+		copied, err := CopyWithRetry(src, 3, backoff)
+		var panicErr *PanicError
+		if !errors.As(err, &panicErr) {
+			t.Fatalf("expected *PanicError, got %v", err)
+		}
+		if copied.A != nil {
+			t.Errorf("expected zero value, got %v", copied)
+		}
+		if n := src.copies.Load(); n != 3 {
+			t.Errorf("expected 3 attempts, got %d", n)
+		}
+	})
+
+	t.Run("non-panic errors are not retried", func(t *testing.T) {
+		_, err := CopyWithRetry(UnsafeHolder{}, 3, backoff, WithUnsafePointerPolicy(UnsafePointerError))
+		if !errors.Is(err, ErrUnsafePointer) {
+			t.Errorf("expected ErrUnsafePointer, got %v", err)
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	cfg := newCopyConfig([]Option{WithRetryBackoff(10*time.Millisecond, 40*time.Millisecond)})
+	for attempt, want := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 5: 40 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			if d := cfg.retryDelay(attempt); d < want/2 || d > want {
+				t.Errorf("attempt %d: delay %v out of [%v, %v]", attempt, d, want/2, want)
+			}
+		}
+	}
+}
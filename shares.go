@@ -0,0 +1,152 @@
+package deepcopy
+
+import (
+	"reflect"
+	"sort"
+)
+
+// SharesPointersWith 检查 copied 是否与 original 共享内存，用于测试拷贝结果：
+// 先收集 original 中指针、切片、映射引用的内存，再遍历 copied，
+// 发现引用落在其中时返回 true 和该引用在 copied 中的位置，如 MapB["hi"].Vals，根值本身为空。
+// 与 Copy 一致，只遍历导出字段，不展开 time.Time 等不可变值类型以及 reflect.Type
+func SharesPointersWith[T any](original, copied T) (bool, string) {
+	var owned memRanges
+	collect := newPointerWalker(func(r memRange) bool {
+		owned.add(r)
+		return false
+	})
+	collect.walk(reflect.ValueOf(&original).Elem())
+	owned.merge()
+
+	var path string
+	check := newPointerWalker(nil)
+	check.found = func(r memRange) bool {
+		if owned.overlaps(r) {
+			path = formatPath(check.path)
+			return true
+		}
+		return false
+	}
+	check.walk(reflect.ValueOf(&copied).Elem())
+	return check.stopped, path
+}
+
+// memRange 一段内存 [start, end)
+type memRange struct {
+	start, end uintptr
+}
+
+// memRanges 内存范围的集合，merge 之后按起始地址排序且互不重叠
+type memRanges []memRange
+
+func (rs *memRanges) add(r memRange) {
+	*rs = append(*rs, r)
+}
+
+// merge 排序并合并重叠的范围
+func (rs *memRanges) merge() {
+	sort.Slice(*rs, func(i, j int) bool { return (*rs)[i].start < (*rs)[j].start })
+	merged := (*rs)[:0]
+	for _, r := range *rs {
+		if n := len(merged); n > 0 && r.start < merged[n-1].end {
+			merged[n-1].end = max(merged[n-1].end, r.end)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	*rs = merged
+}
+
+// overlaps 判断 r 是否与集合中的某个范围重叠，须在 merge 之后调用
+func (rs memRanges) overlaps(r memRange) bool {
+	i := sort.Search(len(rs), func(i int) bool { return rs[i].end > r.start })
+	return i < len(rs) && rs[i].start < r.end
+}
+
+// pointerWalker 遍历值中的引用，对每个引用的内存范围调用 found
+type pointerWalker struct {
+	found   func(r memRange) bool // 返回 true 时停止遍历
+	visited map[refKey]bool
+	path    []pathSegment
+	stopped bool
+}
+
+func newPointerWalker(found func(r memRange) bool) *pointerWalker {
+	return &pointerWalker{found: found, visited: make(map[refKey]bool)}
+}
+
+// ref 报告一个引用，已遍历过或需要停止时返回 false
+func (w *pointerWalker) ref(v reflect.Value, size uintptr, length int) bool {
+	key := refKey{ptr: v.Pointer(), typ: v.Type(), len: length}
+	if w.visited[key] {
+		return false
+	}
+	w.visited[key] = true
+	// 零大小的值可能共用同一地址，不视为共享
+	if size > 0 && w.found(memRange{start: key.ptr, end: key.ptr + size}) {
+		w.stopped = true
+	}
+	return !w.stopped
+}
+
+func (w *pointerWalker) walk(v reflect.Value) {
+	if w.stopped {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() && w.ref(v, v.Type().Elem().Size(), 0) {
+			w.walk(v.Elem())
+		}
+
+	case reflect.Interface:
+		if !v.IsNil() && !v.Elem().Type().Implements(reflectTypeType) {
+			w.walk(v.Elem())
+		}
+
+	case reflect.Slice:
+		if v.Cap() == 0 || !w.ref(v, uintptr(v.Cap())*v.Type().Elem().Size(), v.Len()) {
+			return
+		}
+		w.walkElems(v)
+
+	case reflect.Array:
+		w.walkElems(v)
+
+	case reflect.Map:
+		// 映射的存储不可寻址，只比较映射本身
+		if v.IsNil() || !w.ref(v, 1, 0) {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() && !w.stopped {
+			w.path = append(w.path, pathSegment{key: iter.Key()})
+			w.walk(iter.Key())
+			w.walk(iter.Value())
+			w.path = w.path[:len(w.path)-1]
+		}
+
+	case reflect.Struct:
+		if immutableValueTypes[v.Type()] {
+			return
+		}
+		for i := 0; i < v.NumField() && !w.stopped; i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			w.path = append(w.path, pathSegment{structType: v.Type(), index: i})
+			w.walk(v.Field(i))
+			w.path = w.path[:len(w.path)-1]
+		}
+	}
+}
+
+// walkElems 遍历切片或数组的元素
+func (w *pointerWalker) walkElems(v reflect.Value) {
+	for i := 0; i < v.Len() && !w.stopped; i++ {
+		w.path = append(w.path, pathSegment{index: i})
+		w.walk(v.Index(i))
+		w.path = w.path[:len(w.path)-1]
+	}
+}
@@ -0,0 +1,80 @@
+package deepcopy
+
+import (
+	"testing"
+	"time"
+)
+
+// SharedInner / SharedOuter 用于检查共享内存的嵌套结构
+type SharedInner struct {
+	Vals []int
+}
+
+type SharedOuter struct {
+	Name    string
+	MapB    map[string]*SharedInner
+	Items   []SharedInner
+	Ptr     *int
+	Any     any
+	Created time.Time
+	Self    *SharedOuter
+}
+
+func newSharedOuter() *SharedOuter {
+	n := 1
+	o := &SharedOuter{
+		Name:    "outer",
+		MapB:    map[string]*SharedInner{"hi": {Vals: []int{1, 2}}},
+		Items:   []SharedInner{{Vals: []int{3}}},
+		Ptr:     &n,
+		Any:     &SharedInner{Vals: []int{4}},
+		Created: time.Now(),
+	}
+	o.Self = o
+	return o
+}
+
+func TestSharesPointersWith(t *testing.T) {
+	original := newSharedOuter()
+	if shared, path := SharesPointersWith(original, Copy(original)); shared {
+		t.Errorf("deep copy should not share memory, found at %s", path)
+	}
+	if shared, path := SharesPointersWith(original, original); !shared || path != "" {
+		t.Errorf("same pointer: got (%v, %q)", shared, path)
+	}
+
+	tests := []struct {
+		name  string
+		share func(o, c *SharedOuter)
+		want  string
+	}{
+		{"map value", func(o, c *SharedOuter) { c.MapB["hi"].Vals = o.MapB["hi"].Vals }, `MapB["hi"].Vals`},
+		{"map", func(o, c *SharedOuter) { c.MapB = o.MapB }, "MapB"},
+		{"subslice", func(o, c *SharedOuter) { c.MapB["hi"].Vals = o.MapB["hi"].Vals[1:] }, `MapB["hi"].Vals`},
+		{"element pointer", func(o, c *SharedOuter) { c.Ptr = &o.Items[0].Vals[0] }, "Ptr"},
+		{"interface", func(o, c *SharedOuter) { c.Any.(*SharedInner).Vals = o.Any.(*SharedInner).Vals }, "Any.Vals"},
+	}
+	for _, tt := range tests {
+		o := newSharedOuter()
+		c := Copy(o)
+		tt.share(o, c)
+		shared, path := SharesPointersWith(o, c)
+		if !shared || path != tt.want {
+			t.Errorf("%s: got (%v, %q), want (true, %q)", tt.name, shared, path, tt.want)
+		}
+	}
+}
+
+// 零大小的值和不可变值类型不视为共享
+func TestSharesPointersWithIgnored(t *testing.T) {
+	type empty struct{}
+	type holder struct {
+		E  *empty
+		At time.Time
+	}
+	original := holder{E: &empty{}, At: time.Now()}
+	copied := holder{E: &empty{}, At: original.At}
+	if shared, path := SharesPointersWith(original, copied); shared {
+		t.Errorf("unexpected sharing at %s", path)
+	}
+}
@@ -0,0 +1,68 @@
+package deepcopy
+
+import (
+	"reflect"
+	"strings"
+)
+
+// CacheStats 类型分析缓存的统计信息，由 ReadCacheStats 或 DeepCopyManager.CacheStats 返回
+type CacheStats struct {
+	AnalyzedTypes  int            // 缓存了分析结果的类型数（包括嵌套的字段、元素类型）
+	TypedManagers  int            // Copy 等泛型函数缓存的类型数，只有 ReadCacheStats 统计
+	Instantiations map[string]int // 泛型类型（如 example.com/pkg.Box）在 AnalyzedTypes 中的实例化个数
+	Evictions      uint64         // 设置了 WithCacheSize 时因超出容量淘汰的条目数
+}
+
+// ReadCacheStats 统计默认管理器和泛型函数的缓存，用于观察大量实例化的泛型类型（每个实例化都是不同的类型，
+// 各自缓存一份分析结果）占用的条目。遍历全部缓存，不应在热路径上调用
+func ReadCacheStats() CacheStats {
+	stats := defaultManager.CacheStats()
+	typedManagers.Range(func(_, _ any) bool {
+		stats.TypedManagers++
+		return true
+	})
+	return stats
+}
+
+// CacheStats 统计管理器的类型分析缓存，遍历全部缓存，不应在热路径上调用
+func (m *DeepCopyManager) CacheStats() CacheStats {
+	stats := CacheStats{Instantiations: make(map[string]int)}
+	m.rangeAnalyzed(func(t reflect.Type) {
+		stats.AnalyzedTypes++
+		if name, ok := genericName(t); ok {
+			stats.Instantiations[name]++
+		}
+	})
+	if m.lru != nil {
+		stats.Evictions = m.lru.evictions.Load()
+	}
+	return stats
+}
+
+// rangeAnalyzed 遍历缓存了分析结果的类型
+func (m *DeepCopyManager) rangeAnalyzed(fn func(t reflect.Type)) {
+	if m.lru != nil {
+		m.lru.mu.Lock()
+		defer m.lru.mu.Unlock()
+		for t := range m.lru.entries {
+			fn(t)
+		}
+		return
+	}
+	m.analysisCache.Range(func(key, _ any) bool {
+		fn(key.(reflect.Type))
+		return true
+	})
+}
+
+// genericName 泛型类型实例化的类型名（包路径加不含类型参数的名称），t 不是泛型类型的实例化时返回 false
+func genericName(t reflect.Type) (string, bool) {
+	name, _, ok := strings.Cut(t.Name(), "[")
+	if !ok {
+		return "", false
+	}
+	if t.PkgPath() == "" {
+		return name, true
+	}
+	return t.PkgPath() + "." + name, true
+}
@@ -0,0 +1,59 @@
+package deepcopy
+
+import (
+	"reflect"
+	"testing"
+)
+
+// StatsBox / StatsPage 被多次实例化的泛型类型
+type StatsBox[T any] struct {
+	Value T
+}
+
+type StatsPage[T any] struct {
+	Items []T
+}
+
+func TestGenericInstantiationCaching(t *testing.T) {
+	n := 7
+	ints := StatsBox[int]{Value: 1}
+	slices := StatsBox[[]int]{Value: []int{1}}
+	ptrs := StatsBox[*int]{Value: &n}
+	page := StatsPage[StatsBox[[]int]]{Items: []StatsBox[[]int]{slices}}
+
+	// 每个实例化各自分析：StatsBox[int] 可直接返回，其余需要深拷贝
+	if !AnalyzeType(ints).IsOnlyValues || AnalyzeType(slices).IsOnlyValues || AnalyzeType(ptrs).IsOnlyValues {
+		t.Fatal("instantiations should be analyzed independently")
+	}
+	if Copy(ints) != ints {
+		t.Error("StatsBox[int] copy differs")
+	}
+	if c := Copy(slices); &c.Value[0] == &slices.Value[0] {
+		t.Error("StatsBox[[]int] should not share after StatsBox[int] was cached")
+	}
+	if c := Copy(ptrs); c.Value == ptrs.Value || *c.Value != 7 {
+		t.Error("StatsBox[*int] should copy the pointee")
+	}
+	if c := Copy(page); &c.Items[0].Value[0] == &slices.Value[0] {
+		t.Error("nested instantiation should be deep copied")
+	}
+
+	// typedManagers 以 any 存放，按实例化分别缓存，类型断言不会串用
+	if any(getTypedManager[StatsBox[int]]()) == any(getTypedManager[StatsBox[[]int]]()) {
+		t.Error("typed managers should be per instantiation")
+	}
+	if getTypedManager[StatsBox[int]]().rtype != reflect.TypeOf(ints) {
+		t.Error("typed manager has the wrong type")
+	}
+
+	stats := ReadCacheStats()
+	if got := stats.Instantiations["github.com/wsqun/deepcopy.StatsBox"]; got != 3 {
+		t.Errorf("StatsBox instantiations = %d, want 3", got)
+	}
+	if got := stats.Instantiations["github.com/wsqun/deepcopy.StatsPage"]; got != 1 {
+		t.Errorf("StatsPage instantiations = %d, want 1", got)
+	}
+	if stats.AnalyzedTypes < 4 || stats.TypedManagers < 4 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,57 @@
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// IncompleteCopyError 严格模式下副本与源值不完全等价时返回，列出每个问题的位置和原因
+type IncompleteCopyError struct {
+	Issues []string // 形如 "Inner.secret: unexported field dropped"
+}
+
+func (e *IncompleteCopyError) Error() string {
+	return "deepcopy: copy is not equivalent to the source: " + strings.Join(e.Issues, "; ")
+}
+
+// WithStrict 严格模式：遍历中丢弃了非零的未导出字段、共享了通道或函数、
+// 共享或置零了 unsafe.Pointer、遇到接口中只有未导出字段的非零结构体（默认被共享）时，CopyE 返回 *IncompleteCopyError
+func WithStrict() Option {
+	return func(c *copyConfig) {
+		c.strict = true
+	}
+}
+
+// MustCopy 与 Copy 相同，但副本不能证明与源值等价时 panic（信息中包含类型名和字段路径），
+// 用于不能接受丢失私有状态的快照场景
+func MustCopy[T any](src T) T {
+	result, err := CopyE(src, WithStrict())
+	if err != nil {
+		panic(fmt.Sprintf("deepcopy: MustCopy(%s): %v", reflect.TypeOf((*T)(nil)).Elem(), err))
+	}
+	return result
+}
+
+// recordIssue 严格模式下记录当前位置的问题
+func (s *copyState) recordIssue(reason string) {
+	path := s.pathString()
+	if path == "" {
+		path = "(root)"
+	}
+	s.issues = append(s.issues, path+": "+reason)
+}
+
+// checkDroppedFields 严格模式下记录结构体中被跳过的非零未导出字段
+func (s *copyState) checkDroppedFields(original reflect.Value) {
+	t := original.Type()
+	for i := 0; i < t.NumField(); i++ {
+		// 带 lock 标签的锁正被拷贝持有，不算丢失的数据
+		if t.Field(i).PkgPath == "" || original.Field(i).IsZero() || s.manager.isLockField(t.Field(i)) {
+			continue
+		}
+		s.pushField(t, i)
+		s.recordIssue("unexported field dropped")
+		s.popPath()
+	}
+}
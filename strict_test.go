@@ -0,0 +1,107 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// 含私有状态和通道的快照
+type strictInner struct {
+	Name   string
+	secret string
+}
+
+type strictSnapshot struct {
+	Inner  strictInner
+	Events chan int
+	Hook   func()
+	Items  []string
+}
+
+func TestWithStrict(t *testing.T) {
+	original := strictSnapshot{
+		Inner:  strictInner{Name: "n", secret: "s"},
+		Events: make(chan int),
+		Items:  []string{"a"},
+	}
+
+	_, err := CopyE(original, WithStrict())
+	var incomplete *IncompleteCopyError
+	if !errors.As(err, &incomplete) {
+		t.Fatalf("expected *IncompleteCopyError, got %v", err)
+	}
+	want := []string{"Inner.secret: unexported field dropped", "Events: chan shared"}
+	if fmt.Sprint(incomplete.Issues) != fmt.Sprint(want) {
+		t.Errorf("Issues = %q, want %q", incomplete.Issues, want)
+	}
+
+	// 零值的未导出字段和 nil 通道不影响等价性
+	clean := strictSnapshot{Inner: strictInner{Name: "n"}, Items: []string{"a"}}
+	if _, err := CopyE(clean, WithStrict()); err != nil {
+		t.Errorf("unexpected error for equivalent copy: %v", err)
+	}
+	// 非严格模式下不报错
+	if _, err := CopyE(original); err != nil {
+		t.Errorf("unexpected error without WithStrict: %v", err)
+	}
+}
+
+func TestMustCopy(t *testing.T) {
+	clean := strictSnapshot{Inner: strictInner{Name: "n"}, Items: []string{"a"}}
+	if copied := MustCopy(clean); copied.Inner.Name != "n" || &copied.Items[0] == &clean.Items[0] {
+		t.Errorf("unexpected copy %+v", copied)
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("MustCopy should panic on a partial copy")
+		}
+		msg := fmt.Sprint(r)
+		if !strings.Contains(msg, "strictSnapshot") || !strings.Contains(msg, "Hook: func shared") {
+			t.Errorf("panic should mention type and path: %s", msg)
+		}
+	}()
+	MustCopy(strictSnapshot{Hook: func() {}})
+}
+
+// OpaqueMoney 只有未导出字段，模拟其他包中的不可变值类型
+type OpaqueMoney struct {
+	amount   int64
+	currency string
+}
+
+type OpaqueHolder struct {
+	Price any
+	Items []any
+}
+
+func TestOpaqueInterfaceValue(t *testing.T) {
+	price := OpaqueMoney{amount: 1999, currency: "EUR"}
+	original := OpaqueHolder{Price: price, Items: []any{price, OpaqueMoney{}}}
+
+	// 默认经由接口共享，不再得到零值
+	copied, err := CopyE(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.Price != price || copied.Items[0] != price || copied.Items[1] != (OpaqueMoney{}) {
+		t.Errorf("got %+v, want the opaque values shared", copied)
+	}
+
+	// 严格模式下非零的值报错，零值仍然等价
+	_, err = CopyE(original, WithStrict())
+	var incomplete *IncompleteCopyError
+	if !errors.As(err, &incomplete) {
+		t.Fatalf("expected *IncompleteCopyError, got %v", err)
+	}
+	want := []string{
+		"Price: deepcopy.OpaqueMoney has only unexported fields and no DeepCopy method",
+		"Items[0]: deepcopy.OpaqueMoney has only unexported fields and no DeepCopy method",
+	}
+	if fmt.Sprint(incomplete.Issues) != fmt.Sprint(want) {
+		t.Errorf("Issues = %q, want %q", incomplete.Issues, want)
+	}
+}
@@ -0,0 +1,214 @@
+// Package testutil 提供验证深拷贝结果的测试工具，只应在测试中使用
+package testutil
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// DeepEqualAfterCopy 验证 copied 是 original 的独立副本：先用 reflect.DeepEqual 确认两者相等，
+// 再逐个修改 copied 中可到达的基础值（整数加 1、布尔值取反、字符串追加字符等），
+// 每次修改后检查 original 没有随之改变，随后把 copied 恢复原状。
+// 传入 tb 时，对不相等或共享内存的位置（如 Items[0].Name）调用 tb.Errorf。
+// 只能修改导出字段，未导出字段中的共享不会被发现
+func DeepEqualAfterCopy[T any](original, copied T, tb ...testing.TB) bool {
+	report := func(format string, args ...any) {
+		for _, t := range tb {
+			t.Helper()
+			t.Errorf(format, args...)
+		}
+	}
+
+	if !reflect.DeepEqual(original, copied) {
+		report("testutil: copy is not equal to the original")
+		return false
+	}
+
+	c := &checker{
+		equal:   func() bool { return reflect.DeepEqual(original, copied) },
+		visited: make(map[visitKey]bool),
+	}
+	c.walk(reflect.ValueOf(&copied).Elem(), "", false)
+	if c.leak != "" {
+		report("testutil: copy shares memory with the original at %s", c.leak)
+		return false
+	}
+	return true
+}
+
+// visitKey 已遍历的引用，类型不同的同一地址（如结构体与其第一个字段）分别遍历
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// checker 单次检查的状态
+type checker struct {
+	equal   func() bool // original 与 copied 是否仍然相等
+	visited map[visitKey]bool
+	leak    string // 第一个发现共享的位置
+}
+
+// walk 遍历 v 中可修改的基础值。detached 表示 v 是接口或映射中的值复制出来的临时值，
+// 其中直接包含的基础值不属于 copied，只有经由指针、切片、映射到达的部分才需要修改
+func (c *checker) walk(v reflect.Value, path string, detached bool) {
+	if c.leak != "" {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || c.seen(v) {
+			return
+		}
+		c.walk(v.Elem(), path, false)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		// 接口中的值不可寻址，指针、切片、映射等引用类型仍可经由副本修改
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		c.walk(elem, path, true)
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				c.walk(v.Field(i), joinField(path, v.Type().Field(i).Name), detached)
+			}
+		}
+
+	case reflect.Slice:
+		if v.Len() == 0 || c.seen(v) {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			c.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), false)
+		}
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), detached)
+		}
+
+	case reflect.Map:
+		if v.Len() == 0 || c.seen(v) {
+			return
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			c.walkMapValue(v, iter.Key(), iter.Value(), path+keySegment(iter.Key()))
+		}
+
+	default:
+		if v.CanSet() && !detached {
+			c.mutate(v, path)
+		}
+	}
+}
+
+// walkMapValue 映射的值不可寻址：基础值通过 SetMapIndex 修改后恢复，其余值复制出来继续遍历其中的引用
+func (c *checker) walkMapValue(m, key, value reflect.Value, path string) {
+	elem := reflect.New(value.Type()).Elem()
+	elem.Set(value)
+	if !isScalar(value.Kind()) {
+		c.walk(elem, path, true)
+		return
+	}
+	if !mutateScalar(elem) {
+		return
+	}
+	m.SetMapIndex(key, elem)
+	if c.equal() {
+		c.leak = orRoot(path)
+	}
+	m.SetMapIndex(key, value)
+}
+
+// mutate 修改一个基础值，检查 original 后恢复
+func (c *checker) mutate(v reflect.Value, path string) {
+	old := reflect.New(v.Type()).Elem()
+	old.Set(v)
+	if !mutateScalar(v) {
+		return
+	}
+	if c.equal() {
+		c.leak = orRoot(path)
+	}
+	v.Set(old)
+}
+
+// seen 记录引用，已遍历过时返回 true
+func (c *checker) seen(v reflect.Value) bool {
+	key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+	if c.visited[key] {
+		return true
+	}
+	c.visited[key] = true
+	return false
+}
+
+// mutateScalar 把基础值改为一个不同的值，无法修改的种类返回 false
+func mutateScalar(v reflect.Value) bool {
+	switch {
+	case v.CanInt():
+		v.SetInt(v.Int() + 1)
+	case v.CanUint():
+		v.SetUint(v.Uint() + 1)
+	case v.CanFloat():
+		f := v.Float()
+		if f+1 == f { // 无穷大或精度不足以表示加 1
+			f = 0
+		} else {
+			f++
+		}
+		v.SetFloat(f)
+	case v.CanComplex():
+		v.SetComplex(v.Complex() + 1)
+	case v.Kind() == reflect.Bool:
+		v.SetBool(!v.Bool())
+	case v.Kind() == reflect.String:
+		v.SetString(v.String() + "~")
+	default:
+		return false
+	}
+	return true
+}
+
+// isScalar 是否为可以直接修改的基础值种类
+func isScalar(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// joinField 路径中追加字段名
+func joinField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// keySegment 映射键在路径中的形式，与 deepcopy 的错误路径一致
+func keySegment(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return fmt.Sprintf("[%q]", key.String())
+	}
+	return fmt.Sprintf("[%v]", key)
+}
+
+// orRoot 根值本身的路径显示为 (root)
+func orRoot(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
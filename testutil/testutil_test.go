@@ -0,0 +1,92 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/wsqun/deepcopy"
+)
+
+type item struct {
+	Name  string
+	Count int
+}
+
+type order struct {
+	ID     int
+	Items  []item
+	Labels map[string]string
+	Owner  *item
+	Meta   any
+	Nested struct{ Flags []bool }
+}
+
+func newOrder() order {
+	o := order{
+		ID:     1,
+		Items:  []item{{Name: "a", Count: 1}},
+		Labels: map[string]string{"env": "prod"},
+		Owner:  &item{Name: "owner"},
+		Meta:   &item{Name: "meta"},
+	}
+	o.Nested.Flags = []bool{true}
+	return o
+}
+
+// recorder 记录 Errorf 的消息
+type recorder struct {
+	testing.TB
+	msgs []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+func TestDeepEqualAfterCopy(t *testing.T) {
+	o := newOrder()
+	DeepEqualAfterCopy(o, deepcopy.Copy(o), t)
+
+	tests := []struct {
+		name    string
+		share   func(o, c *order)
+		wantMsg string
+	}{
+		{"slice", func(o, c *order) { c.Items = o.Items }, "Items[0].Name"},
+		{"map", func(o, c *order) { c.Labels = o.Labels }, `Labels["env"]`},
+		{"pointer", func(o, c *order) { c.Owner = o.Owner }, "Owner.Name"},
+		{"interface", func(o, c *order) { c.Meta = o.Meta }, "Meta.Name"},
+		{"nested", func(o, c *order) { c.Nested.Flags = o.Nested.Flags }, "Nested.Flags[0]"},
+	}
+	for _, tt := range tests {
+		o := newOrder()
+		c := deepcopy.Copy(o)
+		tt.share(&o, &c)
+
+		r := &recorder{}
+		if DeepEqualAfterCopy(o, c, r) {
+			t.Errorf("%s: shared memory not detected", tt.name)
+			continue
+		}
+		want := "testutil: copy shares memory with the original at " + tt.wantMsg
+		if len(r.msgs) != 1 || r.msgs[0] != want {
+			t.Errorf("%s: messages = %q, want %q", tt.name, r.msgs, want)
+		}
+		// 检查后副本恢复原状
+		if !DeepEqualAfterCopy(newOrder(), c) {
+			t.Errorf("%s: copy was not restored", tt.name)
+		}
+	}
+}
+
+func TestDeepEqualAfterCopyNotEqual(t *testing.T) {
+	r := &recorder{}
+	if DeepEqualAfterCopy(item{Name: "a"}, item{Name: "b"}, r) {
+		t.Error("unequal values should fail")
+	}
+	if len(r.msgs) != 1 || r.msgs[0] != "testutil: copy is not equal to the original" {
+		t.Errorf("messages = %q", r.msgs)
+	}
+}
@@ -0,0 +1,330 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrCyclicValue ToMap、FromMap 遇到循环引用时返回，嵌套的映射无法表示环
+var ErrCyclicValue = errors.New("deepcopy: value contains a cycle")
+
+// WithMapTag ToMap、FromMap 以标签 key 的值作为字段在映射中的键（如 `json:"user_id"`），
+// 取逗号前的部分，"-" 表示忽略该字段，没有该标签或值为空时使用字段名
+func WithMapTag(key string) Option {
+	return func(c *copyConfig) {
+		c.mapTag = key
+	}
+}
+
+// ToMap 把结构体（或结构体指针）转换为嵌套的 map[string]any：结构体和结构体指针转为 map[string]any，
+// 切片和数组转为 []any，以字符串为键的映射转为 map[string]any，其余值按 Copy 深拷贝，结果不与 src 共享内存。
+// 与 Copy 一致，只包含导出字段，跳过脱敏字段；time.Time、实现了 DeepCopy 或序列化接口的结构体保持原类型。
+// src 为 nil 指针时返回 nil，src 不是结构体或包含循环引用时 panic
+func ToMap(src any, opts ...Option) map[string]any {
+	srcVal := reflect.ValueOf(src)
+	for srcVal.Kind() == reflect.Ptr {
+		if srcVal.IsNil() {
+			return nil
+		}
+		srcVal = srcVal.Elem()
+	}
+
+	c := newMapConverter(opts)
+	if srcVal.Kind() != reflect.Struct || c.isLeaf(srcVal.Type()) {
+		panic(fmt.Sprintf("deepcopy: ToMap source must be a struct, got %T", src))
+	}
+
+	var result map[string]any
+	if err := c.state.run(func() { result = c.structToMap(srcVal) }); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// FromMap 把 ToMap 形式的映射还原为 T（结构体或结构体指针），映射中的值被深拷贝。
+// 缺少或值为 nil 的字段保持零值，映射中多余的键被忽略；值的类型与字段不同时按 WithConversionPolicy
+// 的策略（默认 ConvertSafe）转换数值和命名类型，无法转换时返回 ErrTypeConversion
+func FromMap[T any](m map[string]any, opts ...Option) (T, error) {
+	var result T
+	dst := reflect.ValueOf(&result).Elem()
+
+	t := dst.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	c := newMapConverter(opts)
+	if t.Kind() != reflect.Struct || c.isLeaf(t) {
+		return result, fmt.Errorf("deepcopy: FromMap destination must be a struct, got %s", dst.Type())
+	}
+	if m == nil {
+		return result, nil
+	}
+
+	if err := c.state.run(func() { c.fromValue(reflect.ValueOf(m), dst) }); err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// mapConverter 结构体与嵌套映射之间的转换
+type mapConverter struct {
+	state  *copyState
+	onPath map[refKey]bool // 当前路径上的指针、切片和映射，用于发现循环引用
+}
+
+func newMapConverter(opts []Option) *mapConverter {
+	return &mapConverter{state: newCopyState(newCopyConfig(opts)), onPath: make(map[refKey]bool)}
+}
+
+// isLeaf 结构体是否作为整体深拷贝，而不转换为映射
+func (c *mapConverter) isLeaf(t reflect.Type) bool {
+	m := c.state.manager
+	if t == reflectValueType || (typeHasDeepCopyMethod(t) && !c.state.cfg.ignoreMethods) {
+		return true
+	}
+	if m.disableBuiltins {
+		return false
+	}
+	analysis := m.getOrAnalyzeType(t)
+	return immutableValueTypes[t] || analysis.ImplementsTextMarshaler || analysis.ImplementsBinaryMarshaler
+}
+
+// fields 结构体在映射中的字段：下标和对应的键
+func (c *mapConverter) fields(t reflect.Type) ([]int, []string) {
+	var indices []int
+	var names []string
+	for _, i := range c.state.manager.getOrAnalyzeType(t).ExportedFieldIndices {
+		field := t.Field(i)
+		if c.state.cfg.fieldFilter != nil && !c.state.cfg.fieldFilter(field) {
+			continue
+		}
+		name := field.Name
+		if c.state.cfg.mapTag != "" {
+			tagged, _, _ := strings.Cut(field.Tag.Get(c.state.cfg.mapTag), ",")
+			if tagged == "-" {
+				continue
+			}
+			if tagged != "" {
+				name = tagged
+			}
+		}
+		indices = append(indices, i)
+		names = append(names, name)
+	}
+	return indices, names
+}
+
+// enter 进入引用，已在当前路径上时记录 ErrCyclicValue 并返回 false
+func (c *mapConverter) enter(key refKey) bool {
+	if c.onPath[key] {
+		c.state.err = fmt.Errorf("%w at %s", ErrCyclicValue, c.path())
+		return false
+	}
+	c.onPath[key] = true
+	return true
+}
+
+func (c *mapConverter) leave(key refKey) {
+	delete(c.onPath, key)
+}
+
+// structToMap 结构体转换为映射
+func (c *mapConverter) structToMap(v reflect.Value) map[string]any {
+	indices, names := c.fields(v.Type())
+	result := make(map[string]any, len(indices))
+	for n, i := range indices {
+		c.state.pushField(v.Type(), i)
+		result[names[n]] = c.toValue(v.Field(i))
+		c.state.popPath()
+	}
+	return result
+}
+
+// toValue 把值转换为映射中的形式
+func (c *mapConverter) toValue(v reflect.Value) any {
+	if c.state.err != nil {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return c.toValue(v.Elem())
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		key := refKey{ptr: v.Pointer(), typ: v.Type()}
+		if !c.enter(key) {
+			return nil
+		}
+		defer c.leave(key)
+		return c.toValue(v.Elem())
+
+	case reflect.Struct:
+		if !c.isLeaf(v.Type()) {
+			return c.structToMap(v)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				return nil
+			}
+			key := refKey{ptr: v.Pointer(), typ: v.Type(), len: v.Len()}
+			if !c.enter(key) {
+				return nil
+			}
+			defer c.leave(key)
+		}
+		result := make([]any, v.Len())
+		for i := range result {
+			c.state.pushIndex(i)
+			result[i] = c.toValue(v.Index(i))
+			c.state.popPath()
+		}
+		return result
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			return nil
+		}
+		key := refKey{ptr: v.Pointer(), typ: v.Type()}
+		if !c.enter(key) {
+			return nil
+		}
+		defer c.leave(key)
+		result := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.state.pushKey(iter.Key())
+			result[iter.Key().String()] = c.toValue(iter.Value())
+			c.state.popPath()
+		}
+		return result
+	}
+
+	cpy := reflect.New(v.Type()).Elem()
+	c.state.copyRecursive(v, cpy)
+	return cpy.Interface()
+}
+
+// fromValue 把映射中的值 src 还原到 dst
+func (c *mapConverter) fromValue(src, dst reflect.Value) {
+	for src.Kind() == reflect.Interface && !src.IsNil() {
+		src = src.Elem()
+	}
+	if c.state.err != nil || !src.IsValid() || src.Kind() == reflect.Interface {
+		return
+	}
+
+	st, dt := src.Type(), dst.Type()
+	switch {
+	case st.AssignableTo(dt):
+		cpy := reflect.New(st).Elem()
+		c.state.copyRecursive(src, cpy)
+		dst.Set(cpy)
+
+	case dt.Kind() == reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dt.Elem()))
+		}
+		c.fromValue(src, dst.Elem())
+
+	case dt.Kind() == reflect.Struct && st.Kind() == reflect.Map && st.Key().Kind() == reflect.String:
+		key := refKey{ptr: src.Pointer(), typ: st}
+		if !c.enter(key) {
+			return
+		}
+		defer c.leave(key)
+		indices, names := c.fields(dt)
+		for n, i := range indices {
+			value := src.MapIndex(reflect.ValueOf(names[n]).Convert(st.Key()))
+			if !value.IsValid() {
+				continue
+			}
+			c.state.pushField(dt, i)
+			c.fromValue(value, dst.Field(i))
+			c.state.popPath()
+		}
+
+	case (dt.Kind() == reflect.Slice || dt.Kind() == reflect.Array) &&
+		(st.Kind() == reflect.Slice || st.Kind() == reflect.Array):
+		if st.Kind() == reflect.Slice {
+			if src.IsNil() {
+				return
+			}
+			key := refKey{ptr: src.Pointer(), typ: st, len: src.Len()}
+			if !c.enter(key) {
+				return
+			}
+			defer c.leave(key)
+		}
+		if dt.Kind() == reflect.Slice {
+			dst.Set(c.state.cfg.allocator.NewSlice(dt, src.Len(), src.Len()))
+		} else if src.Len() != dst.Len() {
+			c.fail(src, dst, fmt.Sprintf("length %d", src.Len()))
+			return
+		}
+		for i := 0; i < src.Len(); i++ {
+			c.state.pushIndex(i)
+			c.fromValue(src.Index(i), dst.Index(i))
+			c.state.popPath()
+		}
+
+	case dt.Kind() == reflect.Map && st.Kind() == reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := refKey{ptr: src.Pointer(), typ: st}
+		if !c.enter(key) {
+			return
+		}
+		defer c.leave(key)
+		dst.Set(c.state.cfg.allocator.NewMap(dt, src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			c.state.pushKey(iter.Key())
+			k := reflect.New(dt.Key()).Elem()
+			v := reflect.New(dt.Elem()).Elem()
+			c.fromValue(iter.Key(), k)
+			c.fromValue(iter.Value(), v)
+			dst.SetMapIndex(k, v)
+			c.state.popPath()
+		}
+
+	default:
+		loss, ok := numericConversionLoss(st, dt)
+		if !ok || !c.state.cfg.conversionPolicy.allows(loss) {
+			c.fail(src, dst, "")
+			return
+		}
+		dst.Set(src.Convert(dt))
+	}
+}
+
+// fail 记录无法还原的值
+func (c *mapConverter) fail(src, dst reflect.Value, detail string) {
+	what := src.Type().String()
+	if detail != "" {
+		what += " of " + detail
+	}
+	c.state.err = fmt.Errorf("%w: cannot assign %s to %s at %s", ErrTypeConversion, what, dst.Type(), c.path())
+}
+
+// path 当前位置，根值显示为 (root)
+func (c *mapConverter) path() string {
+	if path := c.state.pathString(); path != "" {
+		return path
+	}
+	return "(root)"
+}
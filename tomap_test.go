@@ -0,0 +1,200 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// MapAddress / MapUser 与 map[string]any 互相转换的结构体
+type MapAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"-"`
+}
+
+type MapUser struct {
+	ID       int               `json:"id"`
+	Name     string            `json:"name,omitempty"`
+	Tags     []string          `json:"tags"`
+	Home     MapAddress        `json:"home"`
+	Work     *MapAddress       `json:"work"`
+	Labels   map[string]int    `json:"labels"`
+	Created  time.Time         `json:"created"`
+	Password string            `deepcopy:"redact"`
+	Extra    any               `json:"extra"`
+	ByID     map[int]string    `json:"by_id"`
+	Nested   [][]int           `json:"nested"`
+	Friends  []*MapAddress     `json:"friends"`
+	Notes    map[string]string `json:"-"`
+	secret   string
+}
+
+func newMapUser() MapUser {
+	return MapUser{
+		ID:       7,
+		Name:     "alice",
+		Tags:     []string{"a", "b"},
+		Home:     MapAddress{City: "Paris", Zip: "75001"},
+		Work:     &MapAddress{City: "Berlin"},
+		Labels:   map[string]int{"x": 1},
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Password: "hunter2",
+		Extra:    []any{1, "two"},
+		ByID:     map[int]string{1: "one"},
+		Nested:   [][]int{{1, 2}},
+		Friends:  []*MapAddress{{City: "Rome"}, nil},
+		Notes:    map[string]string{"n": "v"},
+		secret:   "s",
+	}
+}
+
+func TestToMap(t *testing.T) {
+	src := newMapUser()
+	got := ToMap(&src, WithMapTag("json"))
+
+	want := map[string]any{
+		"id":      7,
+		"name":    "alice",
+		"tags":    []any{"a", "b"},
+		"home":    map[string]any{"city": "Paris"},
+		"work":    map[string]any{"city": "Berlin"},
+		"labels":  map[string]any{"x": 1},
+		"created": src.Created,
+		"extra":   []any{1, "two"},
+		"by_id":   map[int]string{1: "one"},
+		"nested":  []any{[]any{1, 2}},
+		"friends": []any{map[string]any{"city": "Rome"}, nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToMap =\n%#v\nwant\n%#v", got, want)
+	}
+
+	// 结果不与源值共享内存
+	got["by_id"].(map[int]string)[1] = "changed"
+	if src.ByID[1] != "one" {
+		t.Error("ToMap result shares memory with src")
+	}
+
+	// 未指定标签时使用字段名
+	if byName := ToMap(src); byName["Home"].(map[string]any)["Zip"] != "75001" {
+		t.Errorf("Home = %#v", byName["Home"])
+	}
+	if ToMap((*MapUser)(nil)) != nil {
+		t.Error("nil pointer should give nil map")
+	}
+}
+
+func TestToMapPanics(t *testing.T) {
+	type node struct{ Next *node }
+	cyclic := &node{}
+	cyclic.Next = cyclic
+
+	tests := []struct {
+		name string
+		src  any
+	}{
+		{"not a struct", 42},
+		{"leaf struct", time.Now()},
+		{"cycle", cyclic},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", tt.name)
+				}
+			}()
+			ToMap(tt.src)
+		}()
+	}
+}
+
+func TestFromMapRoundTrip(t *testing.T) {
+	src := newMapUser()
+	m := ToMap(src, WithMapTag("json"))
+
+	got, err := FromMap[MapUser](m, WithMapTag("json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := src
+	want.Home.Zip = "" // json:"-"
+	want.Password = "" // 脱敏
+	want.Notes = nil   // json:"-"
+	want.secret = ""   // 未导出
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromMap =\n%+v\nwant\n%+v", got, want)
+	}
+
+	got.Tags[0] = "changed"
+	if m["tags"].([]any)[0] != "a" {
+		t.Error("FromMap result shares memory with the map")
+	}
+
+	ptr, err := FromMap[*MapUser](m, WithMapTag("json"))
+	if err != nil || ptr == nil || ptr.Name != "alice" {
+		t.Errorf("FromMap pointer: %+v, %v", ptr, err)
+	}
+}
+
+func TestFromMapConversions(t *testing.T) {
+	type target struct {
+		Count int64
+		Ratio float64
+		Name  convMyString
+		IDs   [2]int
+	}
+
+	got, err := FromMap[target](map[string]any{
+		"Count":   int32(3),
+		"Ratio":   float32(0.5),
+		"Name":    "x",
+		"IDs":     []any{1, 2},
+		"Unknown": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (target{Count: 3, Ratio: 0.5, Name: "x", IDs: [2]int{1, 2}}); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		m    map[string]any
+		want string
+	}{
+		{"narrowing", map[string]any{"Count": 1.5}, "cannot assign float64 to int64 at Count"},
+		{"array length", map[string]any{"IDs": []any{1}}, "cannot assign []interface {} of length 1 to [2]int at IDs"},
+		{"element", map[string]any{"IDs": []any{1, "two"}}, "cannot assign string to int at IDs[1]"},
+	}
+	for _, tt := range tests {
+		_, err := FromMap[target](tt.m)
+		if !errors.Is(err, ErrTypeConversion) || err.Error() != ErrTypeConversion.Error()+": "+tt.want {
+			t.Errorf("%s: got %v", tt.name, err)
+		}
+	}
+
+	// 放宽转换策略后允许截断
+	if got, err := FromMap[target](map[string]any{"Count": 1.5}, WithConversionPolicy(ConvertNarrowing)); err != nil || got.Count != 1 {
+		t.Errorf("narrowing policy: %+v, %v", got, err)
+	}
+}
+
+func TestFromMapErrors(t *testing.T) {
+	if _, err := FromMap[int](map[string]any{}); err == nil {
+		t.Error("non-struct destination should fail")
+	}
+
+	type node struct{ Next *node }
+	cyclic := map[string]any{}
+	cyclic["Next"] = cyclic
+	if _, err := FromMap[node](cyclic); !errors.Is(err, ErrCyclicValue) {
+		t.Errorf("expected ErrCyclicValue, got %v", err)
+	}
+
+	if got, err := FromMap[MapUser](nil); err != nil || !reflect.DeepEqual(got, MapUser{}) {
+		t.Errorf("nil map: %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,215 @@
+package deepcopy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrTypeConversion 类型转换的结果无法放入目标位置，或两种类型之间无法拷贝时返回
+var ErrTypeConversion = errors.New("deepcopy: cannot convert type")
+
+// typeConverter 将 from 类型的值转换为 to 类型
+type typeConverter struct {
+	to   reflect.Type
+	conv func(any) any
+}
+
+// WithTypeConverter 拷贝过程中遇到 from 类型的值时调用 conv，用其结果代替深拷贝。
+// 结果需要能赋值（或转换）给目标位置：目标为接口时放入 to 类型的值，
+// 目标本身为 to 类型时（见 CopyWithTypeMap）直接赋值。conv 的结果不会再被深拷贝
+func WithTypeConverter(from, to reflect.Type, conv func(any) any) Option {
+	return func(c *copyConfig) {
+		if c.typeConverters == nil {
+			c.typeConverters = make(map[reflect.Type]typeConverter)
+		}
+		c.typeConverters[from] = typeConverter{to: to, conv: conv}
+	}
+}
+
+// CopyWithTypeMap 将 src 深拷贝到类型为 D 的新值中，D 可以与 src 的类型不同：
+// 结构体按导出字段名匹配（目标中没有的字段被忽略），指针、切片、映射和数组逐元素拷贝，
+// 遇到通过 WithTypeConverter 注册的类型时使用转换函数，常用于新旧版本结构体之间的迁移
+func CopyWithTypeMap[D any](src any, opts ...Option) (D, error) {
+	var dst D
+	if src == nil {
+		return dst, nil
+	}
+
+	cfg := newCopyConfig(opts)
+	if cfg.locker != nil {
+		cfg.locker.Lock()
+		defer cfg.locker.Unlock()
+	}
+	// 目标类型与源类型不同时需要走跨类型拷贝
+	if cfg.typeConverters == nil {
+		cfg.typeConverters = make(map[reflect.Type]typeConverter)
+	}
+	state := newCopyState(cfg)
+	err := state.run(func() {
+		state.copyRecursive(reflect.ValueOf(src), reflect.ValueOf(&dst).Elem())
+	})
+	if err != nil {
+		var zero D
+		return zero, err
+	}
+	return dst, nil
+}
+
+// convertType 应用类型转换或跨类型拷贝，返回 true 表示已处理
+func (s *copyState) convertType(original, cpy reflect.Value) bool {
+	if tc, ok := s.cfg.typeConverters[original.Type()]; ok {
+		s.applyConverter(original, cpy, tc)
+		return true
+	}
+	if original.Type() == cpy.Type() {
+		return false
+	}
+	s.copyAcrossTypes(original, cpy)
+	return true
+}
+
+// applyConverter 调用转换函数并将结果放入 cpy
+func (s *copyState) applyConverter(original, cpy reflect.Value, tc typeConverter) {
+	result := reflect.ValueOf(tc.conv(original.Interface()))
+	switch {
+	case !result.IsValid():
+		cpy.Set(reflect.Zero(cpy.Type()))
+	case result.Type().AssignableTo(cpy.Type()):
+		cpy.Set(result)
+	case result.Type().ConvertibleTo(cpy.Type()):
+		cpy.Set(result.Convert(cpy.Type()))
+	default:
+		s.err = fmt.Errorf("%w: converter for %s returned %s, want %s",
+			ErrTypeConversion, original.Type(), result.Type(), cpy.Type())
+	}
+}
+
+// interfaceElemType 放入接口 dst 中的副本类型，注册了转换且结果可放入接口时为转换后的类型
+func (s *copyState) interfaceElemType(t, dst reflect.Type) reflect.Type {
+	if tc, ok := s.cfg.typeConverters[t]; ok && tc.to.AssignableTo(dst) {
+		return tc.to
+	}
+	return t
+}
+
+// copyAcrossTypes 将 original 深拷贝到类型不同的 cpy 中
+func (s *copyState) copyAcrossTypes(original, cpy reflect.Value) {
+	st, dt := original.Type(), cpy.Type()
+
+	switch {
+	case st.Kind() == reflect.Interface:
+		if original.IsNil() {
+			cpy.Set(reflect.Zero(dt))
+			return
+		}
+		s.copyRecursive(original.Elem(), cpy)
+		return
+
+	case dt.Kind() == reflect.Interface:
+		copyValue := reflect.New(s.interfaceElemType(st, dt)).Elem()
+		s.copyRecursive(original, copyValue)
+		if !copyValue.Type().AssignableTo(dt) {
+			s.err = fmt.Errorf("%w: %s does not implement %s", ErrTypeConversion, copyValue.Type(), dt)
+			return
+		}
+		cpy.Set(copyValue)
+		return
+
+	case st.Kind() == dt.Kind() && st.ConvertibleTo(dt):
+		// 结构相同的类型（如命名类型与其底层类型）先按源类型拷贝再转换
+		copyValue := reflect.New(st).Elem()
+		s.copyRecursive(original, copyValue)
+		cpy.Set(copyValue.Convert(dt))
+		return
+	}
+
+	if st.Kind() != dt.Kind() {
+		s.err = fmt.Errorf("%w: cannot copy %s into %s", ErrTypeConversion, st, dt)
+		return
+	}
+
+	switch st.Kind() {
+	case reflect.Ptr:
+		if original.IsNil() {
+			cpy.Set(reflect.Zero(dt))
+			return
+		}
+		key := refKey{ptr: original.Pointer(), typ: dt}
+		if v, ok := s.lookupRef(key); ok {
+			cpy.Set(v)
+			return
+		}
+		newPtr := s.cfg.allocator.New(dt.Elem())
+		cpy.Set(newPtr)
+		s.markRef(key, newPtr)
+		s.copyRecursive(original.Elem(), newPtr.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < dt.NumField(); i++ {
+			field := dt.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			// 只匹配源结构体的直接导出字段
+			srcField, ok := st.FieldByName(field.Name)
+			if !ok || srcField.PkgPath != "" || len(srcField.Index) != 1 {
+				continue
+			}
+			if s.cfg.fieldFilter != nil && !s.cfg.fieldFilter(srcField) {
+				continue
+			}
+			s.pushField(dt, i)
+			s.copyRecursive(original.Field(srcField.Index[0]), cpy.Field(i))
+			s.popPath()
+		}
+
+	case reflect.Slice:
+		if original.IsNil() {
+			cpy.Set(reflect.Zero(dt))
+			return
+		}
+		newSlice := s.cfg.allocator.NewSlice(dt, original.Len(), original.Len())
+		cpy.Set(newSlice)
+		for i := 0; i < original.Len(); i++ {
+			s.pushIndex(i)
+			s.copyRecursive(original.Index(i), newSlice.Index(i))
+			s.popPath()
+		}
+
+	case reflect.Array:
+		if original.Len() != dt.Len() {
+			s.err = fmt.Errorf("%w: cannot copy %s into %s", ErrTypeConversion, st, dt)
+			return
+		}
+		for i := 0; i < original.Len(); i++ {
+			s.pushIndex(i)
+			s.copyRecursive(original.Index(i), cpy.Index(i))
+			s.popPath()
+		}
+
+	case reflect.Map:
+		if original.IsNil() {
+			cpy.Set(reflect.Zero(dt))
+			return
+		}
+		newMap := s.cfg.allocator.NewMap(dt, original.Len())
+		cpy.Set(newMap)
+		iter := original.MapRange()
+		for iter.Next() {
+			s.pushKey(iter.Key())
+			copyKey := reflect.New(dt.Key()).Elem()
+			s.copyRecursive(iter.Key(), copyKey)
+			copyValue := reflect.New(dt.Elem()).Elem()
+			s.copyRecursive(iter.Value(), copyValue)
+			s.popPath()
+			if s.err != nil {
+				return
+			}
+			newMap.SetMapIndex(copyKey, copyValue)
+		}
+
+	default:
+		s.err = fmt.Errorf("%w: cannot copy %s into %s", ErrTypeConversion, st, dt)
+	}
+}
@@ -0,0 +1,137 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// 旧版本的地址结构
+type OldAddr struct {
+	Street string
+	City   string
+}
+
+// 新版本的地址结构
+type NewAddr struct {
+	Lines []string
+	City  string
+}
+
+type CustomerV1 struct {
+	Name    string
+	Addr    OldAddr
+	History []*OldAddr
+	Tags    map[string]string
+	Meta    []any
+	Dropped int
+}
+
+type CustomerV2 struct {
+	Name    string
+	Addr    NewAddr
+	History []*NewAddr
+	Tags    map[string]string
+	Meta    []any
+}
+
+var (
+	oldAddrType = reflect.TypeOf(OldAddr{})
+	newAddrType = reflect.TypeOf(NewAddr{})
+)
+
+func oldToNewAddr(v any) any {
+	old := v.(OldAddr)
+	return NewAddr{Lines: []string{old.Street}, City: old.City}
+}
+
+func TestCopyWithTypeMap(t *testing.T) {
+	prev := &OldAddr{Street: "1 Old Rd", City: "Paris"}
+	src := CustomerV1{
+		Name:    "alice",
+		Addr:    OldAddr{Street: "2 Main St", City: "Berlin"},
+		History: []*OldAddr{prev, prev, nil},
+		Tags:    map[string]string{"tier": "gold"},
+		Meta:    []any{OldAddr{City: "Rome"}, 7},
+		Dropped: 1,
+	}
+
+	dst, err := CopyWithTypeMap[CustomerV2](src, WithTypeConverter(oldAddrType, newAddrType, oldToNewAddr))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst.Name != "alice" || dst.Tags["tier"] != "gold" {
+		t.Errorf("plain fields not copied: %+v", dst)
+	}
+	if !reflect.DeepEqual(dst.Addr, NewAddr{Lines: []string{"2 Main St"}, City: "Berlin"}) {
+		t.Errorf("Addr not converted: %+v", dst.Addr)
+	}
+	if len(dst.History) != 3 || dst.History[0].City != "Paris" || dst.History[2] != nil {
+		t.Fatalf("History not converted: %+v", dst.History)
+	}
+	if dst.History[0] != dst.History[1] {
+		t.Error("shared pointer should stay shared after conversion")
+	}
+	if addr, ok := dst.Meta[0].(NewAddr); !ok || addr.City != "Rome" {
+		t.Errorf("value inside interface not converted: %#v", dst.Meta[0])
+	}
+
+	src.Tags["tier"] = "silver"
+	if dst.Tags["tier"] != "gold" {
+		t.Error("map should be deep copied")
+	}
+}
+
+func TestTypeConverterSameType(t *testing.T) {
+	src := []any{OldAddr{Street: "3 Side St"}, "keep"}
+
+	copied, err := CopyE(src, WithTypeConverter(oldAddrType, newAddrType, oldToNewAddr))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if addr, ok := copied[0].(NewAddr); !ok || addr.Lines[0] != "3 Side St" {
+		t.Errorf("expected NewAddr in interface slot, got %#v", copied[0])
+	}
+	if copied[1] != "keep" {
+		t.Errorf("unexpected %v", copied[1])
+	}
+
+	// 目标字段仍为 OldAddr 时转换结果无法放入
+	_, err = CopyE(CustomerV1{}, WithTypeConverter(oldAddrType, newAddrType, oldToNewAddr))
+	if !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("expected ErrTypeConversion, got %v", err)
+	}
+}
+
+func TestCopyWithTypeMapIncompatible(t *testing.T) {
+	type target struct{ Name int }
+	_, err := CopyWithTypeMap[target](CustomerV1{Name: "bob"})
+	if !errors.Is(err, ErrTypeConversion) {
+		t.Errorf("expected ErrTypeConversion, got %v", err)
+	}
+}
+
+// 映射元素拷贝失败时路径同样出栈，不影响之后的字段
+func TestCopyWithTypeMapMapErrorPath(t *testing.T) {
+	type source struct {
+		Tags map[string]string
+		Name string
+	}
+	type target struct {
+		Tags map[string]int
+		Name string
+	}
+	cfg := newCopyConfig([]Option{WithTypeConverter(oldAddrType, newAddrType, oldToNewAddr)})
+	state := newCopyState(cfg)
+	var dst target
+	err := state.run(func() {
+		state.copyRecursive(reflect.ValueOf(source{Tags: map[string]string{"a": "b"}}), reflect.ValueOf(&dst).Elem())
+	})
+	if !errors.Is(err, ErrTypeConversion) {
+		t.Fatalf("expected ErrTypeConversion, got %v", err)
+	}
+	if len(state.path) != 0 {
+		t.Errorf("path not popped after error: %s", state.pathString())
+	}
+}
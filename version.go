@@ -0,0 +1,87 @@
+package deepcopy
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// modulePath 本包的模块路径，用于从构建信息中查找版本
+const modulePath = "github.com/wsqun/deepcopy"
+
+// version 包的版本号，发布构建时通过 -ldflags "-X github.com/wsqun/deepcopy.version=v1.2.3" 设置，
+// 未设置时在 init 中取构建信息里依赖的模块版本，仍然未知时为 (devel)
+var version string
+
+// buildTags 构建时的 -tags，从构建信息中读取
+var buildTags string
+
+// capabilities 编译进包中的可选功能
+var capabilities = []string{
+	"slog-integration", // WithLogger 使用 log/slog 输出警告
+	"unsafe-copy",      // 基础类型的切片通过 unsafe 视为 []E 整块复制
+}
+
+func init() {
+	info, ok := debug.ReadBuildInfo()
+	if ok {
+		if version == "" {
+			version = moduleVersion(info)
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "-tags" {
+				buildTags = setting.Value
+			}
+		}
+	}
+	if version == "" {
+		version = "(devel)"
+	}
+	sort.Strings(capabilities)
+}
+
+// moduleVersion 构建信息中本包模块的版本，被 replace 时取替换后的版本
+func moduleVersion(info *debug.BuildInfo) string {
+	mod := &info.Main
+	if mod.Path != modulePath {
+		mod = nil
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				mod = dep
+				break
+			}
+		}
+	}
+	if mod == nil {
+		return ""
+	}
+	if mod.Replace != nil && mod.Replace.Version != "" {
+		return mod.Replace.Version
+	}
+	if mod.Version == "(devel)" {
+		return ""
+	}
+	return mod.Version
+}
+
+// Version 包的版本号（如 v1.2.3），见 version 变量；本地开发构建中为 (devel)
+func Version() string {
+	return version
+}
+
+// BuildInfo 运行环境的描述，用于日志：包版本、Go 版本、GOOS/GOARCH、是否以 -race 构建以及构建时的 -tags，
+// 如 "deepcopy v1.2.3 (go1.21.1 linux/amd64, race=false)"
+func BuildInfo() string {
+	parts := []string{runtime.Version(), runtime.GOOS + "/" + runtime.GOARCH, fmt.Sprintf("race=%t", raceEnabled)}
+	if buildTags != "" {
+		parts = append(parts, "tags="+buildTags)
+	}
+	return fmt.Sprintf("deepcopy %s (%s)", version, strings.Join(parts, ", "))
+}
+
+// Capabilities 编译进包中的可选功能，按名称排序，如 "slog-integration"、"unsafe-copy"
+func Capabilities() []string {
+	return append([]string(nil), capabilities...)
+}
@@ -0,0 +1,32 @@
+package deepcopy
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestBuildInfo(t *testing.T) {
+	if Version() == "" {
+		t.Error("Version should not be empty")
+	}
+	info := BuildInfo()
+	for _, want := range []string{Version(), runtime.Version(), runtime.GOOS + "/" + runtime.GOARCH} {
+		if !strings.Contains(info, want) {
+			t.Errorf("BuildInfo %q should contain %q", info, want)
+		}
+	}
+	if race := strings.Contains(info, "race=true"); race != raceEnabled {
+		t.Errorf("BuildInfo %q does not match raceEnabled=%t", info, raceEnabled)
+	}
+
+	// 返回副本，调用方修改不影响包内的列表
+	caps := Capabilities()
+	if len(caps) == 0 || caps[0] != "slog-integration" {
+		t.Fatalf("Capabilities = %v", caps)
+	}
+	caps[0] = "changed"
+	if Capabilities()[0] != "slog-integration" {
+		t.Error("Capabilities should return a copy")
+	}
+}
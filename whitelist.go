@@ -0,0 +1,41 @@
+package deepcopy
+
+import "reflect"
+
+// includeOption 标签选项，如 `deepcopy:"include"`：WithTagWhitelist 模式下拷贝该字段
+const includeOption = "include"
+
+// WithTagWhitelist 白名单模式：只拷贝带 `deepcopy:"include"` 标签（标签名见 TagKey）或带有 key 标签的字段，
+// 其余字段在副本中为零值（CopyInto 时保留目标原有的值），可从大型结构体中直接得到精简快照，无需另外定义 DTO。
+// 嵌套的结构体同样只拷贝其中被选中的字段；key 为空时只认 include 选项
+func WithTagWhitelist(key string) Option {
+	return func(c *copyConfig) {
+		c.whitelist = true
+		c.whitelistKey = key
+	}
+}
+
+// whitelistFields 返回白名单模式下结构体要拷贝的字段下标，结果按 key 缓存在分析结果中
+func (r *TypeAnalysisResult) whitelistFields(m *DeepCopyManager, t reflect.Type, key string) []int {
+	if cached, ok := r.whitelists.Load(key); ok {
+		return cached.([]int)
+	}
+	var fields []int
+	for _, i := range r.ExportedFieldIndices {
+		if m.isIncluded(t.Field(i), key) {
+			fields = append(fields, i)
+		}
+	}
+	r.whitelists.Store(key, fields)
+	return fields
+}
+
+// isIncluded 字段是否在白名单中
+func (m *DeepCopyManager) isIncluded(field reflect.StructField, key string) bool {
+	if key != "" {
+		if _, ok := field.Tag.Lookup(key); ok {
+			return true
+		}
+	}
+	return m.hasTagOption(field, includeOption)
+}
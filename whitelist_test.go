@@ -0,0 +1,95 @@
+package deepcopy
+
+import (
+	"reflect"
+	"testing"
+)
+
+// FatAddress 只有部分字段进入快照
+type FatAddress struct {
+	City   string `deepcopy:"include"`
+	Street string
+	Geo    []float64 `snapshot:""`
+}
+
+// FatOrder 大型领域对象
+type FatOrder struct {
+	ID       int         `deepcopy:"include"`
+	Status   string      `snapshot:"status"`
+	Notes    []string    // 不在快照中
+	Shipping *FatAddress `deepcopy:"include"`
+	Billing  FatAddress  // 父字段未选中，整体为零值
+	Items    []FatItem   `deepcopy:"include"`
+	Meta     map[string]int
+}
+
+type FatItem struct {
+	SKU   string `deepcopy:"include,redact"`
+	Price int    `deepcopy:"include"`
+	Desc  string
+}
+
+func TestCopyTagWhitelist(t *testing.T) {
+	src := FatOrder{
+		ID:       7,
+		Status:   "paid",
+		Notes:    []string{"fragile"},
+		Shipping: &FatAddress{City: "Paris", Street: "Rue 1", Geo: []float64{48.8, 2.3}},
+		Billing:  FatAddress{City: "Lyon"},
+		Items:    []FatItem{{SKU: "a", Price: 10, Desc: "apple"}},
+		Meta:     map[string]int{"x": 1},
+	}
+
+	copied, err := CopyE(src, WithTagWhitelist("snapshot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FatOrder{
+		ID:       7,
+		Status:   "paid",
+		Shipping: &FatAddress{City: "Paris", Geo: []float64{48.8, 2.3}},
+		Items:    []FatItem{{Price: 10}},
+	}
+	if !reflect.DeepEqual(copied, want) {
+		t.Errorf("copied = %+v\nwant     %+v", copied, want)
+	}
+	if copied.Shipping == src.Shipping || &copied.Shipping.Geo[0] == &src.Shipping.Geo[0] {
+		t.Error("included reference fields should be deep copied")
+	}
+
+	// 不指定标签名时只认 include 选项
+	copied, err = CopyE(src, WithTagWhitelist(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.Status != "" || copied.Shipping.Geo != nil || copied.Shipping.City != "Paris" {
+		t.Errorf("copied = %+v, Shipping = %+v", copied, copied.Shipping)
+	}
+}
+
+// 白名单字段按标签名缓存在分析结果中
+func TestTagWhitelistCached(t *testing.T) {
+	rt := reflect.TypeOf(FatAddress{})
+	analysis := defaultManager.getOrAnalyzeType(rt)
+	first := analysis.whitelistFields(defaultManager, rt, "snapshot")
+	if !reflect.DeepEqual(first, []int{0, 2}) {
+		t.Errorf("whitelist fields = %v, want [0 2]", first)
+	}
+	if got := analysis.whitelistFields(defaultManager, rt, ""); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("include-only fields = %v, want [0]", got)
+	}
+	if cached, _ := analysis.whitelists.Load("snapshot"); &cached.([]int)[0] != &first[0] {
+		t.Error("whitelist fields should be cached per tag key")
+	}
+}
+
+// CopyInto 时未选中的字段保留目标原有的值
+func TestCopyIntoTagWhitelist(t *testing.T) {
+	dst := FatAddress{City: "old", Street: "keep"}
+	if err := CopyInto(&dst, FatAddress{City: "new", Street: "ignored"}, WithTagWhitelist("")); err != nil {
+		t.Fatal(err)
+	}
+	if dst.City != "new" || dst.Street != "keep" {
+		t.Errorf("dst = %+v", dst)
+	}
+}